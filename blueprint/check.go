@@ -0,0 +1,328 @@
+package blueprint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	goparser "go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Header that the generators write at the top of every Go file
+var generatedHeader = regexp.MustCompile(`^// Blueprint: auto-generated by (.+?)(?: plugin)?\n`)
+
+// A package that was loaded by the codeChecker
+type checkedPackage struct {
+	pkg   *types.Package
+	files []*ast.File
+	err   error
+}
+
+// codeChecker formats and type-checks the Go modules generated for the containers. Errors in the generated code are
+// reported against the modifier or plugin that generated the code.
+type codeChecker struct {
+	fset       *token.FileSet
+	diags      *parser.Diagnostics
+	std        types.Importer
+	services   map[string]string                      // Instance names to the services they are declared as
+	infos      map[string]*generators.ServiceImplInfo // Generated types by name
+	runtimeDir string                                 // Source of the Blueprint runtime packages, if the compiler runs from it
+	packages   map[string]*checkedPackage             // By directory
+	reported   map[string]bool
+}
+
+func newCodeChecker(diags *parser.Diagnostics, root *generators.MillenialNode) *codeChecker {
+	fset := token.NewFileSet()
+	c := &codeChecker{fset: fset, diags: diags, std: importer.ForCompiler(fset, "gc", nil), services: make(map[string]string), infos: make(map[string]*generators.ServiceImplInfo), packages: make(map[string]*checkedPackage), reported: make(map[string]bool)}
+	if wd, err := os.Getwd(); err == nil {
+		if data, err := ioutil.ReadFile(filepath.Join(wd, "go.mod")); err == nil && modfile.ModulePath(data) == generators.MODULE_ROOT {
+			c.runtimeDir = wd
+		}
+	}
+	for _, nodeType := range []string{"FuncServiceNode", "QueueServiceNode"} {
+		for _, node := range root.GetNodes(nodeType) {
+			var service *generators.ServiceNode
+			switch n := node.(type) {
+			case *generators.FuncServiceNode:
+				service = &n.ServiceNode
+			case *generators.QueueServiceNode:
+				service = &n.ServiceNode
+			}
+			c.services[service.Name] = service.AbstractType
+			c.addInfos(service.ASTServerNodes)
+			for _, name := range parser.SortedKeys(service.ParamClientNodes) {
+				c.addInfos(service.ParamClientNodes[name])
+			}
+			for _, name := range parser.SortedKeys(service.ModifierClientNodes) {
+				c.addInfos(service.ModifierClientNodes[name])
+			}
+		}
+	}
+	return c
+}
+
+func (c *codeChecker) addInfos(infos []*generators.ServiceImplInfo) {
+	for _, info := range infos {
+		if _, ok := c.infos[info.Name]; !ok {
+			c.infos[info.Name] = info
+		}
+	}
+}
+
+// Checks the packages of the Go module in dir
+func (c *codeChecker) checkModule(dir string) error {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return err
+	}
+	mod, err := modfile.ParseLax(filepath.Join(dir, "go.mod"), data, nil)
+	if err != nil || mod.Module == nil {
+		return fmt.Errorf("could not parse the go.mod file of %s: %v", dir, err)
+	}
+	imp := &moduleImporter{checker: c, modPath: mod.Module.Mod.Path, roots: map[string]string{mod.Module.Mod.Path: dir}}
+	for _, replace := range mod.Replace {
+		if modfile.IsDirectoryPath(replace.New.Path) {
+			imp.roots[replace.Old.Path] = filepath.Join(dir, filepath.FromSlash(replace.New.Path))
+		}
+	}
+	if c.runtimeDir != "" {
+		imp.roots[generators.MODULE_ROOT] = c.runtimeDir
+	}
+
+	var dirs []string
+	err = filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && name != dir {
+			if _, err := os.Stat(filepath.Join(name, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		if !info.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			if len(dirs) == 0 || dirs[len(dirs)-1] != filepath.Dir(name) {
+				dirs = append(dirs, filepath.Dir(name))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, pkgDir := range dirs {
+		rel, err := filepath.Rel(dir, pkgDir)
+		if err != nil {
+			return err
+		}
+		c.load(path.Join(mod.Module.Mod.Path, filepath.ToSlash(rel)), pkgDir, imp, true)
+	}
+	return nil
+}
+
+// Loads the package in dir. Errors are only reported for the packages of the module that is checked, the packages
+// it imports are used as far as they type-check.
+func (c *codeChecker) load(importPath string, dir string, imp types.Importer, report bool) *checkedPackage {
+	if loaded, ok := c.packages[dir]; ok {
+		return loaded
+	}
+	loaded := &checkedPackage{}
+	c.packages[dir] = loaded
+	ctxt := build.Default
+	ctxt.CgoEnabled = false
+	bpkg, err := ctxt.ImportDir(dir, 0)
+	if err != nil {
+		loaded.err = err
+		return loaded
+	}
+	syntaxErrors := false
+	for _, name := range bpkg.GoFiles {
+		filename := filepath.Join(dir, name)
+		src, err := ioutil.ReadFile(filename)
+		if err != nil {
+			loaded.err = err
+			return loaded
+		}
+		file, err := goparser.ParseFile(c.fset, filename, src, goparser.ParseComments)
+		if err != nil {
+			syntaxErrors = true
+			if report {
+				var list scanner.ErrorList
+				if errors.As(err, &list) {
+					for _, e := range list {
+						c.report(file, src, e.Pos, e.Msg)
+					}
+				}
+			}
+		} else if report {
+			if formatted, err := format.Source(src); err == nil && !bytes.Equal(formatted, src) {
+				// Written by a tool that doesn't go through parser.WriteFile
+				if err := parser.WriteFile(filename, formatted, parser.FileMode); err != nil {
+					loaded.err = err
+					return loaded
+				}
+			}
+		}
+		if file != nil {
+			loaded.files = append(loaded.files, file)
+		}
+	}
+	if syntaxErrors {
+		loaded.err = fmt.Errorf("%s contains syntax errors", dir)
+		return loaded
+	}
+
+	conf := types.Config{Importer: imp, Error: func(err error) {
+		var typeErr types.Error
+		// Packages that can't be resolved offline, e.g. third-party ones, are left unchecked
+		if report && errors.As(err, &typeErr) && !strings.HasPrefix(typeErr.Msg, "could not import ") {
+			pos := c.fset.Position(typeErr.Pos)
+			for _, file := range loaded.files {
+				if c.fset.File(file.Pos()).Name() == pos.Filename {
+					src, _ := ioutil.ReadFile(pos.Filename)
+					c.report(file, src, pos, typeErr.Msg)
+				}
+			}
+		}
+	}}
+	loaded.pkg, _ = conf.Check(importPath, c.fset, loaded.files, nil)
+	return loaded
+}
+
+// Reports an error at pos in file against the modifier or plugin that generated the enclosing declaration
+func (c *codeChecker) report(file *ast.File, src []byte, pos token.Position, msg string) {
+	generator, subject := "Blueprint Core", filepath.Base(pos.Filename)
+	if match := generatedHeader.FindSubmatch(src); match != nil {
+		generator = string(match[1])
+	}
+	if file != nil {
+		typeName, method := enclosingDecl(file, c.fset.File(file.Pos()).Pos(pos.Offset))
+		if info, ok := c.infos[typeName]; ok {
+			if info.Generator != "" {
+				generator = info.Generator
+			} else if info.PluginName != "" {
+				generator = info.PluginName
+			}
+			subject = info.Name
+			if method != "" {
+				subject = c.serviceName(info) + "." + method
+			}
+		} else if typeName != "" {
+			subject = typeName
+			if method != "" {
+				subject += "." + method
+			}
+		}
+	}
+	message := fmt.Sprintf("%s produced invalid code for %s: %s", generator, subject, msg)
+	if key := pos.String() + message; !c.reported[key] {
+		c.reported[key] = true
+		c.diags.Errorf(pos, "%s", message)
+	}
+}
+
+// Returns the name of the service that the methods of info implement
+func (c *codeChecker) serviceName(info *generators.ServiceImplInfo) string {
+	if service := c.services[info.InstanceName]; service != "" {
+		return service
+	}
+	if info.BaseName != "" {
+		return info.BaseName
+	}
+	return info.Name
+}
+
+// Returns the type and the method of the declaration that contains pos. Constructors belong to the type they
+// construct.
+func enclosingDecl(file *ast.File, pos token.Pos) (typeName string, method string) {
+	for _, decl := range file.Decls {
+		if pos < decl.Pos() || pos > decl.End() {
+			continue
+		}
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				return strings.TrimPrefix(d.Name.Name, "New"), ""
+			}
+			recv := d.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				return ident.Name, d.Name.Name
+			}
+			return "", d.Name.Name
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok && pos >= spec.Pos() && pos <= spec.End() {
+					return typeSpec.Name.Name, ""
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// moduleImporter resolves the imports of a generated module: the packages of the module itself and of the
+// directories it replaces modules with are loaded from source, the standard library from export data.
+type moduleImporter struct {
+	checker *codeChecker
+	modPath string            // Path of the module that is checked
+	roots   map[string]string // Module paths to directories
+}
+
+func (imp *moduleImporter) Import(importPath string) (*types.Package, error) {
+	var modPaths []string
+	for modPath := range imp.roots {
+		modPaths = append(modPaths, modPath)
+	}
+	// The longest module path wins
+	sort.Slice(modPaths, func(i, j int) bool { return len(modPaths[i]) > len(modPaths[j]) })
+	for _, modPath := range modPaths {
+		if importPath == modPath || strings.HasPrefix(importPath, modPath+"/") {
+			dir := filepath.Join(imp.roots[modPath], filepath.FromSlash(strings.TrimPrefix(importPath, modPath)))
+			loaded := imp.checker.load(importPath, dir, imp, modPath == imp.modPath)
+			if loaded.pkg == nil {
+				return nil, fmt.Errorf("could not load %s: %v", importPath, loaded.err)
+			}
+			return loaded.pkg, nil
+		}
+	}
+	if bpkg, err := build.Default.Import(importPath, "", build.FindOnly); err == nil && bpkg.Goroot {
+		return imp.checker.std.Import(importPath)
+	}
+	return nil, fmt.Errorf("%s is not available", importPath)
+}
+
+// Checks the Go modules of the containers in outDir
+func checkGeneratedCode(s *State) {
+	checker := newCodeChecker(s.Diagnostics, s.Root)
+	for _, node := range s.Root.GetNodes("DockerContainerNode") {
+		container := node.(*generators.DockerContainerNode)
+		dir := filepath.Join(s.Config.OutDir, strings.ToLower(container.Name))
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+			// Containers of components don't have any generated code
+			continue
+		}
+		if err := checker.checkModule(dir); err != nil {
+			parser.Abort(err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package blueprint
+
+import (
+	"context"
+	"errors"
+	"go/format"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// A server modifier that calls a method the service doesn't have
+type brokenModifier struct {
+	*generators.NoOpSourceCodeModifier
+}
+
+func (m *brokenModifier) Accept(v generators.Visitor) {
+	v.VisitModifier(v, m)
+}
+
+func (m *brokenModifier) GetNodes(nodeType string) []generators.Node {
+	return nil
+}
+
+func (m *brokenModifier) GetName() string {
+	return "TestBroken"
+}
+
+func (m *brokenModifier) GetPluginName() string {
+	return "TestBroken"
+}
+
+func (m *brokenModifier) ModifyServer(prev_node *generators.ServiceImplInfo) (*generators.ServiceImplInfo, error) {
+	name := prev_node.BaseName + "Broken"
+	methods := make(map[string]parser.FuncInfo)
+	bodies := make(map[string]string)
+	for method_name, method := range prev_node.Methods {
+		if !method.Public {
+			continue
+		}
+		var arg_names []string
+		for _, arg := range method.Args {
+			arg_names = append(arg_names, arg.Name)
+		}
+		methods[method_name] = method
+		bodies[method_name] = "return b.service." + method_name + "Missing(" + strings.Join(arg_names, ", ") + ")"
+	}
+	constructor := parser.FuncInfo{Name: "New" + name, Args: []parser.ArgInfo{parser.GetPointerArg("service", prev_node.Name)}, Return: []parser.ArgInfo{parser.GetPointerArg("", name)}}
+	bodies[constructor.Name] = "return &" + name + "{service: service}"
+	imports := []parser.ImportInfo{{FullName: "context"}}
+	fields := []parser.ArgInfo{parser.GetPointerArg("service", prev_node.Name)}
+	return &generators.ServiceImplInfo{Name: name, ReceiverName: "b", Methods: methods, MethodBodies: bodies, BaseName: prev_node.BaseName, Imports: imports, Fields: fields, Constructors: []parser.FuncInfo{constructor}, InstanceName: prev_node.InstanceName, BaseImports: prev_node.BaseImports}, nil
+}
+
+func init() {
+	generators.RegisterModifier(generators.ModifierPlugin{
+		Name:        "TestBroken",
+		Description: "Generates code that doesn't compile",
+		Generate: func(node parser.ModifierNode) generators.Modifier {
+			return &brokenModifier{generators.NewNoOpSourceCodeModifier()}
+		},
+	})
+}
+
+func TestCompileChecksGeneratedCode(t *testing.T) {
+	chdirRoot(t)
+	wiring := filepath.Join(t.TempDir(), "wiring.py")
+	content := `default_server_conn_opts : Modifier = WebServer(framework="default")
+default_deployer : Modifier = Deployer(framework="docker")
+broken : Modifier = TestBroken()
+server_modifiers : List[Modifier] = [broken, default_server_conn_opts, default_deployer]
+leafService : LeafService = LeafServiceImpl().WithServer(server_modifiers)
+`
+	if err := os.WriteFile(wiring, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := leafConfig(t, wiring)
+	_, err := Compile(context.Background(), config, Options{})
+	var diagErr *DiagnosticsError
+	if !errors.As(err, &diagErr) {
+		t.Fatalf("Expected diagnostics, got %v", err)
+	}
+	var messages []string
+	for _, diag := range diagErr.Diagnostics.Sorted() {
+		messages = append(messages, filepath.Base(diag.Pos.Filename)+": "+diag.Message)
+	}
+	expected := []string{
+		"LeafServiceImplBroken.go: brokenModifier produced invalid code for LeafService.Leaf: b.service.LeafMissing undefined (type *LeafServiceImpl has no field or method LeafMissing)",
+		"LeafServiceImplBroken.go: brokenModifier produced invalid code for LeafService.Object: b.service.ObjectMissing undefined (type *LeafServiceImpl has no field or method ObjectMissing)",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected %q, got %q", expected, messages)
+	}
+}
+
+func TestGeneratedCodeIsFormatted(t *testing.T) {
+	chdirRoot(t)
+	config := leafConfig(t, "examples/Leaf/wiring/instances_all_in_one.py")
+	result, err := Compile(context.Background(), config, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range result.Files {
+		if !strings.HasSuffix(name, ".go") || strings.Contains(filepath.ToSlash(name), "/spec/") {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if formatted, err := format.Source(src); err != nil || string(formatted) != string(src) {
+			t.Errorf("Expected %s to be formatted", name)
+		}
+	}
+}
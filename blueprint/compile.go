@@ -0,0 +1,363 @@
+// Package blueprint runs the Blueprint compiler pipeline as a library.
+package blueprint
+
+import (
+	"context"
+	"errors"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/deploy"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Stages of the compiler pipeline in the order in which they are executed
+type Stage string
+
+const (
+	StageParseSpec                Stage = "parse_spec"
+	StageParseWiring              Stage = "parse_wiring"
+	StageInitModifiers            Stage = "init_modifiers"
+	StageConvertIR                Stage = "convert_ir"
+	StagePrintIR                  Stage = "print_ir"
+	StageCoLocatedServices        Stage = "colocated_services"
+	StageDependencyGraph          Stage = "dependency_graph"
+	StageRemoteTypes              Stage = "remote_types"
+	StageCollectClients           Stage = "collect_clients"
+	StageGenerateSourceCode       Stage = "generate_source_code"
+	StageGenerateClientSourceCode Stage = "generate_client_source_code"
+	StageDeploy                   Stage = "deploy"
+	StageDeployModifiers          Stage = "deploy_modifiers"
+	StageCollectAddresses         Stage = "collect_addresses"
+	StageWriteSpec                Stage = "write_spec"
+	StageCollectLocalServices     Stage = "collect_local_services"
+	StageGenerateMain             Stage = "generate_main"
+	StageWriteSourceCode          Stage = "write_source_code"
+	StageCheckGeneratedCode       Stage = "check_generated_code"
+)
+
+var Stages = []Stage{
+	StageParseSpec,
+	StageParseWiring,
+	StageInitModifiers,
+	StageConvertIR,
+	StagePrintIR,
+	StageCoLocatedServices,
+	StageDependencyGraph,
+	StageRemoteTypes,
+	StageCollectClients,
+	StageGenerateSourceCode,
+	StageGenerateClientSourceCode,
+	StageDeploy,
+	StageDeployModifiers,
+	StageCollectAddresses,
+	StageWriteSpec,
+	StageCollectLocalServices,
+	StageGenerateMain,
+	StageWriteSourceCode,
+	StageCheckGeneratedCode,
+}
+
+// A Hook runs after a stage has completed. Returning an error stops the compilation.
+type Hook func(ctx context.Context, state *State) error
+
+// VisitorHook returns a Hook that runs the visitor created by newVisitor over the IR
+func VisitorHook(newVisitor func(state *State) generators.Visitor) Hook {
+	return func(ctx context.Context, state *State) error {
+		if state.Root == nil {
+			return errors.New("the IR has not been built yet")
+		}
+		state.Root.Accept(newVisitor(state))
+		return nil
+	}
+}
+
+// DumpIRHook returns a Hook that writes the IR as a JSON document to filename, see generators.MarshalIR. The
+// document can be loaded with generators.UnmarshalIR.
+func DumpIRHook(filename string) Hook {
+	return func(ctx context.Context, state *State) error {
+		if state.Root == nil {
+			return errors.New("the IR has not been built yet")
+		}
+		jsonVisitor := generators.NewJSONVisitor()
+		state.Root.Accept(jsonVisitor)
+		if jsonVisitor.Err != nil {
+			return jsonVisitor.Err
+		}
+		return ioutil.WriteFile(filename, jsonVisitor.Data, 0644)
+	}
+}
+
+// Returns the stage called name
+func ParseStage(name string) (Stage, bool) {
+	for _, stage := range Stages {
+		if string(stage) == name {
+			return stage, true
+		}
+	}
+	return "", false
+}
+
+type Options struct {
+	// Logger receives the log output of the compiler. Log output is discarded if nil.
+	Logger *log.Logger
+	// Hooks are run after the stage they are registered for
+	Hooks map[Stage][]Hook
+	// Progress is called after every completed stage
+	Progress func(stage Stage)
+}
+
+// State holds the intermediate results of the pipeline. Fields are filled in as the stages complete.
+type State struct {
+	Config          *parser.Config
+	Logger          *log.Logger
+	Diagnostics     *parser.Diagnostics
+	Spec            *parser.SpecParser
+	Wiring          *parser.MillenialNode
+	ModRegistry     *generators.ModifierRegistry
+	Root            *generators.MillenialNode
+	DepGraph        *generators.DependencyGraph
+	Addresses       map[string]generators.ConnInfo
+	CoLocated       map[string][]string
+	Clients         *generators.ClientCollectorVisitor
+	SourceGenerator *generators.GenerateSourceCodeVisitor
+	LocalInfos      map[string]map[string]string
+	PortAuthority   *deploy.PortAuthority
+}
+
+type Result struct {
+	Root        *generators.MillenialNode
+	DepGraph    *generators.DependencyGraph
+	Addresses   map[string]generators.ConnInfo
+	Files       []string // Files created or modified in the output directory, sorted
+	Removed     []string // Files of a previous run that were removed from the output directory, sorted
+	Diagnostics *parser.Diagnostics
+}
+
+// DiagnosticsError is returned when the specification or the wiring file contains errors
+type DiagnosticsError struct {
+	Diagnostics *parser.Diagnostics
+}
+
+func (e *DiagnosticsError) Error() string {
+	var lines []string
+	for _, diag := range e.Diagnostics.Sorted() {
+		if diag.Severity == parser.SeverityError {
+			lines = append(lines, diag.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// The generators and the parser keep package-level state, so only one pipeline runs at a time
+var pipelineLock sync.Mutex
+
+// Compile runs the whole pipeline for config. On success the returned Result also carries any warnings. Concurrent
+// calls of Compile, Graph and Lint are serialized.
+func Compile(ctx context.Context, config *parser.Config, opts Options) (result *Result, err error) {
+	pipelineLock.Lock()
+	defer pipelineLock.Unlock()
+	diags := parser.NewDiagnostics()
+	defer func() {
+		// Generators that report a diagnostic stop the compilation by aborting it
+		var abort *parser.AbortError
+		if errors.As(err, &abort) && diags.HasErrors() {
+			err = &DiagnosticsError{Diagnostics: diags}
+		}
+	}()
+	defer parser.RecoverAbort(&err)
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", 0)
+	}
+	state := &State{Config: config, Logger: logger, Diagnostics: diags}
+	start := time.Now()
+	parser.ResetWrittenFiles()
+	generators.ResetState()
+	if err := parser.LoadTemplates(config.TemplateDir); err != nil {
+		return nil, err
+	}
+	for _, stage := range Stages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		runStage(stage, state)
+		if state.Diagnostics.HasErrors() {
+			if continueWithErrors(stage) {
+				continue
+			}
+			return nil, &DiagnosticsError{Diagnostics: state.Diagnostics}
+		}
+		for _, hook := range opts.Hooks[stage] {
+			if err := hook(ctx, state); err != nil {
+				return nil, err
+			}
+		}
+		if opts.Progress != nil {
+			opts.Progress(stage)
+		}
+	}
+	generated, err := generatedFiles(config.OutDir, start)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := pruneStaleFiles(config.OutDir, generated)
+	if err != nil {
+		return nil, err
+	}
+	files, err := modifiedFiles(config.OutDir, start)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeManifest(config.OutDir, generated); err != nil {
+		return nil, err
+	}
+	return &Result{Root: state.Root, DepGraph: state.DepGraph, Addresses: state.Addresses, Files: files, Removed: removed, Diagnostics: state.Diagnostics}, nil
+}
+
+// Errors in the specification are reported together with those in the wiring file, so the wiring is parsed even if
+// the specification has errors
+func continueWithErrors(stage Stage) bool {
+	return stage == StageParseSpec
+}
+
+func runStage(stage Stage, s *State) {
+	config, logger := s.Config, s.Logger
+	switch stage {
+	case StageParseSpec:
+		s.Spec = parser.NewSpecParser(config, logger, s.Diagnostics)
+		s.Spec.ParseSpec()
+	case StageParseWiring:
+		wiringParser := parser.NewWiringParser(config, logger, s.Diagnostics)
+		wiringParser.ParseWiring()
+		s.Wiring = wiringParser.RootNode
+	case StageInitModifiers:
+		s.ModRegistry = generators.InitModifierRegistry(logger, s.Diagnostics)
+	case StageConvertIR:
+		generator := generators.NewGenerator(config, logger, s.Spec.Implementations, s.ModRegistry, s.Diagnostics)
+		generator.ConvertSerializedRep(s.Wiring)
+		s.Root = generator.RootNode
+		if s.Root != nil {
+			s.ModRegistry.CheckOrders(s.Root)
+			s.ModRegistry.CheckFrameworks(s.Root)
+		}
+	case StagePrintIR:
+		printVisitor := generators.NewPrintVisitor(logger)
+		s.Root.Accept(printVisitor)
+		printVisitor.Print()
+	case StageCoLocatedServices:
+		coLocatedServiceVisitor := generators.NewCoLocatedServiceInfosVisitor(logger)
+		s.Root.Accept(coLocatedServiceVisitor)
+		s.CoLocated = coLocatedServiceVisitor.CoLocatedServices
+	case StageDependencyGraph:
+		depGraphVisitor := generators.NewDependencyGraphVisitor(logger, s.Spec.Implementations, s.Spec.Services)
+		s.Root.Accept(depGraphVisitor)
+		s.DepGraph = depGraphVisitor.DepGraph
+		logger.Println("Dependency graph is as follows: \n" + s.DepGraph.String())
+		for _, cycle := range s.DepGraph.Cycles() {
+			s.Diagnostics.Errorf(instancePos(s.Wiring, cycle[0]), "dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+		if s.Diagnostics.HasErrors() {
+			return
+		}
+		s.DepGraph.TopoSort(s.CoLocated)
+		logger.Println("Start order:", strings.Join(s.DepGraph.StartOrder(), ", "))
+	case StageRemoteTypes:
+		// Apply source code modifiers + generate network layer node files
+		fixRemoteTypeVisitor := generators.NewRemoteTypeVisitor(logger, s.Spec.RemoteTypes, s.Spec.PathPkgs, s.Spec.Implementations)
+		s.Root.Accept(fixRemoteTypeVisitor)
+	case StageCollectClients:
+		s.Clients = generators.NewClientCollectorVisitor(logger, s.Spec.Implementations, s.Spec.PathPkgs, config.SrcDir, s.Spec.RemoteTypes, s.Spec.Services)
+		s.Root.Accept(s.Clients)
+	case StageGenerateSourceCode:
+		s.SourceGenerator = generators.NewGenerateSourceCodeVisitor(logger, s.ModRegistry, config.AppName, config.OutDir, s.Spec.RemoteTypes, s.Clients.DefaultClientInfos, s.Spec.PathPkgs, s.Spec.Implementations, config.SrcDir, s.Spec.Enums)
+		s.Root.Accept(s.SourceGenerator)
+	case StageGenerateClientSourceCode:
+		generateClientVisitor := generators.NewGenerateClientSourceCodeVisitor(logger, s.ModRegistry, config.AppName, config.OutDir, s.Spec.RemoteTypes, s.Clients.DefaultClientInfos, s.Spec.PathPkgs, s.Spec.Implementations, config.SrcDir, s.Spec.Enums, s.Spec.Services, s.DepGraph)
+		s.Root.Accept(generateClientVisitor)
+	case StageDeploy:
+		// Addr + Port Resolution
+		s.PortAuthority = deploy.NewPortAuthority(logger)
+		basicDeployVisitor := generators.NewBasicDeployVisitor(logger, config, s.PortAuthority)
+		s.Root.Accept(basicDeployVisitor)
+	case StageDeployModifiers:
+		depModVisitor := generators.NewDeployModifierVisitor(logger, s.ModRegistry)
+		s.Root.Accept(depModVisitor)
+	case StageCollectAddresses:
+		addrCollectorVisitor := generators.NewAddrCollectorVisitor(logger)
+		s.Root.Accept(addrCollectorVisitor)
+		s.Addresses = addrCollectorVisitor.Addrs
+	case StageWriteSpec:
+		specWriterVisitor := generators.NewSpecSourceWriterVisitor(logger, config.OutDir, config.AppName, config.SrcDir, s.Spec.RemoteTypes, s.Spec.Services, s.Spec.PathPkgs)
+		s.Root.Accept(specWriterVisitor)
+	case StageCollectLocalServices:
+		localServicesInfoCollectorVisitor := generators.NewLocalServicesInfoCollectorVisitor(logger)
+		s.Root.Accept(localServicesInfoCollectorVisitor)
+		s.LocalInfos = localServicesInfoCollectorVisitor.LocalServiceInfos
+	case StageGenerateMain:
+		// Generate main functions, run scripts, container config files
+		mainVisitor := generators.NewMainVisitor(logger, config.OutDir, s.Spec.PathPkgs, s.Spec.Implementations, config.SrcDir, deploy.GetDepGenFactory(), s.Addresses, config.Inventory, s.SourceGenerator.Frameworks, s.DepGraph, s.LocalInfos)
+		s.Root.Accept(mainVisitor)
+	case StageWriteSourceCode:
+		writerVisitor := generators.NewSourceCodeWriterVisitor(logger, config.OutDir, config.AppName, config.SrcDir, s.Spec.RemoteTypes, s.Spec.Services, s.Spec.PathPkgs)
+		s.Root.Accept(writerVisitor)
+	case StageCheckGeneratedCode:
+		// Format and type-check the Go code generated for the containers
+		checkGeneratedCode(s)
+	}
+}
+
+// Returns the files in dir that were modified after start. Generated files are written by the
+// visitors as well as by external tools (protoc, thrift, kompose), so the output directory is inspected.
+func modifiedFiles(dir string, start time.Time) ([]string, error) {
+	var files []string
+	// Some filesystems only keep modification times with a one second granularity
+	start = start.Truncate(time.Second)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.ModTime().Before(start) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Returns the position of the named instance in the wiring file
+func instancePos(wiring *parser.MillenialNode, name string) token.Position {
+	var find func(nodes []parser.DetailNode) (token.Position, bool)
+	find = func(nodes []parser.DetailNode) (token.Position, bool) {
+		for _, node := range nodes {
+			if node.Name == name {
+				return node.Pos, true
+			}
+			if pos, ok := find(node.Children); ok {
+				return pos, true
+			}
+		}
+		return token.Position{}, false
+	}
+	if wiring != nil {
+		for _, container := range wiring.Children {
+			if pos, ok := find(container.Children); ok {
+				return pos
+			}
+		}
+	}
+	return token.Position{}
+}
@@ -0,0 +1,301 @@
+package blueprint
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+type serviceCounter struct {
+	generators.DefaultVisitor
+	count int
+}
+
+func (v *serviceCounter) VisitFuncServiceNode(_ generators.Visitor, n *generators.FuncServiceNode) {
+	v.count += 1
+}
+
+// The spec parser resolves the standard library relative to the repository root
+func chdirRoot(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(".."); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func leafConfig(t *testing.T, wiring string) *parser.Config {
+	return &parser.Config{AppName: "leaf", SrcDir: "examples/Leaf/input/input_go", OutDir: t.TempDir(), WiringFile: wiring, Target: "go"}
+}
+
+func TestCompile(t *testing.T) {
+	chdirRoot(t)
+	config := leafConfig(t, "examples/Leaf/wiring/instances_all_in_one.py")
+	counter := &serviceCounter{}
+	var completed []Stage
+	opts := Options{
+		Hooks: map[Stage][]Hook{
+			StageConvertIR: {VisitorHook(func(*State) generators.Visitor { return counter })},
+		},
+		Progress: func(stage Stage) { completed = append(completed, stage) },
+	}
+	result, err := Compile(context.Background(), config, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter.count != 2 {
+		t.Errorf("Expected the hook to visit 2 services, visited %d", counter.count)
+	}
+	if len(completed) != len(Stages) {
+		t.Errorf("Expected progress for %d stages, got %v", len(Stages), completed)
+	}
+	if _, ok := result.Addresses["leafService"]; !ok {
+		t.Errorf("Missing address for leafService in %v", result.Addresses)
+	}
+	if result.Root == nil || result.DepGraph == nil {
+		t.Error("Expected the IR and the dependency graph in the result")
+	}
+	found := false
+	for _, file := range result.Files {
+		if filepath.Base(file) == "docker-compose.yml" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("docker-compose.yml not reported in written files %v", result.Files)
+	}
+}
+
+// Compile is a library, the generators log to the logger of the options instead of printing
+func TestCompileLogsToLogger(t *testing.T) {
+	chdirRoot(t)
+	config, err := parser.ParseConfig("blueprint/testdata/golden/configs/config_go_ansible.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.OutDir = t.TempDir()
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	var logs strings.Builder
+	_, err = Compile(context.Background(), config, Options{Logger: log.New(&logs, "", 0)})
+	os.Stdout = stdout
+	w.Close()
+	printed, _ := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(printed) != 0 {
+		t.Errorf("Expected nothing on stdout, got\n%s", printed)
+	}
+	if !strings.Contains(logs.String(), "Copying setup files..") {
+		t.Errorf("Expected the progress of the Ansible deployer in the logs")
+	}
+}
+
+func TestCompileReportsDiagnostics(t *testing.T) {
+	chdirRoot(t)
+	wiring := filepath.Join(t.TempDir(), "wiring.py")
+	if err := os.WriteFile(wiring, []byte("x = 1\ns : Service = Missing()\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Compile(context.Background(), leafConfig(t, wiring), Options{})
+	var diagErr *DiagnosticsError
+	if !errors.As(err, &diagErr) || diagErr.Diagnostics.ErrorCount() != 1 {
+		t.Fatalf("Expected wiring diagnostics, got %v", err)
+	}
+}
+
+func TestCompileReportsSpecAndWiringErrors(t *testing.T) {
+	chdirRoot(t)
+	srcDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(srcDir, "service.go"), []byte("package services\n\nfunc (\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wiring := filepath.Join(t.TempDir(), "wiring.py")
+	if err := os.WriteFile(wiring, []byte("x = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := leafConfig(t, wiring)
+	config.SrcDir = srcDir
+	_, err := Compile(context.Background(), config, Options{})
+	var diagErr *DiagnosticsError
+	if !errors.As(err, &diagErr) {
+		t.Fatalf("Expected diagnostics, got %v", err)
+	}
+	files := make(map[string]bool)
+	for _, diag := range diagErr.Diagnostics.Items {
+		files[filepath.Base(diag.Pos.Filename)] = true
+	}
+	if !files["service.go"] || !files["wiring.py"] {
+		t.Errorf("Expected errors in the specification and the wiring file, got %v", diagErr.Diagnostics.Items)
+	}
+}
+
+func TestCompileCancelled(t *testing.T) {
+	chdirRoot(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := Options{Hooks: map[Stage][]Hook{StageParseSpec: {func(context.Context, *State) error {
+		cancel()
+		return nil
+	}}}}
+	_, err := Compile(ctx, leafConfig(t, "examples/Leaf/wiring/instances_all_in_one.py"), opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDumpIRRoundTrip(t *testing.T) {
+	chdirRoot(t)
+	dump := filepath.Join(t.TempDir(), "ir.json")
+	opts := Options{Hooks: map[Stage][]Hook{StageWriteSourceCode: {DumpIRHook(dump)}}}
+	result, err := Compile(context.Background(), leafConfig(t, "examples/Leaf/wiring/instances_all_in_one.py"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := generators.UnmarshalIR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := generators.MarshalIR(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(data) {
+		t.Error("The loaded IR does not serialize to the same document")
+	}
+
+	services := root.GetNodes("FuncServiceNode")
+	if len(services) != len(result.Root.GetNodes("FuncServiceNode")) {
+		t.Fatalf("Expected %d services in the loaded IR, got %d", len(result.Root.GetNodes("FuncServiceNode")), len(services))
+	}
+	for _, node := range services {
+		service := node.(*generators.FuncServiceNode)
+		addr := result.Addresses[service.Name]
+		if service.DepInfo == nil || service.DepInfo.Port != addr.Port {
+			t.Errorf("Deploy info of %s was not restored, expected port %d", service.Name, addr.Port)
+		}
+		if len(service.ASTServerNodes) == 0 {
+			t.Errorf("Server chain of %s was not restored", service.Name)
+		}
+	}
+}
+
+func TestUnmarshalIRKeepsSharedNodes(t *testing.T) {
+	modifier := &generators.RetryModifier{NoOpSourceCodeModifier: &generators.NoOpSourceCodeModifier{DefaultModifier: &generators.DefaultModifier{}}}
+	service := &generators.FuncServiceNode{ServiceNode: generators.ServiceNode{
+		Name:            "leafService",
+		ClientModifiers: []generators.Modifier{modifier},
+		ASTServerNodes:  []*generators.ServiceImplInfo{{Name: "LeafServiceImpl", ModifierNode: modifier}},
+	}}
+	data, err := generators.MarshalIR(&generators.MillenialNode{Children: []generators.Node{service}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := generators.UnmarshalIR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := root.Children[0].(*generators.FuncServiceNode)
+	if loaded.ASTServerNodes[0].ModifierNode != loaded.ClientModifiers[0] {
+		t.Error("Expected the modifier to be shared by the service and its server chain")
+	}
+	if _, err := generators.UnmarshalIR([]byte(`{"version": 1, "root": {"Children": [{"@type": "UnknownNode"}]}}`)); err == nil {
+		t.Error("Expected an error for an unregistered node type")
+	}
+}
+
+func TestCompileDependencyCycle(t *testing.T) {
+	chdirRoot(t)
+	_, err := Compile(context.Background(), leafConfig(t, "blueprint/testdata/dependencies/cycle.py"), Options{})
+	var diagErr *DiagnosticsError
+	if !errors.As(err, &diagErr) {
+		t.Fatalf("Expected a dependency cycle, got %v", err)
+	}
+	expected := "blueprint/testdata/dependencies/cycle.py:7: error: dependency cycle: backService -> frontService -> backService"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestCompileStartOrder(t *testing.T) {
+	chdirRoot(t)
+	config := leafConfig(t, "blueprint/testdata/dependencies/chain.py")
+	result, err := Compile(context.Background(), config, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := strings.Join(result.DepGraph.StartOrder(), " ")
+	if order != "leafService frontService" {
+		t.Errorf("Expected leafService to start before frontService, got %s", order)
+	}
+	compose, err := os.ReadFile(filepath.Join(config.OutDir, "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(compose), "    depends_on:\n      - container2\n") {
+		t.Errorf("Expected container1 to depend on container2 in\n%s", compose)
+	}
+}
+
+func TestDependencyGraphCycles(t *testing.T) {
+	graph := generators.NewDependencyGraph()
+	graph.AddEdge("b", "a")
+	graph.AddEdge("c", "b")
+	graph.AddEdge("a", "c")
+	graph.AddEdge("d", "c")
+	graph.AddEdge("e", "e")
+	cycles := graph.Cycles()
+	expected := [][]string{{"a", "b", "c", "a"}, {"e", "e"}}
+	if !reflect.DeepEqual(cycles, expected) {
+		t.Errorf("Expected cycles %v, got %v", expected, cycles)
+	}
+}
+
+func TestCompileTemplateOverrides(t *testing.T) {
+	chdirRoot(t)
+	config := leafConfig(t, "examples/Leaf/wiring/instances_all_in_one.py")
+	config.TemplateDir = t.TempDir()
+	text, _ := parser.DefaultTemplate("web.server_method")
+	override := "log.Println(\"Handling {{.Method.Name}}\")\n" + text
+	if err := os.WriteFile(filepath.Join(config.TemplateDir, "web.server_method.tmpl"), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Compile(context.Background(), config, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	handler, err := os.ReadFile(filepath.Join(config.OutDir, "container1", "process", "NonLeafServiceImplHandler.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(handler), "log.Println(\"Handling Leaf\")") {
+		t.Errorf("Expected the override in\n%s", handler)
+	}
+
+	if err := os.WriteFile(filepath.Join(config.TemplateDir, "web.server_methods.tmpl"), []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Compile(context.Background(), config, Options{})
+	if err == nil || !strings.Contains(err.Error(), "no template is named web.server_methods") {
+		t.Errorf("Expected an error for the unknown template, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package blueprint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Number of unchanged lines shown around every change of a unified diff
+const diffContext = 3
+
+// An edit of a line diff: ' ' keeps a line, '-' deletes a line of the old text and '+' inserts a line of the new text.
+// oldIdx and newIdx are the positions of the edit in the old and the new lines.
+type lineEdit struct {
+	kind   byte
+	line   string
+	oldIdx int
+	newIdx int
+}
+
+// Splits text into lines that keep their line endings
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Returns the shortest edit script turning old into new, computed with the algorithm of Myers
+func diffLines(old []string, new []string) []lineEdit {
+	n, m := len(old), len(new)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+	done := false
+	for d := 0; d <= max && !done; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && old[x] == new[y] {
+				x += 1
+				y += 1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+	}
+
+	// Walk the trace backwards from the end of both texts
+	var edits []lineEdit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prev_k int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prev_k = k + 1
+		} else {
+			prev_k = k - 1
+		}
+		prev_x := v[offset+prev_k]
+		prev_y := prev_x - prev_k
+		for x > prev_x && y > prev_y {
+			edits = append(edits, lineEdit{' ', old[x-1], x - 1, y - 1})
+			x -= 1
+			y -= 1
+		}
+		if prev_k == k+1 {
+			edits = append(edits, lineEdit{'+', new[y-1], x, y - 1})
+		} else {
+			edits = append(edits, lineEdit{'-', old[x-1], x - 1, y})
+		}
+		x, y = prev_x, prev_y
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, lineEdit{' ', old[x-1], x - 1, y - 1})
+		x -= 1
+		y -= 1
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// Returns the start and length of a hunk range in the format of unified diffs
+func hunkRange(start int, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// unifiedDiff returns the changes from old to new in the unified diff format, or an empty string if they are equal
+func unifiedDiff(oldName string, newName string, old string, new string) string {
+	if old == new {
+		return ""
+	}
+	if strings.Contains(old, "\x00") || strings.Contains(new, "\x00") {
+		return fmt.Sprintf("Binary files %s and %s differ\n", oldName, newName)
+	}
+	edits := diffLines(splitLines(old), splitLines(new))
+	var changes []int
+	for idx, edit := range edits {
+		if edit.kind != ' ' {
+			changes = append(changes, idx)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(changes); {
+		// Changes that are separated by few unchanged lines share a hunk
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*diffContext+1 {
+			j += 1
+		}
+		start := changes[i] - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := changes[j] + diffContext + 1
+		if end > len(edits) {
+			end = len(edits)
+		}
+		old_len, new_len := 0, 0
+		for _, edit := range edits[start:end] {
+			if edit.kind != '+' {
+				old_len += 1
+			}
+			if edit.kind != '-' {
+				new_len += 1
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(edits[start].oldIdx, old_len), hunkRange(edits[start].newIdx, new_len))
+		for _, edit := range edits[start:end] {
+			b.WriteByte(edit.kind)
+			b.WriteString(edit.line)
+			if !strings.HasSuffix(edit.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = j + 1
+	}
+	return b.String()
+}
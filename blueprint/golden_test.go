@@ -0,0 +1,228 @@
+package blueprint
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/deploy"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/netgen"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden output of TestGolden")
+
+// Configs compiled by TestGolden. The golden output of a config is in testdata/golden/output/<config name>.
+var goldenConfigs = []string{"examples/Leaf/input/*.json", "blueprint/testdata/golden/configs/*.json"}
+
+// External tools that the frameworks run during compilation. Configs that use a missing tool are skipped, and don't
+// count towards the frameworks that the configs cover.
+var goldenTools = map[string]string{"grpc": "protoc", "aiothrift": "thrift", "kubernetes": "kompose"}
+
+var wiringFramework = regexp.MustCompile(`framework\s*=\s*"(\w+)"`)
+
+// Returns the files of the output that are compared against the golden output: the files written by Blueprint
+// except for the copy of the specification and the code generated by protoc or thrift, which depend on the version
+// of the tools. The output directory is replaced with $OUT_DIR in the content of the files.
+func goldenFiles(outDir string) (map[string][]byte, error) {
+	absOutDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for _, name := range parser.WrittenFiles() {
+		rel, ok := relativePath(absOutDir, name)
+		if !ok || rel == ManifestFile || strings.HasPrefix(rel, "spec/") || strings.HasPrefix(rel, "gen-go/") || strings.HasSuffix(rel, "go.sum") {
+			continue
+		}
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		files[rel] = bytes.ReplaceAll(data, []byte(absOutDir), []byte("$OUT_DIR"))
+	}
+	return files, nil
+}
+
+func TestGolden(t *testing.T) {
+	chdirRoot(t)
+	var configs []string
+	for _, pattern := range goldenConfigs {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		configs = append(configs, matches...)
+	}
+	// Frameworks used by a config, and by a config that ran
+	used, covered := make(map[string]bool), make(map[string]bool)
+	// Tools missing to run the configs of a framework
+	skipped := make(map[string]string)
+	for _, configFile := range configs {
+		name := strings.TrimSuffix(filepath.Base(configFile), ".json")
+		config, err := parser.ParseConfig(configFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wiring, err := ioutil.ReadFile(config.WiringFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var frameworks, missing []string
+		for _, match := range wiringFramework.FindAllStringSubmatch(string(wiring), -1) {
+			frameworks = append(frameworks, match[1])
+			used[match[1]] = true
+			if tool, ok := goldenTools[match[1]]; ok {
+				if _, err := exec.LookPath(tool); err != nil {
+					missing = append(missing, tool)
+				}
+			}
+		}
+		for _, framework := range frameworks {
+			if len(missing) == 0 {
+				covered[framework] = true
+			} else {
+				skipped[framework] = strings.Join(missing, ", ")
+			}
+		}
+		t.Run(name, func(t *testing.T) {
+			if len(missing) > 0 {
+				t.Skipf("%s not installed", strings.Join(missing, ", "))
+			}
+			config.OutDir = t.TempDir()
+			if _, err := Compile(context.Background(), config, Options{}); err != nil {
+				t.Fatal(err)
+			}
+			generated, err := goldenFiles(config.OutDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			goldenDir := filepath.Join("blueprint", "testdata", "golden", "output", name)
+			if *update {
+				if err := os.RemoveAll(goldenDir); err != nil {
+					t.Fatal(err)
+				}
+				for path, data := range generated {
+					filename := filepath.Join(goldenDir, filepath.FromSlash(path))
+					if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+						t.Fatal(err)
+					}
+					if err := parser.WriteFile(filename, data, parser.FileMode); err != nil {
+						t.Fatal(err)
+					}
+				}
+				return
+			}
+			golden, err := listFiles(goldenDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, path := range parser.SortedKeys(golden) {
+				if _, ok := generated[path]; !ok {
+					t.Errorf("%s is not generated anymore", path)
+				}
+			}
+			for _, path := range parser.SortedKeys(generated) {
+				if !golden[path] {
+					t.Errorf("%s is generated but not in the golden output", path)
+					continue
+				}
+				want, err := ioutil.ReadFile(filepath.Join(goldenDir, filepath.FromSlash(path)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := unifiedDiff("golden/"+path, "generated/"+path, string(want), string(generated[path])); diff != "" {
+					t.Errorf("%s differs from the golden output:\n%s", path, diff)
+				}
+			}
+		})
+	}
+	// Every framework has to be compiled by one of the configs, configs skipped for a missing tool don't count
+	var frameworks []string
+	frameworks = append(frameworks, parser.SortedKeys(netgen.GetNetGenFactory().GeneratorFuncs)...)
+	frameworks = append(frameworks, parser.SortedKeys(deploy.GetDepGenFactory().GeneratorFuncs)...)
+	for _, framework := range frameworks {
+		if !used[framework] {
+			t.Errorf("No golden config uses the %s framework", framework)
+		} else if !covered[framework] {
+			t.Errorf("The golden configs that use the %s framework were skipped, %s not installed", framework, skipped[framework])
+		}
+	}
+}
+
+// The client modifiers run from the caller to the network in the order of the wiring file, Retry calls the
+// CircuitBreaker once per attempt
+func TestGoldenClientNesting(t *testing.T) {
+	chdirRoot(t)
+	config, err := parser.ParseConfig("blueprint/testdata/golden/configs/config_go_resilience.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	topology, err := Graph(context.Background(), config, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"ClientPool", "RetryModifier", "CircuitBreakerModifier", "TimeoutModifier", "ConcurrencyLimiterModifier"}
+	var modifiers []string
+	for _, edge := range topology.Edges {
+		if edge.From == "nonleafService" && edge.To == "leafService" {
+			modifiers = edge.Modifiers
+		}
+	}
+	if !reflect.DeepEqual(modifiers, expected) {
+		t.Errorf("Expected the client modifiers %v, got %v", expected, modifiers)
+	}
+}
+
+// Returns how often the functions of the generated file call each function, outside of function literals such as
+// the one that creates the clients of a ClientPool, and inside of them
+func generatedCalls(t *testing.T, filename string) (map[string]int, map[string]int) {
+	file, err := goparser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside, inside := make(map[string]int), make(map[string]int)
+	var count func(node ast.Node, calls map[string]int)
+	count = func(node ast.Node, calls map[string]int) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				count(n.Body, inside)
+				return false
+			case *ast.CallExpr:
+				if ident, ok := n.Fun.(*ast.Ident); ok {
+					calls[ident.Name] += 1
+				}
+			}
+			return true
+		})
+	}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			count(fn.Body, outside)
+		}
+	}
+	return outside, inside
+}
+
+// The clients created by a ClientPool share the state of their modifiers, which is created once, before the function
+// that creates the pooled clients
+func TestGoldenPooledClientsShareState(t *testing.T) {
+	outside, inside := generatedCalls(t, "testdata/golden/output/config_go_resilience/container2/proc2/nonleafService.go")
+	for _, constructor := range []string{"NewLeafServiceImplClientConcurrencyLimiterLimiter", "NewLeafServiceImplRetrierRetrier", "NewLeafServiceImplCircuitBreakerBreaker"} {
+		if outside[constructor] != 1 || inside[constructor] != 0 {
+			t.Errorf("Expected %s to be called once, outside of the function that creates the pooled clients, got %d calls outside and %d inside", constructor, outside[constructor], inside[constructor])
+		}
+	}
+}
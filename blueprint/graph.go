@@ -0,0 +1,36 @@
+package blueprint
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Graph builds the IR of the application and returns its topology, without generating any code. Errors in the
+// specification or the wiring file are returned as a *DiagnosticsError.
+func Graph(ctx context.Context, config *parser.Config, opts Options) (topology *generators.Topology, err error) {
+	pipelineLock.Lock()
+	defer pipelineLock.Unlock()
+	defer parser.RecoverAbort(&err)
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", 0)
+	}
+	state := &State{Config: config, Logger: logger, Diagnostics: parser.NewDiagnostics()}
+	for _, stage := range []Stage{StageParseSpec, StageParseWiring, StageInitModifiers, StageConvertIR} {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		runStage(stage, state)
+		if state.Diagnostics.HasErrors() && !continueWithErrors(stage) {
+			return nil, &DiagnosticsError{Diagnostics: state.Diagnostics}
+		}
+	}
+	topologyVisitor := generators.NewTopologyVisitor(logger, state.ModRegistry, config.AppName)
+	state.Root.Accept(topologyVisitor)
+	return topologyVisitor.Topology, nil
+}
@@ -0,0 +1,65 @@
+package blueprint
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+)
+
+func TestGraph(t *testing.T) {
+	chdirRoot(t)
+	topology, err := Graph(context.Background(), leafConfig(t, "examples/Leaf/wiring/instances.py"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topology.Clusters) != 5 || topology.Clusters[3].Clusters[0].Nodes[0].Name != "leafService" {
+		t.Fatalf("Unexpected clusters %+v", topology.Clusters)
+	}
+	var calls []generators.TopologyEdge
+	for _, edge := range topology.Edges {
+		if !edge.Modifier {
+			calls = append(calls, edge)
+		}
+	}
+	expected := []generators.TopologyEdge{{From: "nonleafService", To: "leafService", Modifiers: []string{"ClientPool", "RetryModifier"}}}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %+v, got %+v", expected, calls)
+	}
+
+	dot := topology.DOT()
+	for _, line := range []string{
+		`subgraph cluster_4 {`,
+		`"leafService" [label="leafService\nLeafServiceImpl"];`,
+		`"jaegerTracer" [label="jaegerTracer\nJaegerTracer", shape=ellipse];`,
+		`"nonleafService" -> "leafService" [label="ClientPool, RetryModifier"];`,
+		`"nonleafService" -> "jaegerTracer" [label="TracerModifier", style=dashed];`,
+	} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("Expected %s in\n%s", line, dot)
+		}
+	}
+}
+
+func TestTopologyMermaid(t *testing.T) {
+	topology := &generators.Topology{
+		Name: "app",
+		Clusters: []*generators.TopologyCluster{{Name: "container1", Type: "DockerContainer", Nodes: []*generators.TopologyNode{
+			{Name: "frontend", Type: "FrontendImpl", Kind: generators.TopologyService},
+		}}},
+		Nodes: []*generators.TopologyNode{{Name: "cache", Type: "RedisCache", Kind: generators.TopologyBackend}},
+		Edges: []generators.TopologyEdge{{From: "frontend", To: "cache", Modifiers: []string{"ClientPool", "CircuitBreakerModifier"}}},
+	}
+	expected := `flowchart LR
+	subgraph c0["container1 (DockerContainer)"]
+		n0["frontend<br/>FrontendImpl"]
+	end
+	n1[("cache<br/>RedisCache")]
+	n0 -->|"ClientPool, CircuitBreakerModifier"| n1
+`
+	if mermaid := topology.Mermaid(); mermaid != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, mermaid)
+	}
+}
@@ -0,0 +1,238 @@
+package blueprint
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Lint rules. They are used as the code of the reported diagnostics.
+const (
+	RuleContextFirst            = "context-first"
+	RuleErrorLast               = "error-last"
+	RuleUnserializableType      = "unserializable-type"
+	RuleMissingConstructor      = "missing-constructor"
+	RuleAmbiguousImplementation = "ambiguous-implementation"
+)
+
+// Lint checks the services and implementations of the specification against Blueprint's conventions without
+// generating any code. The wiring file decides which services are remote. Without a wiring file, or if the wiring
+// cannot be converted, every service is treated as remote. Problems in the specification or the wiring are
+// reported in the returned diagnostics together with the lint findings.
+func Lint(ctx context.Context, config *parser.Config, opts Options) (diags *parser.Diagnostics, err error) {
+	pipelineLock.Lock()
+	defer pipelineLock.Unlock()
+	defer parser.RecoverAbort(&err)
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", 0)
+	}
+	state := &State{Config: config, Logger: logger, Diagnostics: parser.NewDiagnostics()}
+	runStage(StageParseSpec, state)
+	if state.Diagnostics.HasErrors() {
+		return state.Diagnostics, nil
+	}
+	var remote map[string]bool
+	if config.WiringFile != "" {
+		remote = remoteImplementations(state)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	l := &linter{spec: state.Spec, srcDir: filepath.Clean(config.SrcDir), remote: remote, diags: state.Diagnostics, reported: make(map[string]bool)}
+	l.lint()
+	return state.Diagnostics, nil
+}
+
+// Converts the wiring and returns the implementations that are deployed behind an RPC or web server. Returns nil
+// if the wiring contains errors, they are kept in the diagnostics.
+func remoteImplementations(state *State) map[string]bool {
+	for _, stage := range []Stage{StageParseWiring, StageInitModifiers, StageConvertIR} {
+		runStage(stage, state)
+		if state.Diagnostics.HasErrors() {
+			return nil
+		}
+	}
+	remote := make(map[string]bool)
+	for _, node := range state.Root.GetNodes("FuncServiceNode") {
+		service := node.(*generators.FuncServiceNode)
+		for _, modifier := range service.ServerModifiers {
+			switch modifier.(type) {
+			case *generators.RPCServerModifier, *generators.WebServerModifier:
+				remote[service.Type] = true
+			}
+		}
+	}
+	return remote
+}
+
+type linter struct {
+	spec     *parser.SpecParser
+	srcDir   string
+	remote   map[string]bool // Remote implementations, nil if every service is remote
+	diags    *parser.Diagnostics
+	reported map[string]bool // Methods are shared through embedding, every problem is only reported once
+}
+
+// Reports a problem with subject, e.g. a method name, unless it has already been reported at the same position
+func (l *linter) report(severity parser.Severity, rule string, pos token.Position, subject string, format string, args ...interface{}) {
+	key := rule + ":" + pos.String() + ":" + subject
+	if l.reported[key] {
+		return
+	}
+	l.reported[key] = true
+	l.diags.Report(severity, rule, pos, format, args...)
+}
+
+// Only the application's own declarations are linted, not the ones from the standard library of components
+func (l *linter) inSpec(pkgPath string) bool {
+	rel, err := filepath.Rel(l.srcDir, filepath.Clean(pkgPath))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func (l *linter) lint() {
+	implemented := make(map[string][]*parser.ImplInfo) // service name -> implementations
+	for _, name := range parser.SortedKeys(l.spec.Implementations) {
+		impl := l.spec.Implementations[name]
+		services := l.implementedServices(impl)
+		for _, service := range services {
+			implemented[service.Name] = append(implemented[service.Name], impl)
+		}
+		if len(services) == 0 || !l.inSpec(impl.PkgPath) {
+			continue
+		}
+		l.lintImplementation(impl, services)
+	}
+	for _, name := range parser.SortedKeys(l.spec.Services) {
+		service := l.spec.Services[name]
+		if !l.inSpec(service.PkgPath) || len(service.Methods) == 0 {
+			continue
+		}
+		remote := l.remote == nil
+		for _, impl := range implemented[name] {
+			remote = remote || l.remote[impl.Name]
+		}
+		for _, methodName := range parser.SortedKeys(service.Methods) {
+			l.lintMethod(name, service.Methods[methodName], service.Pos, remote)
+		}
+	}
+}
+
+// Returns the services of the specification that impl implements, sorted by name
+func (l *linter) implementedServices(impl *parser.ImplInfo) []*parser.ServiceInfo {
+	if impl.Interfaces["Remote"] {
+		// Remote types are data types and not services
+		return nil
+	}
+	var services []*parser.ServiceInfo
+	for _, name := range parser.SortedKeys(impl.Interfaces) {
+		if service, ok := l.spec.Services[name]; ok && l.inSpec(service.PkgPath) && len(service.Methods) > 0 {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+func (l *linter) lintImplementation(impl *parser.ImplInfo, services []*parser.ServiceInfo) {
+	if len(impl.ConstructorInfos) == 0 {
+		l.report(parser.SeverityError, RuleMissingConstructor, impl.Pos, impl.Name, "%s implements %s but has no constructor, declare a function New%s that returns *%s", impl.Name, services[0].Name, impl.Name, impl.Name)
+	}
+	// An implementation may implement several services if one of them includes the methods of all the others,
+	// e.g. through embedding. Otherwise it is unclear which service the implementation provides.
+	var widest *parser.ServiceInfo
+	for _, service := range services {
+		if widest == nil || len(service.Methods) > len(widest.Methods) {
+			widest = service
+		}
+	}
+	for _, service := range services {
+		if !includesMethods(widest, service) {
+			var names []string
+			for _, s := range services {
+				names = append(names, s.Name)
+			}
+			l.report(parser.SeverityWarning, RuleAmbiguousImplementation, impl.Pos, impl.Name, "%s implements unrelated services %s", impl.Name, strings.Join(names, ", "))
+			break
+		}
+	}
+	// Every public method of an implementation is exposed by the generated servers and clients
+	remote := l.remote == nil || l.remote[impl.Name]
+	for _, methodName := range parser.SortedKeys(impl.Methods) {
+		method := impl.Methods[methodName]
+		if method.Public {
+			l.lintMethod(impl.Name, method, impl.Pos, remote)
+		}
+	}
+}
+
+func includesMethods(wide *parser.ServiceInfo, narrow *parser.ServiceInfo) bool {
+	for name := range narrow.Methods {
+		if _, ok := wide.Methods[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *linter) lintMethod(owner string, method parser.FuncInfo, ownerPos token.Position, remote bool) {
+	// Promoted methods of interfaces outside of the parsed sources have no position
+	pos := method.Pos
+	if !method.Pos.IsValid() {
+		pos = ownerPos
+	}
+	name := owner + "." + method.Name
+	if len(method.Args) == 0 || !isBasicType(method.Args[0].Type, parser.CONTEXT) {
+		l.report(parser.SeverityError, RuleContextFirst, pos, method.Name, "%s must take a context.Context as its first argument", name)
+	}
+	if len(method.Return) == 0 || !isBasicType(method.Return[len(method.Return)-1].Type, parser.ERROR) {
+		l.report(parser.SeverityError, RuleErrorLast, pos, method.Name, "%s must return an error as its last result", name)
+	}
+	if !remote {
+		return
+	}
+	var args []parser.ArgInfo
+	args = append(args, method.Args...)
+	args = append(args, method.Return...)
+	for idx, arg := range args {
+		if kind := unserializableKind(arg.Type); kind != "" {
+			l.report(parser.SeverityError, RuleUnserializableType, pos, fmt.Sprintf("%s:%d", method.Name, idx), "%s is called remotely but %s of type %s contains a %s which cannot be serialized", name, argDescription(arg), arg.Type.String(), kind)
+		}
+	}
+}
+
+func argDescription(arg parser.ArgInfo) string {
+	if arg.Name == "" {
+		return "a result"
+	}
+	return arg.Name
+}
+
+func isBasicType(t parser.TypeInfo, basicType parser.BasicType) bool {
+	return t.BaseType == parser.BASIC && t.Detail.TypeName == basicType
+}
+
+// Returns the kind of the part of t that cannot be sent over the network, or an empty string
+func unserializableKind(t parser.TypeInfo) string {
+	switch t.BaseType {
+	case parser.CHAN:
+		return "chan"
+	case parser.FUNC:
+		return "func"
+	case parser.MAP:
+		if kind := unserializableKind(t.KeyType()); kind != "" {
+			return kind
+		}
+		return unserializableKind(t.ElemType())
+	case parser.POINTER, parser.LIST, parser.ELLIPSIS:
+		return unserializableKind(t.ElemType())
+	}
+	return ""
+}
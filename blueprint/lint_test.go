@@ -0,0 +1,55 @@
+package blueprint
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+func TestLint(t *testing.T) {
+	chdirRoot(t)
+	config := &parser.Config{AppName: "lint", SrcDir: "blueprint/testdata/lint", OutDir: t.TempDir(), Target: "go"}
+	diags, err := Lint(context.Background(), config, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual []string
+	for _, diag := range diags.Items {
+		actual = append(actual, diag.Code+" "+strings.SplitN(diag.Message, " ", 2)[0])
+	}
+	sort.Strings(actual)
+	expected := []string{
+		RuleAmbiguousImplementation + " FileImpl",
+		RuleContextFirst + " StreamService.Lookup",
+		RuleContextFirst + " StreamServiceImpl.Lookup",
+		RuleErrorLast + " StreamService.Notify",
+		RuleErrorLast + " StreamServiceImpl.Notify",
+		RuleMissingConstructor + " StreamServiceImpl",
+		RuleUnserializableType + " StreamService.Notify",
+		RuleUnserializableType + " StreamService.Subscribe",
+		RuleUnserializableType + " StreamServiceImpl.Notify",
+		RuleUnserializableType + " StreamServiceImpl.Subscribe",
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+	for _, diag := range diags.Items {
+		if !diag.Pos.IsValid() {
+			t.Errorf("%s has no position", diag)
+		}
+	}
+}
+
+func TestLintLeaf(t *testing.T) {
+	chdirRoot(t)
+	diags, err := Lint(context.Background(), leafConfig(t, "examples/Leaf/wiring/instances.py"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags.Items) != 0 {
+		t.Errorf("expected no findings, got %v", diags.Items)
+	}
+}
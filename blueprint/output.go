@@ -0,0 +1,130 @@
+package blueprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// ManifestFile lists the files that were generated into the output directory, relative to it. Files that were
+// generated by a previous run but aren't generated anymore are removed from the output directory.
+const ManifestFile = ".blueprint-files"
+
+// Returns the files generated into outDir relative to it: the files written by the generators, including the files
+// they left unchanged, and the files that external tools such as protoc modified since start
+func generatedFiles(outDir string, start time.Time) ([]string, error) {
+	absOutDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, name := range parser.WrittenFiles() {
+		if rel, ok := relativePath(absOutDir, name); ok {
+			names[rel] = true
+		}
+	}
+	modified, err := modifiedFiles(outDir, start)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range modified {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+		if rel, ok := relativePath(absOutDir, abs); ok {
+			names[rel] = true
+		}
+	}
+	delete(names, ManifestFile)
+	var files []string
+	for name := range names {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Returns name relative to dir if it is below dir
+func relativePath(dir string, name string) (string, bool) {
+	rel, err := filepath.Rel(dir, name)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// Returns the files listed in the manifest of outDir, or nothing if outDir has no manifest
+func readManifest(outDir string) ([]string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(outDir, ManifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(string(data), "\n") {
+		// Entries that point outside of the output directory are never removed
+		name := path.Clean(line)
+		if line == "" || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			continue
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+func writeManifest(outDir string, files []string) error {
+	return parser.WriteFile(filepath.Join(outDir, ManifestFile), []byte(strings.Join(files, "\n")+"\n"), parser.FileMode)
+}
+
+// Returns the files in the manifest of outDir that are not part of generated
+func staleFiles(outDir string, generated []string) ([]string, error) {
+	previous, err := readManifest(outDir)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]bool)
+	for _, name := range generated {
+		current[name] = true
+	}
+	var stale []string
+	for _, name := range previous {
+		if !current[name] {
+			stale = append(stale, name)
+		}
+	}
+	return stale, nil
+}
+
+// Removes the stale files of outDir and the directories they leave empty. Returns the paths of the removed files.
+func pruneStaleFiles(outDir string, generated []string) ([]string, error) {
+	stale, err := staleFiles(outDir, generated)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, name := range stale {
+		filename := filepath.Join(outDir, filepath.FromSlash(name))
+		err := os.Remove(filename)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		removed = append(removed, filename)
+		// Removing a directory fails once it isn't empty
+		for dir := filepath.Dir(filename); dir != filepath.Clean(outDir); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return removed, nil
+}
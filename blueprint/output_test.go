@@ -0,0 +1,94 @@
+package blueprint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCompileWritesIncrementally(t *testing.T) {
+	chdirRoot(t)
+	config := leafConfig(t, "examples/Leaf/wiring/instances_all_in_one.py")
+	if _, err := Compile(context.Background(), config, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	dockerfile := filepath.Join(config.OutDir, "container1", "docker", "Dockerfile")
+	info, err := os.Stat(dockerfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("Expected the Dockerfile to be written with mode 0644, got %v", info.Mode().Perm())
+	}
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(dockerfile, past, past); err != nil {
+		t.Fatal(err)
+	}
+	// A file of a previous run and a file that was never generated
+	stale := filepath.Join(config.OutDir, "container2", "app", "main.go")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{stale, filepath.Join(config.OutDir, "notes.txt")} {
+		if err := os.WriteFile(name, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(name, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest, err := os.OpenFile(filepath.Join(config.OutDir, ManifestFile), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest.WriteString("container2/app/main.go\n../outside.txt\n")
+	manifest.Close()
+
+	result, err := Compile(context.Background(), config, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dockerfile); err != nil || !info.ModTime().Equal(past) {
+		t.Errorf("Expected the unchanged Dockerfile to keep its modification time, got %v", info.ModTime())
+	}
+	for _, file := range result.Files {
+		if file == dockerfile {
+			t.Errorf("Expected the unchanged Dockerfile not to be listed as written")
+		}
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != stale {
+		t.Errorf("Expected %s to be removed, got %v", stale, result.Removed)
+	}
+	if _, err := os.Stat(filepath.Join(config.OutDir, "container2")); !os.IsNotExist(err) {
+		t.Errorf("Expected the emptied directory to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(config.OutDir, "notes.txt")); err != nil {
+		t.Errorf("Expected files that were never generated to be kept, got %v", err)
+	}
+}
+
+func TestCompileIsDeterministic(t *testing.T) {
+	chdirRoot(t)
+	config := leafConfig(t, "examples/Leaf/wiring/instances_all_in_one.py")
+	if _, err := Compile(context.Background(), config, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	// Maps are iterated in a different order every time, so a single rerun could match by chance
+	for run := 0; run < 3; run++ {
+		rerun := leafConfig(t, config.WiringFile)
+		if _, err := Compile(context.Background(), rerun, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		files, err := compareDirs(rerun.OutDir, config.OutDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			if file.Status != FileUnchanged {
+				t.Errorf("Expected %s to be the same in every run, got %s\n%s", file.Path, file.Status, file.Diff)
+			}
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package blueprint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Status of a file of a Plan compared to the output directory
+type FileStatus string
+
+const (
+	FileAdded     FileStatus = "added"     // The file doesn't exist yet
+	FileModified  FileStatus = "modified"  // The file exists with a different content
+	FileUnchanged FileStatus = "unchanged" // The file exists with the same content
+	FileStale     FileStatus = "stale"     // The file was generated before but isn't anymore, it would be removed
+)
+
+// PlanFile is a file that would be written to the output directory
+type PlanFile struct {
+	Path   string // Relative to the output directory
+	Kind   string // go, dockerfile, idl, deployer or other
+	Status FileStatus
+	Diff   string // Unified diff against the existing file if the file is modified
+}
+
+// Plan describes the output that Compile would generate for a config
+type Plan struct {
+	OutDir      string
+	Topology    *generators.Topology // Containers and processes with the instances they host
+	Addresses   map[string]generators.ConnInfo
+	Ports       map[string][]int // Ports assigned by the PortAuthority, by hostname
+	Files       []PlanFile       // Sorted by path
+	Diagnostics *parser.Diagnostics
+}
+
+// DryRun compiles the application into a temporary directory and compares the result with the output directory of
+// config, which is left untouched. Errors are returned as by Compile.
+func DryRun(ctx context.Context, config *parser.Config, opts Options) (plan *Plan, err error) {
+	tmpDir, err := ioutil.TempDir("", "blueprint-plan")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	planConfig := *config
+	planConfig.OutDir = tmpDir
+	var state *State
+	hooks := make(map[Stage][]Hook)
+	for stage, stageHooks := range opts.Hooks {
+		hooks[stage] = stageHooks
+	}
+	last := Stages[len(Stages)-1]
+	hooks[last] = append(append([]Hook{}, hooks[last]...), func(ctx context.Context, s *State) error {
+		state = s
+		return nil
+	})
+	opts.Hooks = hooks
+	result, err := Compile(ctx, &planConfig, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan = &Plan{OutDir: config.OutDir, Addresses: result.Addresses, Ports: make(map[string][]int), Diagnostics: result.Diagnostics}
+	topologyVisitor := generators.NewTopologyVisitor(state.Logger, state.ModRegistry, config.AppName)
+	state.Root.Accept(topologyVisitor)
+	plan.Topology = topologyVisitor.Topology
+	for hostname, ports := range state.PortAuthority.PortsInUse {
+		for port := range ports {
+			plan.Ports[hostname] = append(plan.Ports[hostname], port)
+		}
+		sort.Ints(plan.Ports[hostname])
+	}
+	plan.Files, err = compareDirs(tmpDir, config.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Returns the kind of a generated file
+func fileKind(path string) string {
+	base := filepath.Base(path)
+	switch {
+	case strings.HasSuffix(base, ".go") || base == "go.mod" || base == "go.sum":
+		return "go"
+	case base == "Dockerfile":
+		return "dockerfile"
+	case strings.HasSuffix(base, ".proto") || strings.HasSuffix(base, ".thrift"):
+		return "idl"
+	case strings.HasSuffix(base, ".yml") || strings.HasSuffix(base, ".yaml") || base == "inventory" || base == "ansible.cfg":
+		return "deployer"
+	}
+	return "other"
+}
+
+// Returns the files below dir relative to it except for the manifest, or nothing if dir doesn't exist
+func listFiles(dir string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !info.IsDir() {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(rel)] = true
+		}
+		return nil
+	})
+	delete(files, ManifestFile)
+	return files, err
+}
+
+// Compares the files generated into genDir with the files in outDir. Some generators write the absolute path of the
+// output directory into the files, e.g. the build playbook of Ansible, so the files are compared as if they had been
+// generated into outDir.
+func compareDirs(genDir string, outDir string) ([]PlanFile, error) {
+	absGenDir, err := filepath.Abs(genDir)
+	if err != nil {
+		return nil, err
+	}
+	absOutDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := listFiles(genDir)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := listFiles(outDir)
+	if err != nil {
+		return nil, err
+	}
+	var files []PlanFile
+	var names []string
+	for path := range generated {
+		names = append(names, path)
+		file := PlanFile{Path: path, Kind: fileKind(path), Status: FileAdded}
+		if existing[path] {
+			new, err := ioutil.ReadFile(filepath.Join(genDir, path))
+			if err != nil {
+				return nil, err
+			}
+			new = bytes.ReplaceAll(new, []byte(absGenDir), []byte(absOutDir))
+			old, err := ioutil.ReadFile(filepath.Join(outDir, path))
+			if err != nil {
+				return nil, err
+			}
+			file.Status = FileUnchanged
+			if diff := unifiedDiff("a/"+path, "b/"+path, string(old), string(new)); diff != "" {
+				file.Status = FileModified
+				file.Diff = diff
+			}
+		}
+		files = append(files, file)
+	}
+	stale, err := staleFiles(outDir, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range stale {
+		if existing[path] {
+			files = append(files, PlanFile{Path: path, Kind: fileKind(path), Status: FileStale})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// OutOfDate returns the files that differ between the plan and the output directory
+func (p *Plan) OutOfDate() []PlanFile {
+	var files []PlanFile
+	for _, file := range p.Files {
+		if file.Status != FileUnchanged {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// Markers of the file statuses in the output of Plan.Write
+var fileStatusMarkers = map[FileStatus]string{FileAdded: "+", FileModified: "~", FileUnchanged: " ", FileStale: "-"}
+
+func writeCluster(w io.Writer, cluster *generators.TopologyCluster, indent string) {
+	fmt.Fprintf(w, "%s%s (%s)\n", indent, cluster.Name, cluster.Type)
+	for _, child := range cluster.Clusters {
+		writeCluster(w, child, indent+"  ")
+	}
+	for _, node := range cluster.Nodes {
+		fmt.Fprintf(w, "%s  %s (%s)\n", indent, node.Name, node.Type)
+	}
+}
+
+// Write prints the plan for review, followed by the diffs of the modified files if diffs is true
+func (p *Plan) Write(w io.Writer, diffs bool) {
+	fmt.Fprintln(w, "Containers:")
+	for _, cluster := range p.Topology.Clusters {
+		writeCluster(w, cluster, "  ")
+	}
+	for _, node := range p.Topology.Nodes {
+		fmt.Fprintf(w, "  %s (%s)\n", node.Name, node.Type)
+	}
+
+	fmt.Fprintln(w, "Addresses:")
+	var names []string
+	for name := range p.Addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addr := p.Addresses[name]
+		fmt.Fprintf(w, "  %s: %s:%d (host %s)\n", name, addr.Address, addr.Port, addr.Hostname)
+	}
+
+	fmt.Fprintln(w, "Ports:")
+	var hostnames []string
+	for hostname := range p.Ports {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	for _, hostname := range hostnames {
+		var ports []string
+		for _, port := range p.Ports[hostname] {
+			ports = append(ports, fmt.Sprint(port))
+		}
+		fmt.Fprintf(w, "  %s: %s\n", hostname, strings.Join(ports, ", "))
+	}
+
+	counts := make(map[FileStatus]int)
+	fmt.Fprintf(w, "Files in %s:\n", p.OutDir)
+	for _, file := range p.Files {
+		counts[file.Status] += 1
+		fmt.Fprintf(w, "  %s %s (%s)\n", fileStatusMarkers[file.Status], file.Path, file.Kind)
+	}
+	fmt.Fprintf(w, "%d added, %d modified, %d unchanged, %d stale\n", counts[FileAdded], counts[FileModified], counts[FileUnchanged], counts[FileStale])
+
+	if diffs {
+		for _, file := range p.Files {
+			if file.Diff != "" {
+				fmt.Fprintln(w)
+				fmt.Fprint(w, file.Diff)
+			}
+		}
+	}
+}
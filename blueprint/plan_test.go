@@ -0,0 +1,130 @@
+package blueprint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+func TestDryRun(t *testing.T) {
+	chdirRoot(t)
+	config := leafConfig(t, "examples/Leaf/wiring/instances_all_in_one.py")
+	if _, err := Compile(context.Background(), config, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	compose := filepath.Join(config.OutDir, "docker-compose.yml")
+	content, err := os.ReadFile(compose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(compose, append(content, "# edited\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(config.OutDir, "spec", "go.mod")); err != nil {
+		t.Fatal(err)
+	}
+	// A file of a previous run and a file that was never generated
+	for _, name := range []string{"old.txt", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(config.OutDir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest, err := os.OpenFile(filepath.Join(config.OutDir, ManifestFile), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest.WriteString("old.txt\n")
+	manifest.Close()
+
+	plan, err := DryRun(context.Background(), config, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]PlanFile)
+	for _, file := range plan.Files {
+		files[file.Path] = file
+	}
+	if file := files["docker-compose.yml"]; file.Status != FileModified || file.Kind != "deployer" || !strings.Contains(file.Diff, "-# edited\n") {
+		t.Errorf("Expected docker-compose.yml to be modified, got %+v", file)
+	}
+	if file := files["spec/go.mod"]; file.Status != FileAdded {
+		t.Errorf("Expected spec/go.mod to be added, got %+v", file)
+	}
+	if file := files["old.txt"]; file.Status != FileStale {
+		t.Errorf("Expected old.txt to be stale, got %+v", file)
+	}
+	if file, ok := files["notes.txt"]; ok {
+		t.Errorf("Expected notes.txt to be left out, got %+v", file)
+	}
+	if file := files["container1/docker/Dockerfile"]; file.Kind != "dockerfile" || file.Status != FileUnchanged {
+		t.Errorf("Expected an unchanged Dockerfile, got %+v", file)
+	}
+	if _, err := os.Stat(filepath.Join(config.OutDir, "spec", "go.mod")); !os.IsNotExist(err) {
+		t.Errorf("Expected the output directory to be left untouched, got %v", err)
+	}
+	if outOfDate := plan.OutOfDate(); len(outOfDate) != 3 {
+		t.Errorf("Expected 3 out of date files, got %+v", outOfDate)
+	}
+	if addr := plan.Addresses["leafService"]; addr.Port != 8000 || len(plan.Ports["localhost"]) != 2 {
+		t.Errorf("Unexpected addresses %v and ports %v", plan.Addresses, plan.Ports)
+	}
+
+	var out strings.Builder
+	plan.Write(&out, true)
+	for _, expected := range []string{"  container1 (DockerContainer)\n", "  leafService: localhost:8000 (host localhost)\n", "  ~ docker-compose.yml (deployer)\n", "  - old.txt (other)\n", "--- a/docker-compose.yml\n"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in\n%s", expected, out.String())
+		}
+	}
+}
+
+// Generators that write the path of the output directory into the files don't make a fresh output out of date
+func TestDryRunAfterCompile(t *testing.T) {
+	chdirRoot(t)
+	config, err := parser.ParseConfig("blueprint/testdata/golden/configs/config_go_ansible.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.OutDir = t.TempDir()
+	if _, err := Compile(context.Background(), config, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := DryRun(context.Background(), config, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outOfDate := plan.OutOfDate(); len(outOfDate) != 0 {
+		t.Errorf("Expected the output to be up to date, got %+v", outOfDate)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl"
+	expected := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -9,3 +9,4 @@
+ i
+ j
+ k
++l
+\ No newline at end of file
+`
+	if diff := unifiedDiff("old", "new", old, new); diff != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, diff)
+	}
+	if diff := unifiedDiff("old", "new", old, old); diff != "" {
+		t.Errorf("Expected no diff, got\n%s", diff)
+	}
+}
@@ -0,0 +1,405 @@
+package blueprint
+
+import (
+	"context"
+	"errors"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/deploy"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// A deployer modifier and a component registered the way plugins in other modules register them
+
+type labelModifier struct {
+	*generators.NoOpDeployerModifier
+	Params []generators.Parameter
+}
+
+func (m *labelModifier) Accept(v generators.Visitor) {
+	v.VisitModifier(v, m)
+}
+
+func (m *labelModifier) GetNodes(nodeType string) []generators.Node {
+	return nil
+}
+
+func (m *labelModifier) GetName() string {
+	return "TestLabel"
+}
+
+func (m *labelModifier) GetPluginName() string {
+	return "TestLabel"
+}
+
+func (m *labelModifier) GetParams() []generators.Parameter {
+	return m.Params
+}
+
+func (m *labelModifier) ModifyDeployInfo(depInfo *deploy.DeployInfo) error {
+	for _, param := range m.Params {
+		if value, ok := param.(*generators.ValueParameter); ok && value.KeywordName == "label" {
+			depInfo.EnvVars["LABEL"] = value.Value
+		}
+	}
+	return nil
+}
+
+type kvNode struct {
+	generators.ComponentBase
+}
+
+func (n *kvNode) Accept(v generators.Visitor) {
+	v.VisitComponentNode(v, n)
+}
+
+func (n *kvNode) GetNodes(nodeType string) []generators.Node {
+	return generators.GetComponentNodes(n, nodeType)
+}
+
+func (n *kvNode) GetImage() generators.ComponentImage {
+	return generators.ComponentImage{Name: "test/kv:latest", Port: 7000, Ports: []int{7001}, Command: []string{"--memory", "64m"}}
+}
+
+func (n *kvNode) GenerateClientNode(info *parser.ImplInfo) *generators.ServiceImplInfo {
+	return &generators.ServiceImplInfo{Name: n.Name, ReceiverName: "kv", MethodBodies: make(map[string]string)}
+}
+
+func init() {
+	generators.RegisterModifier(generators.ModifierPlugin{
+		Name:        "TestLabel",
+		Description: "Sets the LABEL environment variable of a container",
+		Params:      []generators.ParamInfo{{Name: "label", Description: "Value of the label", Required: true}},
+		Generate: func(node parser.ModifierNode) generators.Modifier {
+			var params []generators.Parameter
+			for _, arg := range node.ModifierParams {
+				params = append(params, &generators.ValueParameter{KeywordName: arg.KeywordName, Value: strings.Trim(arg.Value, "'")})
+			}
+			return &labelModifier{generators.NewNoOpDeployerModifier(), params}
+		},
+		IRType: &labelModifier{},
+	})
+	generators.RegisterComponent(generators.ComponentPlugin{
+		Type:         "TestKV",
+		AbstractType: "Cache",
+		Description:  "Key-value store",
+		Generate: func(node parser.DetailNode) generators.Node {
+			return &kvNode{generators.NewComponentBase(node)}
+		},
+		IRType: &kvNode{},
+	})
+	// Two modifiers that both want to wrap the other one
+	for _, names := range [][]string{{"TestOuterA", "TestOuterB"}, {"TestOuterB", "TestOuterA"}} {
+		name := names[0]
+		generators.RegisterModifier(generators.ModifierPlugin{
+			Name:        name,
+			Description: "Conflicts with " + names[1],
+			Generate: func(node parser.ModifierNode) generators.Modifier {
+				return &labelModifier{generators.NewNoOpDeployerModifier(), nil}
+			},
+			Order: generators.ModifierOrder{Outside: []string{names[1]}},
+		})
+	}
+}
+
+const pluginWiring = `default_server_conn_opts : Modifier = WebServer(framework="default")
+default_deployer : Modifier = Deployer(framework="docker")
+label : Modifier = TestLabel(label="blue")
+server_modifiers : List[Modifier] = [default_server_conn_opts, default_deployer, label]
+kv : Cache = TestKV().WithServer(default_deployer)
+leafService : LeafService = LeafServiceImpl().WithServer(server_modifiers)
+`
+
+func TestCompilePlugins(t *testing.T) {
+	chdirRoot(t)
+	wiring := filepath.Join(t.TempDir(), "wiring.py")
+	if err := os.WriteFile(wiring, []byte(pluginWiring), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := leafConfig(t, wiring)
+	result, err := Compile(context.Background(), config, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := result.Addresses["kv"]; addr.Port != 7000 {
+		t.Errorf("Expected kv at its default port 7000, got %d", addr.Port)
+	}
+	compose, err := os.ReadFile(filepath.Join(config.OutDir, "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"image: test/kv:latest", `- "7001:7001"`, `["--memory", "64m"]`, "- LABEL=blue"} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("Expected %s in\n%s", expected, compose)
+		}
+	}
+
+	// The IR types of plugins are registered with the plugins and qualified with their package
+	data, err := generators.MarshalIR(result.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := reflect.TypeOf(kvNode{}).PkgPath()
+	for _, expected := range []string{`"@type": "` + pkg + `.labelModifier"`, `"@type": "` + pkg + `.kvNode"`, `"@type": "WebServerModifier"`} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %s in the IR document", expected)
+		}
+	}
+	if _, err := generators.UnmarshalIR(data); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegisterIRTypeTwice(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "labelModifier is registered twice") {
+			t.Errorf("Expected a panic for the second registration, got %v", r)
+		}
+	}()
+	generators.RegisterIRType(&labelModifier{})
+}
+
+func TestCompileChecksPluginParams(t *testing.T) {
+	chdirRoot(t)
+	wiring := filepath.Join(t.TempDir(), "wiring.py")
+	content := strings.Replace(pluginWiring, `TestLabel(label="blue")`, `TestLabel(colour="blue")`, 1)
+	if err := os.WriteFile(wiring, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Compile(context.Background(), leafConfig(t, wiring), Options{})
+	var diagErr *DiagnosticsError
+	if !errors.As(err, &diagErr) {
+		t.Fatalf("Expected diagnostics, got %v", err)
+	}
+	var messages []string
+	for _, diag := range diagErr.Diagnostics.Sorted() {
+		messages = append(messages, diag.Severity.String()+": "+diag.Message)
+	}
+	expected := []string{
+		"warning: Modifier TestLabel does not accept parameter colour (accepted parameters: label)",
+		"error: Modifier TestLabel requires parameter label: Value of the label",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(messages, "\n"))
+	}
+}
+
+func TestCompileValidatesModifierValues(t *testing.T) {
+	chdirRoot(t)
+	wiring := filepath.Join(t.TempDir(), "wiring.py")
+	content := `default_server_conn_opts : Modifier = WebServer(framework="default")
+default_deployer : Modifier = Deployer(framework="docker")
+retry_opts : Modifier = Retry(max_retries=3, backoff="linear")
+server_modifiers : List[Modifier] = [default_server_conn_opts, default_deployer]
+client_modifiers : List[Modifier] = [retry_opts]
+leafService : LeafService = LeafServiceImpl().WithServer(server_modifiers).WithClient(client_modifiers)
+`
+	if err := os.WriteFile(wiring, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Compile(context.Background(), leafConfig(t, wiring), Options{})
+	var diagErr *DiagnosticsError
+	if !errors.As(err, &diagErr) {
+		t.Fatalf("Expected diagnostics, got %v", err)
+	}
+	diags := diagErr.Diagnostics.Sorted()
+	expected := "Modifier Retry: backoff: unknown policy linear, expected one of none, constant, exponential, jitter"
+	if len(diags) != 1 || diags[0].Message != expected || diags[0].Pos.Line != 3 {
+		t.Errorf("Expected %q on line 3, got %v", expected, diags)
+	}
+}
+
+func TestCompileChecksModifierFrameworks(t *testing.T) {
+	chdirRoot(t)
+	for name, modifier := range map[string]string{"RateLimiter": "RateLimiter(rate=100)", "ConcurrencyLimiter": "ConcurrencyLimiter(limit=50)"} {
+		wiring := filepath.Join(t.TempDir(), "wiring.py")
+		content := `default_server_conn_opts : Modifier = RPCServer(framework="aiothrift")
+default_deployer : Modifier = Deployer(framework="docker")
+limiter_opts : Modifier = ` + modifier + `
+server_modifiers : List[Modifier] = [limiter_opts, default_server_conn_opts, default_deployer]
+leafService : LeafService = LeafServiceImpl().WithServer(server_modifiers)
+`
+		if err := os.WriteFile(wiring, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Compile(context.Background(), leafConfig(t, wiring), Options{})
+		var diagErr *DiagnosticsError
+		if !errors.As(err, &diagErr) {
+			t.Fatalf("%s: expected diagnostics, got %v", name, err)
+		}
+		diags := diagErr.Diagnostics.Sorted()
+		expected := "Modifier " + name + " doesn't work with the framework aiothrift, only with grpc, default"
+		if len(diags) != 1 || diags[0].Message != expected || diags[0].Pos.Line != 3 {
+			t.Errorf("Expected %q on line 3, got %v", expected, diags)
+		}
+	}
+}
+
+func TestOrderModifiers(t *testing.T) {
+	diags := parser.NewDiagnostics()
+	registry := generators.InitModifierRegistry(log.New(ioutil.Discard, "", 0), diags)
+	var modifiers []generators.Modifier
+	for _, node := range []parser.ModifierNode{
+		{ModifierType: "RPCServer", ModifierParams: []parser.ArgumentNode{{KeywordName: "framework", Value: "grpc"}}},
+		{ModifierType: "CircuitBreaker", ModifierParams: []parser.ArgumentNode{{KeywordName: "interval", Value: "10s"}}},
+		{ModifierType: "HealthChecker"},
+		{ModifierType: "Retry", ModifierParams: []parser.ArgumentNode{{KeywordName: "max_retries", Value: "3"}}},
+		{ModifierType: "ClientPool", ModifierParams: []parser.ArgumentNode{{KeywordName: "max_clients", Value: "10"}}},
+	} {
+		modifiers = append(modifiers, registry.GetModifier(node))
+	}
+	var names []string
+	for _, modifier := range registry.OrderModifiers(modifiers) {
+		names = append(names, modifier.GetName())
+	}
+	expected := []string{"ClientPool", "HealthCheck", "RetryModifier", "CircuitBreakerModifier", "RPCServer"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+	if diags.HasErrors() {
+		t.Errorf("Expected no errors, got %v", diags.Items)
+	}
+}
+
+func TestTimeoutWarnsAboutMethodsWithoutContext(t *testing.T) {
+	diags := parser.NewDiagnostics()
+	registry := generators.InitModifierRegistry(log.New(ioutil.Discard, "", 0), diags)
+	node := parser.ModifierNode{ModifierType: "Timeout", ModifierParams: []parser.ArgumentNode{{KeywordName: "timeout", Value: "1s"}}}
+	modifier := registry.GetModifier(node)
+	methods := map[string]parser.FuncInfo{
+		"Leaf":  {Name: "Leaf", Args: []parser.ArgInfo{parser.GetContextArg("ctx"), parser.GetBasicArg("a", "int64")}, Return: []parser.ArgInfo{parser.GetBasicArg("", "int64"), parser.GetErrorArg("")}, Public: true},
+		"Count": {Name: "Count", Args: []parser.ArgInfo{parser.GetBasicArg("tm_ret_0", "int64")}, Return: []parser.ArgInfo{parser.GetBasicArg("", "int64"), parser.GetErrorArg("")}, Public: true},
+	}
+	for i := 0; i < 2; i++ {
+		// Every caller of a service modifies its client
+		client, err := modifier.ModifyClient(&generators.ServiceImplInfo{BaseName: "LeafService", Methods: methods})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body := client.MethodBodies["Count"]; !strings.Contains(body, "var tm_ret_0_ int64") || !strings.Contains(body, "tm.client.Count(tm_ret_0)") {
+			t.Errorf("Expected the results to be named after the receiver and not after the arguments, got\n%s", body)
+		}
+		registry.ReportWarnings(modifier)
+	}
+	expected := "Timeout: the methods Count of LeafService have no context.Context argument, their calls can't be cancelled"
+	if len(diags.Items) != 1 || diags.Items[0].Severity != parser.SeverityWarning || diags.Items[0].Message != expected {
+		t.Errorf("Expected the warning %q once, got %v", expected, diags.Items)
+	}
+}
+
+func TestWrapperModifiersRequireErrorResult(t *testing.T) {
+	pos := token.Position{Filename: "leaf.go", Line: 12, Column: 2}
+	methods := []parser.FuncInfo{
+		{Name: "Ping", Args: []parser.ArgInfo{parser.GetContextArg("ctx")}, Public: true, Pos: pos},
+		{Name: "Count", Args: []parser.ArgInfo{parser.GetContextArg("ctx")}, Return: []parser.ArgInfo{parser.GetBasicArg("", "int64")}, Public: true, Pos: pos},
+	}
+	modifiers := []parser.ModifierNode{
+		{ModifierType: "Retry", ModifierParams: []parser.ArgumentNode{{KeywordName: "max_retries", Value: "3"}}},
+		{ModifierType: "RateLimiter", ModifierParams: []parser.ArgumentNode{{KeywordName: "rate", Value: "100"}}},
+	}
+	for _, method := range methods {
+		for _, node := range modifiers {
+			diags := parser.NewDiagnostics()
+			registry := generators.InitModifierRegistry(log.New(ioutil.Discard, "", 0), diags)
+			modifier := registry.GetModifier(node)
+			info := &generators.ServiceImplInfo{BaseName: "LeafService", Methods: map[string]parser.FuncInfo{method.Name: method}}
+			var err error
+			if node.ModifierType == "RateLimiter" {
+				_, err = modifier.ModifyServer(info)
+			} else {
+				_, err = modifier.ModifyClient(info)
+			}
+			expected := node.ModifierType + " can't wrap LeafService." + method.Name + ", which doesn't return an error as its last result"
+			if err == nil || err.Error() != expected {
+				t.Errorf("Expected the error %q, got %v", expected, err)
+				continue
+			}
+			func() {
+				defer parser.RecoverAbort(&err)
+				registry.Abort(modifier, err)
+			}()
+			if len(diags.Items) != 1 || diags.Items[0].Pos != pos {
+				t.Errorf("Expected the error to be reported at the declaration of %s, got %v", method.Name, diags.Items)
+			}
+		}
+	}
+}
+
+func TestClientPoolMethodWithoutResults(t *testing.T) {
+	diags := parser.NewDiagnostics()
+	registry := generators.InitModifierRegistry(log.New(ioutil.Discard, "", 0), diags)
+	node := parser.ModifierNode{ModifierType: "ClientPool", ModifierParams: []parser.ArgumentNode{{KeywordName: "max_clients", Value: "10"}, {KeywordName: "max_wait", Value: "50ms"}}}
+	modifier := registry.GetModifier(node)
+	methods := map[string]parser.FuncInfo{
+		"Notify": {Name: "Notify", Args: []parser.ArgInfo{parser.GetContextArg("ctx")}, Public: true},
+	}
+	client, err := modifier.ModifyClient(&generators.ServiceImplInfo{BaseName: "LeafService", Methods: methods})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "client := cp.pool.Pop()\ndefer cp.pool.Push(client)\nreturn client.Notify(ctx)"
+	if body := client.MethodBodies["Notify"]; body != expected {
+		t.Errorf("Expected a method without results to wait for a client, got\n%s", body)
+	}
+}
+
+func TestCompileChecksModifierOrder(t *testing.T) {
+	chdirRoot(t)
+	for _, test := range []struct {
+		modifiers string
+		expected  []string
+	}{
+		{`first : Modifier = CircuitBreaker(interval="10s")
+second : Modifier = Retry(max_retries=3)
+`, []string{
+			"Modifier Retry is listed after CircuitBreaker and would be closer to the network, but CircuitBreaker has to be closer to the network than Retry",
+		}},
+		{`first : Modifier = TestOuterA()
+second : Modifier = TestOuterB()
+`, []string{
+			"conflicting modifier order: TestOuterA inside TestOuterB inside TestOuterA",
+			"Modifier TestOuterB is listed after TestOuterA and would be closer to the network, but TestOuterA has to be closer to the network than TestOuterB",
+		}},
+	} {
+		wiring := filepath.Join(t.TempDir(), "wiring.py")
+		content := test.modifiers + "default_deployer : Modifier = Deployer(framework=\"docker\")\n" +
+			"client_modifiers : List[Modifier] = [first, second]\n" +
+			"leafService : LeafService = LeafServiceImpl().WithServer(default_deployer).WithClient(client_modifiers)\n"
+		if err := os.WriteFile(wiring, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Compile(context.Background(), leafConfig(t, wiring), Options{})
+		var diagErr *DiagnosticsError
+		if !errors.As(err, &diagErr) {
+			t.Fatalf("Expected diagnostics, got %v", err)
+		}
+		var messages []string
+		for _, diag := range diagErr.Diagnostics.Sorted() {
+			messages = append(messages, diag.Message)
+		}
+		if !reflect.DeepEqual(messages, test.expected) {
+			t.Errorf("Expected %q, got %q", test.expected, messages)
+		}
+	}
+}
+
+func TestPluginsAreDescribed(t *testing.T) {
+	for _, plugin := range generators.ModifierPlugins() {
+		if plugin.Description == "" {
+			t.Errorf("Modifier %s has no description", plugin.Name)
+		}
+	}
+	for _, plugin := range generators.ComponentPlugins() {
+		if plugin.Description == "" {
+			t.Errorf("Component %s has no description", plugin.Type)
+		}
+	}
+}
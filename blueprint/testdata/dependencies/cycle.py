@@ -0,0 +1,7 @@
+default_server_conn_opts : Modifier = WebServer(framework="default")
+default_deployer : Modifier = Deployer(framework="docker")
+server_modifiers : List[Modifier] = [default_server_conn_opts, default_deployer]
+
+frontService : NonLeafService = NonLeafServiceImpl(leafService=backService).WithServer(server_modifiers)
+
+backService : NonLeafService = NonLeafServiceImpl(leafService=frontService).WithServer(server_modifiers)
@@ -0,0 +1,63 @@
+// Blueprint: auto-generated by XTrace plugin
+package proc1
+
+import (
+	"context"
+	"fmt"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/components"
+	"github.com/tracingplane/tracingplane-go/tracingplane"
+	"spec/services"
+)
+
+type LeafServiceImplXTracer struct {
+	service *LeafServiceImplTracer
+	tracer  components.XTracer
+}
+
+func NewLeafServiceImplXTracer(service *LeafServiceImplTracer, tracer components.XTracer) *LeafServiceImplXTracer {
+	return &LeafServiceImplXTracer{service: service, tracer: tracer}
+}
+
+func (t *LeafServiceImplXTracer) Leaf(ctx context.Context, a int64, jaegerTracer_trace_ctx string, xtracer_baggage string) (int64, string, error) {
+	if xtracer_baggage != "" {
+		remote_baggage, _ := tracingplane.DecodeBase64(xtracer_baggage)
+		ctx = t.tracer.Set(ctx, remote_baggage)
+	}
+	if !t.tracer.IsTracing(ctx) {
+		ctx = t.tracer.StartTask(ctx, "Leaf")
+	}
+	ctx = t.tracer.Log(ctx, "Leaf start")
+	ret0, ret1 := t.service.Leaf(ctx, a, jaegerTracer_trace_ctx)
+	ret_baggage := t.tracer.Get(ctx)
+	ret_baggage_str := tracingplane.EncodeBase64(ret_baggage)
+	if ret1 != nil {
+		ctx = t.tracer.LogWithTags(ctx, ret1.Error(), "Error")
+		ctx = t.tracer.Log(ctx, "ctx:"+fmt.Sprintf("%v", ctx))
+		ctx = t.tracer.Log(ctx, "a:"+fmt.Sprintf("%v", a))
+		ctx = t.tracer.Log(ctx, "jaegerTracer_trace_ctx:"+fmt.Sprintf("%v", jaegerTracer_trace_ctx))
+	}
+	ctx = t.tracer.Log(ctx, "Leaf end")
+	return ret0, ret_baggage_str, ret1
+}
+
+func (t *LeafServiceImplXTracer) Object(ctx context.Context, obj services.LeafObject, jaegerTracer_trace_ctx string, xtracer_baggage string) (services.LeafObject, string, error) {
+	if xtracer_baggage != "" {
+		remote_baggage, _ := tracingplane.DecodeBase64(xtracer_baggage)
+		ctx = t.tracer.Set(ctx, remote_baggage)
+	}
+	if !t.tracer.IsTracing(ctx) {
+		ctx = t.tracer.StartTask(ctx, "Object")
+	}
+	ctx = t.tracer.Log(ctx, "Object start")
+	ret0, ret1 := t.service.Object(ctx, obj, jaegerTracer_trace_ctx)
+	ret_baggage := t.tracer.Get(ctx)
+	ret_baggage_str := tracingplane.EncodeBase64(ret_baggage)
+	if ret1 != nil {
+		ctx = t.tracer.LogWithTags(ctx, ret1.Error(), "Error")
+		ctx = t.tracer.Log(ctx, "ctx:"+fmt.Sprintf("%v", ctx))
+		ctx = t.tracer.Log(ctx, "obj:"+fmt.Sprintf("%v", obj))
+		ctx = t.tracer.Log(ctx, "jaegerTracer_trace_ctx:"+fmt.Sprintf("%v", jaegerTracer_trace_ctx))
+	}
+	ctx = t.tracer.Log(ctx, "Object end")
+	return ret0, ret_baggage_str, ret1
+}
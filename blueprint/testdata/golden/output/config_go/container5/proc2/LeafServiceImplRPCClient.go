@@ -0,0 +1,93 @@
+// Blueprint: auto-generated by RPCgrpc plugin
+package proc2
+
+import (
+	"context"
+	"errors"
+	"gen-go/leaf"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/jinzhu/copier"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"os"
+	"spec/services"
+	"time"
+)
+
+type LeafServiceImplRPCClient struct {
+	client  leaf.LeafServiceImplClient
+	Timeout time.Duration
+}
+
+func NewLeafServiceImplRPCClient() (*LeafServiceImplRPCClient, error) {
+	addr := os.Getenv("leafService_ADDRESS")
+	port := os.Getenv("leafService_PORT")
+	if addr == "" || port == "" {
+		return nil, errors.New("Address or port were not set")
+	}
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	duration, err := time.ParseDuration("1s")
+	if err == nil {
+		opts = append(opts, grpc.WithTimeout(duration))
+	}
+	conn, err := grpc.Dial(addr+":"+port, opts...)
+	if err != nil {
+		return nil, err
+	}
+	client := leaf.NewLeafServiceImplClient(conn)
+	return &LeafServiceImplRPCClient{client: client, Timeout: duration}, nil
+
+}
+
+func (rpcclient *LeafServiceImplRPCClient) Leaf(ctx context.Context, a int64, jaegerTracer_trace_ctx string, xtracer_baggage string) (int64, string, error) {
+	request := &leaf.LeafServiceImpl_LeafRequest{}
+	ctx, cancel := context.WithTimeout(ctx, rpcclient.Timeout)
+	defer cancel()
+	request.A = a
+	request.JaegerTracerTraceCtx = jaegerTracer_trace_ctx
+	request.XtracerBaggage = xtracer_baggage
+	response, err := rpcclient.client.Leaf(ctx, request)
+	var ret0 int64
+	var ret1 string
+	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			err = &stdlib.ResourceExhaustedError{Reason: status.Convert(err).Message()}
+		}
+		return ret0, ret1, err
+	}
+	if ctx.Err() != nil {
+		return ret0, ret1, err
+	}
+	ret0 = response.RetVal0
+	ret1 = response.RetVal1
+	return ret0, ret1, err
+}
+
+func (rpcclient *LeafServiceImplRPCClient) Object(ctx context.Context, obj services.LeafObject, jaegerTracer_trace_ctx string, xtracer_baggage string) (services.LeafObject, string, error) {
+	request := &leaf.LeafServiceImpl_ObjectRequest{}
+	ctx, cancel := context.WithTimeout(ctx, rpcclient.Timeout)
+	defer cancel()
+	arg1 := &leaf.LeafObject{}
+	copier.Copy(arg1, &obj)
+	request.Obj = arg1
+	request.JaegerTracerTraceCtx = jaegerTracer_trace_ctx
+	request.XtracerBaggage = xtracer_baggage
+	response, err := rpcclient.client.Object(ctx, request)
+	ret0 := services.LeafObject{}
+	var ret1 string
+	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			err = &stdlib.ResourceExhaustedError{Reason: status.Convert(err).Message()}
+		}
+		return ret0, ret1, err
+	}
+	if ctx.Err() != nil {
+		return ret0, ret1, err
+	}
+	copier.Copy(&ret0, response.RetVal0)
+	ret1 = response.RetVal1
+	return ret0, ret1, err
+}
@@ -0,0 +1,51 @@
+// Blueprint: auto-generated by XTrace plugin
+package proc2
+
+import (
+	"context"
+	"fmt"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/components"
+	"github.com/tracingplane/tracingplane-go/tracingplane"
+	"spec/services"
+)
+
+type LeafServiceImplXTracerClient struct {
+	client *LeafServiceImplRetrier
+	tracer components.XTracer
+}
+
+func NewLeafServiceImplXTracerClient(client *LeafServiceImplRetrier, tracer components.XTracer) *LeafServiceImplXTracerClient {
+	return &LeafServiceImplXTracerClient{client: client, tracer: tracer}
+}
+
+func (t *LeafServiceImplXTracerClient) Leaf(ctx context.Context, a int64, jaegerTracer_trace_ctx string) (int64, error) {
+	ctx = t.tracer.Log(ctx, "Leaf")
+	baggage := t.tracer.Get(ctx)
+	baggage_str := tracingplane.EncodeBase64(baggage)
+	ret0, ret_baggage_str, ret1 := t.client.Leaf(ctx, a, jaegerTracer_trace_ctx, baggage_str)
+	ret_baggage, _ := tracingplane.DecodeBase64(ret_baggage_str)
+	ctx = t.tracer.Merge(ctx, ret_baggage)
+	if ret1 != nil {
+		ctx = t.tracer.LogWithTags(ctx, ret1.Error(), "Error")
+		ctx = t.tracer.Log(ctx, "ctx:"+fmt.Sprintf("%v", ctx))
+		ctx = t.tracer.Log(ctx, "a:"+fmt.Sprintf("%v", a))
+		ctx = t.tracer.Log(ctx, "jaegerTracer_trace_ctx:"+fmt.Sprintf("%v", jaegerTracer_trace_ctx))
+	}
+	return ret0, ret1
+}
+
+func (t *LeafServiceImplXTracerClient) Object(ctx context.Context, obj services.LeafObject, jaegerTracer_trace_ctx string) (services.LeafObject, error) {
+	ctx = t.tracer.Log(ctx, "Object")
+	baggage := t.tracer.Get(ctx)
+	baggage_str := tracingplane.EncodeBase64(baggage)
+	ret0, ret_baggage_str, ret1 := t.client.Object(ctx, obj, jaegerTracer_trace_ctx, baggage_str)
+	ret_baggage, _ := tracingplane.DecodeBase64(ret_baggage_str)
+	ctx = t.tracer.Merge(ctx, ret_baggage)
+	if ret1 != nil {
+		ctx = t.tracer.LogWithTags(ctx, ret1.Error(), "Error")
+		ctx = t.tracer.Log(ctx, "ctx:"+fmt.Sprintf("%v", ctx))
+		ctx = t.tracer.Log(ctx, "obj:"+fmt.Sprintf("%v", obj))
+		ctx = t.tracer.Log(ctx, "jaegerTracer_trace_ctx:"+fmt.Sprintf("%v", jaegerTracer_trace_ctx))
+	}
+	return ret0, ret1
+}
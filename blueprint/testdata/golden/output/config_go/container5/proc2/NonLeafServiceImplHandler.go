@@ -0,0 +1,55 @@
+// Blueprint: auto-generated by RPCgrpc plugin
+package proc2
+
+import (
+	"context"
+	"errors"
+	"gen-go/leaf"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"os"
+)
+
+type NonLeafServiceImplHandler struct {
+	service *NonLeafServiceImplXTracer
+	leaf.UnimplementedNonLeafServiceImplServer
+}
+
+func NewNonLeafServiceImplHandler(old_handler *NonLeafServiceImplXTracer, framework string, timeout string) *NonLeafServiceImplHandler {
+	handler := &NonLeafServiceImplHandler{service: old_handler}
+	return handler
+}
+
+func (rpchandler *NonLeafServiceImplHandler) Leaf(ctx context.Context, request *leaf.NonLeafServiceImpl_LeafRequest) (*leaf.NonLeafServiceImpl_LeafResponse, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		ctx = stdlib.WithCallerAddr(ctx, p.Addr.String())
+	}
+	ret0, ret1, ret2 := rpchandler.service.Leaf(ctx, request.A, request.JaegerTracerTraceCtx, request.XtracerBaggage)
+	if errors.Is(ret2, stdlib.ErrResourceExhausted) {
+		return nil, status.Error(codes.ResourceExhausted, ret2.Error())
+	}
+	response := &leaf.NonLeafServiceImpl_LeafResponse{}
+	response.RetVal0 = ret0
+	response.RetVal1 = ret1
+	return response, ret2
+}
+
+func (rpchandler *NonLeafServiceImplHandler) Run() error {
+	addr := os.Getenv("nonleafService_ADDRESS")
+	port := os.Getenv("nonleafService_PORT")
+	if addr == "" || port == "" {
+		return errors.New("Address or Port were not set")
+	}
+	lis, err := net.Listen("tcp", addr+":"+port)
+	if err != nil {
+		return err
+	}
+	grpcServer := grpc.NewServer()
+	leaf.RegisterNonLeafServiceImplServer(grpcServer, rpchandler)
+	return grpcServer.Serve(lis)
+
+}
@@ -0,0 +1,61 @@
+// Blueprint: auto-generated by XTrace plugin
+package proc2
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/choices/tracer"
+	"github.com/tracingplane/tracingplane-go/tracingplane"
+	"os"
+)
+
+type xtracer struct {
+	internal *tracer.XTracerImpl
+}
+
+func Newxtracer() *xtracer {
+	addr := os.Getenv("xtracer_ADDRESS")
+	port := os.Getenv("xtracer_PORT")
+	int_tracer := tracer.NewXTracerImpl(addr, port)
+	return &xtracer{internal: int_tracer}
+
+}
+
+func (t *xtracer) Get(ctx context.Context) tracingplane.BaggageContext {
+	return t.internal.Get(ctx)
+
+}
+
+func (t *xtracer) IsTracing(ctx context.Context) bool {
+	return t.internal.IsTracing(ctx)
+
+}
+
+func (t *xtracer) Log(ctx context.Context, msg string) context.Context {
+	return t.internal.Log(ctx, msg)
+
+}
+
+func (t *xtracer) LogWithTags(ctx context.Context, msg string, tags ...string) context.Context {
+	return t.internal.LogWithTags(ctx, msg, tags...)
+
+}
+
+func (t *xtracer) Merge(ctx context.Context, other tracingplane.BaggageContext) context.Context {
+	return t.internal.Merge(ctx, other)
+
+}
+
+func (t *xtracer) Set(ctx context.Context, baggage tracingplane.BaggageContext) context.Context {
+	return t.internal.Set(ctx, baggage)
+
+}
+
+func (t *xtracer) StartTask(ctx context.Context, tags ...string) context.Context {
+	return t.internal.StartTask(ctx, tags...)
+
+}
+
+func (t *xtracer) StopTask(ctx context.Context) context.Context {
+	return t.internal.StopTask(ctx)
+
+}
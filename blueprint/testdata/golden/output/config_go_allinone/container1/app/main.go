@@ -0,0 +1,35 @@
+// Blueprint: auto-generated by Blueprint core
+package main
+
+import "container1/process"
+import "sync"
+import "log"
+
+func main() {
+	var leafService *process.LeafServiceImpl
+	var nonleafService *process.NonLeafServiceImplHandler
+	leafService = process.GetleafService()
+	nonleafService = process.GetnonleafService(leafService)
+	c := make(chan error, 1)
+	wg_done := make(chan bool)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := process.RunnonleafService(nonleafService)
+		if err != nil {
+			c <- err
+		}
+	}()
+	go func() {
+		wg.Wait()
+		wg_done <- true
+	}()
+	select {
+	case err := <-c:
+		log.Fatal(err)
+	case <-wg_done:
+		log.Println("Success")
+	}
+
+}
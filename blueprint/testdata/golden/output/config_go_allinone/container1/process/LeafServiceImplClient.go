@@ -0,0 +1,23 @@
+// Blueprint: auto-generated by Blueprint Core plugin
+package process
+
+import (
+	"context"
+	"spec/services"
+)
+
+type LeafServiceImplClient struct {
+	client *LeafServiceImpl
+}
+
+func NewLeafServiceImplClient(client *LeafServiceImpl) *LeafServiceImplClient {
+	return &LeafServiceImplClient{client: client}
+}
+
+func (this *LeafServiceImplClient) Leaf(ctx context.Context, a int64) (int64, error) {
+	return this.client.Leaf(ctx, a)
+}
+
+func (this *LeafServiceImplClient) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	return this.client.Object(ctx, obj)
+}
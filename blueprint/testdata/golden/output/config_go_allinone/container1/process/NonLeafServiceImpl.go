@@ -0,0 +1,19 @@
+// Blueprint: auto-generated by Blueprint Core plugin
+package process
+
+import (
+	"context"
+	"spec/services"
+)
+
+type NonLeafServiceImpl struct {
+	service *services.NonLeafServiceImpl
+}
+
+func NewNonLeafServiceImpl(handler *services.NonLeafServiceImpl) *NonLeafServiceImpl {
+	return &NonLeafServiceImpl{service: handler}
+}
+
+func (this *NonLeafServiceImpl) Leaf(ctx context.Context, a int64) (int64, error) {
+	return this.service.Leaf(ctx, a)
+}
@@ -0,0 +1,10 @@
+// Blueprint: auto-generated by Blueprint Core
+package process
+
+import "spec/services"
+
+func GetleafService() *LeafServiceImpl {
+	spec_handler := services.NewLeafServiceImpl()
+	leafserviceimpl := NewLeafServiceImpl(spec_handler)
+	return leafserviceimpl
+}
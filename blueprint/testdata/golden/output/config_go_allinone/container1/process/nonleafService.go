@@ -0,0 +1,16 @@
+// Blueprint: auto-generated by Blueprint Core
+package process
+
+import "spec/services"
+
+func GetnonleafService(leafService *LeafServiceImpl) *NonLeafServiceImplHandler {
+	leafservice_leafserviceimplclient := NewLeafServiceImplClient(leafService)
+	spec_handler := services.NewNonLeafServiceImpl(leafservice_leafserviceimplclient)
+	nonleafserviceimpl := NewNonLeafServiceImpl(spec_handler)
+	nonleafserviceimplhandler := NewNonLeafServiceImplHandler(nonleafserviceimpl, "default")
+	return nonleafserviceimplhandler
+}
+
+func RunnonleafService(service *NonLeafServiceImplHandler) error {
+	return service.Run()
+}
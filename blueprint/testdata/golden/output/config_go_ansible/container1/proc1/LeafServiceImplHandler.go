@@ -0,0 +1,96 @@
+// Blueprint: auto-generated by WebServer plugin
+package proc1
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/gorilla/mux"
+	"log"
+	"net/http"
+	"os"
+	"spec/services"
+)
+
+type LeafServiceImplHandler struct {
+	service *LeafServiceImpl
+	url     string
+}
+type LeafServiceImpl_Leaf_WebResponse struct {
+	Ret0 int64
+}
+type LeafServiceImpl_Object_WebResponse struct {
+	Ret0 services.LeafObject
+}
+
+func NewLeafServiceImplHandler(old_handler *LeafServiceImpl, framework string) *LeafServiceImplHandler {
+	handler := &LeafServiceImplHandler{service: old_handler, url: ""}
+	return handler
+}
+
+func (webhandler *LeafServiceImplHandler) Leaf(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx, cancel := stdlib.WithEncodedDeadline(stdlib.WithCallerAddr(r.Context(), r.RemoteAddr), r.Header.Get(stdlib.DeadlineHeader))
+	defer cancel()
+	var a int64
+	arg1 := r.FormValue("a")
+	if arg1 != "" {
+		err = json.Unmarshal([]byte(arg1), &a)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			log.Println(err)
+			log.Println(arg1, "a")
+			return
+		}
+	}
+	ret0, ret1 := webhandler.service.Leaf(ctx, a)
+	if ret1 != nil {
+		stdlib.WriteHTTPError(w, ret1)
+		return
+	}
+	response := LeafServiceImpl_Leaf_WebResponse{}
+	response.Ret0 = ret0
+	json.NewEncoder(w).Encode(response)
+
+}
+
+func (webhandler *LeafServiceImplHandler) Object(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx, cancel := stdlib.WithEncodedDeadline(stdlib.WithCallerAddr(r.Context(), r.RemoteAddr), r.Header.Get(stdlib.DeadlineHeader))
+	defer cancel()
+	var obj services.LeafObject
+	arg1 := r.FormValue("obj")
+	if arg1 != "" {
+		err = json.Unmarshal([]byte(arg1), &obj)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			log.Println(err)
+			log.Println(arg1, "obj")
+			return
+		}
+	}
+	ret0, ret1 := webhandler.service.Object(ctx, obj)
+	if ret1 != nil {
+		stdlib.WriteHTTPError(w, ret1)
+		return
+	}
+	response := LeafServiceImpl_Object_WebResponse{}
+	response.Ret0 = ret0
+	json.NewEncoder(w).Encode(response)
+
+}
+
+func (webhandler *LeafServiceImplHandler) Run() error {
+	addr := os.Getenv("leafService_ADDRESS")
+	port := os.Getenv("leafService_PORT")
+	if addr == "" || port == "" {
+		return errors.New("Address or Port were not set")
+	}
+	url := "http://" + addr + ":" + port
+	router := mux.NewRouter()
+	webhandler.url = url
+	router.Path("/Leaf").HandlerFunc(webhandler.Leaf)
+	router.Path("/Object").HandlerFunc(webhandler.Object)
+	log.Println("Launching Server")
+	return http.ListenAndServe(addr+":"+port, router)
+}
@@ -0,0 +1,15 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc1
+
+import "spec/services"
+
+func GetleafService() *LeafServiceImplHandler {
+	spec_handler := services.NewLeafServiceImpl()
+	leafserviceimpl := NewLeafServiceImpl(spec_handler)
+	leafserviceimplhandler := NewLeafServiceImplHandler(leafserviceimpl, "default")
+	return leafserviceimplhandler
+}
+
+func RunleafService(service *LeafServiceImplHandler) error {
+	return service.Run()
+}
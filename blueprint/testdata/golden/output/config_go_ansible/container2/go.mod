@@ -0,0 +1,10 @@
+module container2
+
+go 1.18
+
+require (
+	github.com/alifarahbakhsh/forked-legacy-blueprint-compiler v0.1.1
+	spec v1.0.0
+)
+
+replace spec => ../spec
@@ -0,0 +1,23 @@
+// Blueprint: auto-generated by Blueprint Core plugin
+package proc2
+
+import (
+	"context"
+	"spec/services"
+)
+
+type LeafServiceImplClient struct {
+	client *LeafServiceImplWebClient
+}
+
+func NewLeafServiceImplClient(client *LeafServiceImplWebClient) *LeafServiceImplClient {
+	return &LeafServiceImplClient{client: client}
+}
+
+func (this *LeafServiceImplClient) Leaf(ctx context.Context, a int64) (int64, error) {
+	return this.client.Leaf(ctx, a)
+}
+
+func (this *LeafServiceImplClient) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	return this.client.Object(ctx, obj)
+}
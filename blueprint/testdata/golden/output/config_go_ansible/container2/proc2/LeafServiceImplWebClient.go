@@ -0,0 +1,88 @@
+// Blueprint: auto-generated by WebServer plugin
+package proc2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/url"
+	"os"
+	"spec/services"
+	"strings"
+)
+
+type LeafServiceImplWebClient struct {
+	url string
+}
+type LeafServiceImpl_Leaf_WebResponse struct {
+	Ret0 int64
+}
+type LeafServiceImpl_Object_WebResponse struct {
+	Ret0 services.LeafObject
+}
+
+func NewLeafServiceImplWebClient() (*LeafServiceImplWebClient, error) {
+	addr := os.Getenv("leafService_ADDRESS")
+	port := os.Getenv("leafService_PORT")
+	if addr == "" || port == "" {
+		return nil, errors.New("Address or port were not set")
+	}
+	url := "http://" + addr + ":" + port
+	return &LeafServiceImplWebClient{url: url}, nil
+
+}
+
+func (webclient *LeafServiceImplWebClient) Leaf(ctx context.Context, a int64) (int64, error) {
+	values := url.Values{}
+	arg1, _ := json.Marshal(a)
+	values.Set("a", string(arg1))
+	var response LeafServiceImpl_Leaf_WebResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webclient.url+"/Leaf", strings.NewReader(values.Encode()))
+	if err != nil {
+		return response.Ret0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if timeout, ok := stdlib.EncodeDeadline(ctx); ok {
+		req.Header.Set(stdlib.DeadlineHeader, timeout)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return response.Ret0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return response.Ret0, stdlib.ReadHTTPError(resp)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	return response.Ret0, err
+
+}
+
+func (webclient *LeafServiceImplWebClient) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	values := url.Values{}
+	arg1, _ := json.Marshal(obj)
+	values.Set("obj", string(arg1))
+	var response LeafServiceImpl_Object_WebResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webclient.url+"/Object", strings.NewReader(values.Encode()))
+	if err != nil {
+		return response.Ret0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if timeout, ok := stdlib.EncodeDeadline(ctx); ok {
+		req.Header.Set(stdlib.DeadlineHeader, timeout)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return response.Ret0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return response.Ret0, stdlib.ReadHTTPError(resp)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	return response.Ret0, err
+
+}
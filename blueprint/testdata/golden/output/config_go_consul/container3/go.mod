@@ -0,0 +1,13 @@
+module container3
+
+go 1.18
+
+require (
+	github.com/alifarahbakhsh/forked-legacy-blueprint-compiler v0.1.1
+	spec v1.0.0
+	gen-go/leaf v1.0.0
+)
+
+replace gen-go/leaf v1.0.0 => ../gen-go/leaf
+
+replace spec => ../spec
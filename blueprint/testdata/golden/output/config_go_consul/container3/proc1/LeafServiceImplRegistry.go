@@ -0,0 +1,35 @@
+// Blueprint: auto-generated by Consul plugin
+package proc1
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/components"
+	"os"
+	"spec/services"
+	"strconv"
+)
+
+type LeafServiceImplRegistry struct {
+	service      *LeafServiceImplTracer
+	reg          components.Registry
+	service_name string
+	service_id   string
+}
+
+func NewLeafServiceImplRegistry(service *LeafServiceImplTracer, reg components.Registry, service_name string, service_id string) *LeafServiceImplRegistry {
+	addr := os.Getenv("leafService_ADDRESS")
+	port := os.Getenv("leafService_PORT")
+	port_val, _ := strconv.ParseInt(port, 10, 64)
+	reg.Register(service_id, service_name, addr, port_val)
+	return &LeafServiceImplRegistry{service: service, service_name: service_name, reg: reg}
+}
+
+func (r *LeafServiceImplRegistry) Leaf(ctx context.Context, a int64, jaegerTracer_trace_ctx string) (int64, error) {
+	return r.service.Leaf(ctx, a, jaegerTracer_trace_ctx)
+
+}
+
+func (r *LeafServiceImplRegistry) Object(ctx context.Context, obj services.LeafObject, jaegerTracer_trace_ctx string) (services.LeafObject, error) {
+	return r.service.Object(ctx, obj, jaegerTracer_trace_ctx)
+
+}
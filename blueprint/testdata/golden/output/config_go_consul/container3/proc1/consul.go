@@ -0,0 +1,30 @@
+// Blueprint: auto-generated by Consul plugin
+package proc1
+
+import (
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/choices/registry"
+	"log"
+	"os"
+	"strconv"
+)
+
+type consul struct {
+	reg *registry.ConsulRegistry
+}
+
+func Newconsul() *consul {
+	addr := os.Getenv("consul_ADDRESS")
+	port := os.Getenv("consul_PORT")
+	port_val, _ := strconv.ParseInt(port, 10, 64)
+	reg, err := registry.NewConsulRegistry(addr, int(port_val))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return &consul{reg: reg}
+
+}
+
+func (r *consul) Register(ID string, name string, address string, port int64) error {
+	return r.reg.Register(ID, name, address, port)
+
+}
@@ -0,0 +1,19 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc1
+
+import "spec/services"
+
+func GetleafService() *LeafServiceImplHandler {
+	consul := Newconsul()
+	jaegertracer := NewjaegerTracer()
+	spec_handler := services.NewLeafServiceImpl()
+	leafserviceimpl := NewLeafServiceImpl(spec_handler)
+	leafserviceimpltracer := NewLeafServiceImplTracer(leafserviceimpl, jaegertracer, "leafService", "1")
+	leafserviceimplregistry := NewLeafServiceImplRegistry(leafserviceimpltracer, consul, "leafService", "leafService")
+	leafserviceimplhandler := NewLeafServiceImplHandler(leafserviceimplregistry, "grpc")
+	return leafserviceimplhandler
+}
+
+func RunleafService(service *LeafServiceImplHandler) error {
+	return service.Run()
+}
@@ -0,0 +1,73 @@
+// Blueprint: auto-generated by RPCgrpc plugin
+package proc2
+
+import (
+	"context"
+	"errors"
+	"gen-go/leaf"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/jinzhu/copier"
+	_ "github.com/mbobakov/grpc-consul-resolver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"os"
+	"spec/services"
+)
+
+type LeafServiceImplRPCClient struct {
+	client leaf.LeafServiceImplClient
+}
+
+func NewLeafServiceImplRPCClient() (*LeafServiceImplRPCClient, error) {
+	addr := os.Getenv("consul_ADDRESS")
+	port := os.Getenv("consul_PORT")
+	if addr == "" || port == "" {
+		return nil, errors.New("Address or port were not set")
+	}
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts = append(opts, grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`))
+	conn, err := grpc.Dial("consul://"+addr+":"+port+"/leafService", opts...)
+	if err != nil {
+		return nil, err
+	}
+	client := leaf.NewLeafServiceImplClient(conn)
+	return &LeafServiceImplRPCClient{client: client}, nil
+
+}
+
+func (rpcclient *LeafServiceImplRPCClient) Leaf(ctx context.Context, a int64, jaegerTracer_trace_ctx string) (int64, error) {
+	request := &leaf.LeafServiceImpl_LeafRequest{}
+	request.A = a
+	request.JaegerTracerTraceCtx = jaegerTracer_trace_ctx
+	response, err := rpcclient.client.Leaf(ctx, request)
+	var ret0 int64
+	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			err = &stdlib.ResourceExhaustedError{Reason: status.Convert(err).Message()}
+		}
+		return ret0, err
+	}
+	ret0 = response.RetVal0
+	return ret0, err
+}
+
+func (rpcclient *LeafServiceImplRPCClient) Object(ctx context.Context, obj services.LeafObject, jaegerTracer_trace_ctx string) (services.LeafObject, error) {
+	request := &leaf.LeafServiceImpl_ObjectRequest{}
+	arg1 := &leaf.LeafObject{}
+	copier.Copy(arg1, &obj)
+	request.Obj = arg1
+	request.JaegerTracerTraceCtx = jaegerTracer_trace_ctx
+	response, err := rpcclient.client.Object(ctx, request)
+	ret0 := services.LeafObject{}
+	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			err = &stdlib.ResourceExhaustedError{Reason: status.Convert(err).Message()}
+		}
+		return ret0, err
+	}
+	copier.Copy(&ret0, response.RetVal0)
+	return ret0, err
+}
@@ -0,0 +1,29 @@
+// Blueprint: auto-generated by Consul plugin
+package proc2
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/components"
+	"os"
+	"strconv"
+)
+
+type NonLeafServiceImplRegistry struct {
+	service      *NonLeafServiceImplTracer
+	reg          components.Registry
+	service_name string
+	service_id   string
+}
+
+func NewNonLeafServiceImplRegistry(service *NonLeafServiceImplTracer, reg components.Registry, service_name string, service_id string) *NonLeafServiceImplRegistry {
+	addr := os.Getenv("nonleafService_ADDRESS")
+	port := os.Getenv("nonleafService_PORT")
+	port_val, _ := strconv.ParseInt(port, 10, 64)
+	reg.Register(service_id, service_name, addr, port_val)
+	return &NonLeafServiceImplRegistry{service: service, service_name: service_name, reg: reg}
+}
+
+func (r *NonLeafServiceImplRegistry) Leaf(ctx context.Context, a int64, jaegerTracer_trace_ctx string) (int64, error) {
+	return r.service.Leaf(ctx, a, jaegerTracer_trace_ctx)
+
+}
@@ -0,0 +1,25 @@
+// Blueprint: auto-generated by Jaeger plugin
+package proc2
+
+import (
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/choices/tracer"
+	"go.opentelemetry.io/otel/trace"
+	"os"
+)
+
+type jaegerTracer struct {
+	internal *tracer.JaegerTracer
+}
+
+func NewjaegerTracer() *jaegerTracer {
+	addr := os.Getenv("jaegerTracer_ADDRESS")
+	port := os.Getenv("jaegerTracer_PORT")
+	int_tracer := tracer.NewJaegerTracer(addr, port)
+	return &jaegerTracer{internal: int_tracer}
+
+}
+
+func (t *jaegerTracer) GetTracerProvider() (trace.TracerProvider, error) {
+	return t.internal.GetTracerProvider()
+
+}
@@ -0,0 +1,32 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc2
+
+import "spec/services"
+import "log"
+
+func GetnonleafService() *NonLeafServiceImplHandler {
+	consul := Newconsul()
+	jaegertracer := NewjaegerTracer()
+	var leafservice_leafserviceimplrpcclient_neterr error
+	var leafservice_leafserviceimplrpcclient_netclient *LeafServiceImplRPCClient
+	for {
+		leafservice_leafserviceimplrpcclient_netclient, leafservice_leafserviceimplrpcclient_neterr = NewLeafServiceImplRPCClient()
+		if leafservice_leafserviceimplrpcclient_neterr == nil {
+			break
+		} else {
+			log.Println(leafservice_leafserviceimplrpcclient_neterr)
+		}
+	}
+	leafservice_leafserviceimpltracerclient := NewLeafServiceImplTracerClient(leafservice_leafserviceimplrpcclient_netclient, jaegertracer, "leafService", "1")
+	leafservice_leafserviceimplclient := NewLeafServiceImplClient(leafservice_leafserviceimpltracerclient)
+	spec_handler := services.NewNonLeafServiceImpl(leafservice_leafserviceimplclient)
+	nonleafserviceimpl := NewNonLeafServiceImpl(spec_handler)
+	nonleafserviceimpltracer := NewNonLeafServiceImplTracer(nonleafserviceimpl, jaegertracer, "nonleafService", "1")
+	nonleafserviceimplregistry := NewNonLeafServiceImplRegistry(nonleafserviceimpltracer, consul, "nonleafService", "nonleafService")
+	nonleafserviceimplhandler := NewNonLeafServiceImplHandler(nonleafserviceimplregistry, "grpc")
+	return nonleafserviceimplhandler
+}
+
+func RunnonleafService(service *NonLeafServiceImplHandler) error {
+	return service.Run()
+}
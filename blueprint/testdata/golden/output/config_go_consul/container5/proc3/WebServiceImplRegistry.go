@@ -0,0 +1,34 @@
+// Blueprint: auto-generated by Consul plugin
+package proc3
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/components"
+	"os"
+	"strconv"
+)
+
+type WebServiceImplRegistry struct {
+	service      *WebServiceImplTracer
+	reg          components.Registry
+	service_name string
+	service_id   string
+}
+
+func NewWebServiceImplRegistry(service *WebServiceImplTracer, reg components.Registry, service_name string, service_id string) *WebServiceImplRegistry {
+	addr := os.Getenv("webService_ADDRESS")
+	port := os.Getenv("webService_PORT")
+	port_val, _ := strconv.ParseInt(port, 10, 64)
+	reg.Register(service_id, service_name, addr, port_val)
+	return &WebServiceImplRegistry{service: service, service_name: service_name, reg: reg}
+}
+
+func (r *WebServiceImplRegistry) Hello(ctx context.Context, world string, jaegerTracer_trace_ctx string) (string, error) {
+	return r.service.Hello(ctx, world, jaegerTracer_trace_ctx)
+
+}
+
+func (r *WebServiceImplRegistry) Leaf(ctx context.Context, a int64, jaegerTracer_trace_ctx string) (int64, error) {
+	return r.service.Leaf(ctx, a, jaegerTracer_trace_ctx)
+
+}
@@ -0,0 +1,32 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc3
+
+import "spec/services"
+import "log"
+
+func GetwebService() *WebServiceImplHandler {
+	consul := Newconsul()
+	jaegertracer := NewjaegerTracer()
+	var leafservice_leafserviceimplrpcclient_neterr error
+	var leafservice_leafserviceimplrpcclient_netclient *LeafServiceImplRPCClient
+	for {
+		leafservice_leafserviceimplrpcclient_netclient, leafservice_leafserviceimplrpcclient_neterr = NewLeafServiceImplRPCClient()
+		if leafservice_leafserviceimplrpcclient_neterr == nil {
+			break
+		} else {
+			log.Println(leafservice_leafserviceimplrpcclient_neterr)
+		}
+	}
+	leafservice_leafserviceimpltracerclient := NewLeafServiceImplTracerClient(leafservice_leafserviceimplrpcclient_netclient, jaegertracer, "leafService", "1")
+	leafservice_leafserviceimplclient := NewLeafServiceImplClient(leafservice_leafserviceimpltracerclient)
+	spec_handler := services.NewWebServiceImpl(leafservice_leafserviceimplclient)
+	webserviceimpl := NewWebServiceImpl(spec_handler)
+	webserviceimpltracer := NewWebServiceImplTracer(webserviceimpl, jaegertracer, "WebService", "1")
+	webserviceimplregistry := NewWebServiceImplRegistry(webserviceimpltracer, consul, "WebService", "WebService")
+	webserviceimplhandler := NewWebServiceImplHandler(webserviceimplregistry, "default")
+	return webserviceimplhandler
+}
+
+func RunwebService(service *WebServiceImplHandler) error {
+	return service.Run()
+}
@@ -0,0 +1,73 @@
+// Blueprint: auto-generated by RPCgrpc plugin
+package proc1
+
+import (
+	"context"
+	"errors"
+	"gen-go/leaf"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/jinzhu/copier"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"os"
+	"spec/services"
+)
+
+type LeafServiceImplHandler struct {
+	service *LeafServiceImplTracer
+	leaf.UnimplementedLeafServiceImplServer
+}
+
+func NewLeafServiceImplHandler(old_handler *LeafServiceImplTracer, framework string) *LeafServiceImplHandler {
+	handler := &LeafServiceImplHandler{service: old_handler}
+	return handler
+}
+
+func (rpchandler *LeafServiceImplHandler) Leaf(ctx context.Context, request *leaf.LeafServiceImpl_LeafRequest) (*leaf.LeafServiceImpl_LeafResponse, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		ctx = stdlib.WithCallerAddr(ctx, p.Addr.String())
+	}
+	ret0, ret1 := rpchandler.service.Leaf(ctx, request.A, request.JaegerTracerTraceCtx)
+	if errors.Is(ret1, stdlib.ErrResourceExhausted) {
+		return nil, status.Error(codes.ResourceExhausted, ret1.Error())
+	}
+	response := &leaf.LeafServiceImpl_LeafResponse{}
+	response.RetVal0 = ret0
+	return response, ret1
+}
+
+func (rpchandler *LeafServiceImplHandler) Object(ctx context.Context, request *leaf.LeafServiceImpl_ObjectRequest) (*leaf.LeafServiceImpl_ObjectResponse, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		ctx = stdlib.WithCallerAddr(ctx, p.Addr.String())
+	}
+	arg1 := services.LeafObject{}
+	copier.Copy(&arg1, request.Obj)
+	ret0, ret1 := rpchandler.service.Object(ctx, arg1, request.JaegerTracerTraceCtx)
+	if errors.Is(ret1, stdlib.ErrResourceExhausted) {
+		return nil, status.Error(codes.ResourceExhausted, ret1.Error())
+	}
+	ret_updated0 := &leaf.LeafObject{}
+	copier.Copy(ret_updated0, &ret0)
+	response := &leaf.LeafServiceImpl_ObjectResponse{}
+	response.RetVal0 = ret_updated0
+	return response, ret1
+}
+
+func (rpchandler *LeafServiceImplHandler) Run() error {
+	addr := os.Getenv("leafService_ADDRESS")
+	port := os.Getenv("leafService_PORT")
+	if addr == "" || port == "" {
+		return errors.New("Address or Port were not set")
+	}
+	lis, err := net.Listen("tcp", addr+":"+port)
+	if err != nil {
+		return err
+	}
+	grpcServer := grpc.NewServer()
+	leaf.RegisterLeafServiceImplServer(grpcServer, rpchandler)
+	return grpcServer.Serve(lis)
+
+}
@@ -0,0 +1,48 @@
+// Blueprint: auto-generated by Retry plugin
+package proc2
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"log"
+	"spec/services"
+)
+
+type LeafServiceImplRetrier struct {
+	client  *LeafServiceImplRPCClient
+	retrier *stdlib.Retrier
+}
+
+func NewLeafServiceImplRetrier(client *LeafServiceImplRPCClient, retrier *stdlib.Retrier) *LeafServiceImplRetrier {
+	return &LeafServiceImplRetrier{client: client, retrier: retrier}
+
+}
+
+func NewLeafServiceImplRetrierRetrier(max_retries string) *stdlib.Retrier {
+	retrier, err := stdlib.ParseRetrier(map[string]string{"max_retries": max_retries})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return retrier
+
+}
+
+func (rm *LeafServiceImplRetrier) Leaf(ctx context.Context, a int64, jaegerTracer_trace_ctx string) (int64, error) {
+	var rm_ret_0 int64
+	var rm_ret_1 error
+	rm_ret_1 = rm.retrier.Do(ctx, "Leaf", func() error {
+		rm_ret_0, rm_ret_1 = rm.client.Leaf(ctx, a, jaegerTracer_trace_ctx)
+		return rm_ret_1
+	})
+	return rm_ret_0, rm_ret_1
+}
+
+func (rm *LeafServiceImplRetrier) Object(ctx context.Context, obj services.LeafObject, jaegerTracer_trace_ctx string) (services.LeafObject, error) {
+	var rm_ret_0 services.LeafObject
+	var rm_ret_1 error
+	rm_ret_1 = rm.retrier.Do(ctx, "Object", func() error {
+		rm_ret_0, rm_ret_1 = rm.client.Object(ctx, obj, jaegerTracer_trace_ctx)
+		return rm_ret_1
+	})
+	return rm_ret_0, rm_ret_1
+}
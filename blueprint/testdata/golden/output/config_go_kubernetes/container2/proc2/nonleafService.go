@@ -0,0 +1,17 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc2
+
+import "spec/services"
+
+func GetnonleafService() *NonLeafServiceImplHandler {
+	leafservice_leafserviceimplwebclient_netclient, _ := NewLeafServiceImplWebClient()
+	leafservice_leafserviceimplclient := NewLeafServiceImplClient(leafservice_leafserviceimplwebclient_netclient)
+	spec_handler := services.NewNonLeafServiceImpl(leafservice_leafserviceimplclient)
+	nonleafserviceimpl := NewNonLeafServiceImpl(spec_handler)
+	nonleafserviceimplhandler := NewNonLeafServiceImplHandler(nonleafserviceimpl, "default")
+	return nonleafserviceimplhandler
+}
+
+func RunnonleafService(service *NonLeafServiceImplHandler) error {
+	return service.Run()
+}
@@ -0,0 +1,15 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc2
+
+import "spec/services"
+
+func GetleafServiceReplica() *LeafServiceImplHandler {
+	spec_handler := services.NewLeafServiceImpl()
+	leafserviceimpl := NewLeafServiceImpl(spec_handler)
+	leafserviceimplhandler := NewLeafServiceImplHandler(leafserviceimpl, "grpc", "1s")
+	return leafserviceimplhandler
+}
+
+func RunleafServiceReplica(service *LeafServiceImplHandler) error {
+	return service.Run()
+}
@@ -0,0 +1,28 @@
+// Blueprint: auto-generated by LoadBalancer plugin
+package proc3
+
+import (
+	"context"
+	"genz/stdlib"
+	"spec/services"
+)
+
+type loadBalancerLeafService struct {
+	balancer *stdlib.LoadBalancer[services.LeafService]
+}
+
+func NewloadBalancerLeafService(clients ...services.LeafService) *loadBalancerLeafService {
+	lb := stdlib.NewLoadBalancer[services.LeafService](clients)
+	return &loadBalancerLeafService{balancer: lb}
+
+}
+
+func (lb *loadBalancerLeafService) Leaf(ctx context.Context, a int64) (int64, error) {
+	client := lb.balancer.PickClient()
+	return client.Leaf(ctx, a)
+}
+
+func (lb *loadBalancerLeafService) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	client := lb.balancer.PickClient()
+	return client.Object(ctx, obj)
+}
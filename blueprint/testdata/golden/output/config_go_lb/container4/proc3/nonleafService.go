@@ -0,0 +1,16 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc3
+
+import "spec/services"
+
+func GetnonleafService() *NonLeafServiceImplHandler {
+	_loadbalancerleafservice := NewloadBalancerLeafService("leafService", "leafServiceReplica")
+	spec_handler := services.NewNonLeafServiceImpl(_loadbalancerleafservice)
+	nonleafserviceimpl := NewNonLeafServiceImpl(spec_handler)
+	nonleafserviceimplhandler := NewNonLeafServiceImplHandler(nonleafserviceimpl, "grpc", "1s")
+	return nonleafserviceimplhandler
+}
+
+func RunnonleafService(service *NonLeafServiceImplHandler) error {
+	return service.Run()
+}
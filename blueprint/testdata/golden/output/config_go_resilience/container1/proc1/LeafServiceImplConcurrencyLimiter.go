@@ -0,0 +1,43 @@
+// Blueprint: auto-generated by ConcurrencyLimiter plugin
+package proc1
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"log"
+	"spec/services"
+)
+
+type LeafServiceImplConcurrencyLimiter struct {
+	service *LeafServiceImplRateLimiter
+	limiter *stdlib.ConcurrencyLimiter
+}
+
+func NewLeafServiceImplConcurrencyLimiter(service *LeafServiceImplRateLimiter, limit string, adaptive string, max_wait string, max_queue string) *LeafServiceImplConcurrencyLimiter {
+	limiter, err := stdlib.ParseConcurrencyLimiter("leafService", map[string]string{"limit": limit, "adaptive": adaptive, "max_wait": max_wait, "max_queue": max_queue})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return &LeafServiceImplConcurrencyLimiter{service: service, limiter: limiter}
+
+}
+
+func (cl *LeafServiceImplConcurrencyLimiter) Leaf(ctx context.Context, a int64) (int64, error) {
+	var cl_ret_0 int64
+	var cl_ret_1 error
+	cl_ret_1 = cl.limiter.Do(ctx, "Leaf", func() error {
+		cl_ret_0, cl_ret_1 = cl.service.Leaf(ctx, a)
+		return cl_ret_1
+	})
+	return cl_ret_0, cl_ret_1
+}
+
+func (cl *LeafServiceImplConcurrencyLimiter) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	var cl_ret_0 services.LeafObject
+	var cl_ret_1 error
+	cl_ret_1 = cl.limiter.Do(ctx, "Object", func() error {
+		cl_ret_0, cl_ret_1 = cl.service.Object(ctx, obj)
+		return cl_ret_1
+	})
+	return cl_ret_0, cl_ret_1
+}
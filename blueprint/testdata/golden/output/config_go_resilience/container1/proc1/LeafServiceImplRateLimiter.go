@@ -0,0 +1,41 @@
+// Blueprint: auto-generated by RateLimiter plugin
+package proc1
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"log"
+	"spec/services"
+)
+
+type LeafServiceImplRateLimiter struct {
+	service *LeafServiceImpl
+	limiter *stdlib.RateLimiter
+}
+
+func NewLeafServiceImplRateLimiter(service *LeafServiceImpl, rate string, burst string, per_caller string, methods string) *LeafServiceImplRateLimiter {
+	limiter, err := stdlib.ParseRateLimiter(map[string]string{"rate": rate, "burst": burst, "per_caller": per_caller, "methods": methods})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return &LeafServiceImplRateLimiter{service: service, limiter: limiter}
+
+}
+
+func (rl *LeafServiceImplRateLimiter) Leaf(ctx context.Context, a int64) (int64, error) {
+	var rl_ret_0 int64
+	var rl_ret_1 error
+	if rl_ret_1 = rl.limiter.Allow(ctx, "Leaf"); rl_ret_1 != nil {
+		return rl_ret_0, rl_ret_1
+	}
+	return rl.service.Leaf(ctx, a)
+}
+
+func (rl *LeafServiceImplRateLimiter) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	var rl_ret_0 services.LeafObject
+	var rl_ret_1 error
+	if rl_ret_1 = rl.limiter.Allow(ctx, "Object"); rl_ret_1 != nil {
+		return rl_ret_0, rl_ret_1
+	}
+	return rl.service.Object(ctx, obj)
+}
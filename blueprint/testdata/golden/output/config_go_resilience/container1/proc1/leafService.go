@@ -0,0 +1,17 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc1
+
+import "spec/services"
+
+func GetleafService() *LeafServiceImplHandler {
+	spec_handler := services.NewLeafServiceImpl()
+	leafserviceimpl := NewLeafServiceImpl(spec_handler)
+	leafserviceimplratelimiter := NewLeafServiceImplRateLimiter(leafserviceimpl, "100", "20", "True", "{Object: {rate: 10}}")
+	leafserviceimplconcurrencylimiter := NewLeafServiceImplConcurrencyLimiter(leafserviceimplratelimiter, "50", "gradient", "20ms", "100")
+	leafserviceimplhandler := NewLeafServiceImplHandler(leafserviceimplconcurrencylimiter, "default")
+	return leafserviceimplhandler
+}
+
+func RunleafService(service *LeafServiceImplHandler) error {
+	return service.Run()
+}
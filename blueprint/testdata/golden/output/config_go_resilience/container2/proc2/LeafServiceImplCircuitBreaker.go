@@ -0,0 +1,48 @@
+// Blueprint: auto-generated by CircuitBreaker plugin
+package proc2
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"log"
+	"spec/services"
+)
+
+type LeafServiceImplCircuitBreaker struct {
+	client  *LeafServiceImplTimeout
+	breaker *stdlib.CircuitBreaker
+}
+
+func NewLeafServiceImplCircuitBreaker(client *LeafServiceImplTimeout, breaker *stdlib.CircuitBreaker) *LeafServiceImplCircuitBreaker {
+	return &LeafServiceImplCircuitBreaker{client: client, breaker: breaker}
+
+}
+
+func NewLeafServiceImplCircuitBreakerBreaker(interval string, trip string, threshold string, open_timeout string, half_open_probes string, per_method string) *stdlib.CircuitBreaker {
+	breaker, err := stdlib.ParseCircuitBreaker("leafService", map[string]string{"interval": interval, "trip": trip, "threshold": threshold, "open_timeout": open_timeout, "half_open_probes": half_open_probes, "per_method": per_method})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return breaker
+
+}
+
+func (cbm *LeafServiceImplCircuitBreaker) Leaf(ctx context.Context, a int64) (int64, error) {
+	var cbm_ret_0 int64
+	var cbm_ret_1 error
+	cbm_ret_1 = cbm.breaker.Do(ctx, "Leaf", func() error {
+		cbm_ret_0, cbm_ret_1 = cbm.client.Leaf(ctx, a)
+		return cbm_ret_1
+	})
+	return cbm_ret_0, cbm_ret_1
+}
+
+func (cbm *LeafServiceImplCircuitBreaker) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	var cbm_ret_0 services.LeafObject
+	var cbm_ret_1 error
+	cbm_ret_1 = cbm.breaker.Do(ctx, "Object", func() error {
+		cbm_ret_0, cbm_ret_1 = cbm.client.Object(ctx, obj)
+		return cbm_ret_1
+	})
+	return cbm_ret_0, cbm_ret_1
+}
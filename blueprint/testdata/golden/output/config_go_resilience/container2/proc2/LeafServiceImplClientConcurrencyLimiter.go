@@ -0,0 +1,48 @@
+// Blueprint: auto-generated by ConcurrencyLimiter plugin
+package proc2
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"log"
+	"spec/services"
+)
+
+type LeafServiceImplClientConcurrencyLimiter struct {
+	client  *LeafServiceImplWebClient
+	limiter *stdlib.ConcurrencyLimiter
+}
+
+func NewLeafServiceImplClientConcurrencyLimiter(client *LeafServiceImplWebClient, limiter *stdlib.ConcurrencyLimiter) *LeafServiceImplClientConcurrencyLimiter {
+	return &LeafServiceImplClientConcurrencyLimiter{client: client, limiter: limiter}
+
+}
+
+func NewLeafServiceImplClientConcurrencyLimiterLimiter(limit string, adaptive string, per_method string, methods string) *stdlib.ConcurrencyLimiter {
+	limiter, err := stdlib.ParseConcurrencyLimiter("leafService", map[string]string{"limit": limit, "adaptive": adaptive, "per_method": per_method, "methods": methods})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return limiter
+
+}
+
+func (clm *LeafServiceImplClientConcurrencyLimiter) Leaf(ctx context.Context, a int64) (int64, error) {
+	var clm_ret_0 int64
+	var clm_ret_1 error
+	clm_ret_1 = clm.limiter.Do(ctx, "Leaf", func() error {
+		clm_ret_0, clm_ret_1 = clm.client.Leaf(ctx, a)
+		return clm_ret_1
+	})
+	return clm_ret_0, clm_ret_1
+}
+
+func (clm *LeafServiceImplClientConcurrencyLimiter) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	var clm_ret_0 services.LeafObject
+	var clm_ret_1 error
+	clm_ret_1 = clm.limiter.Do(ctx, "Object", func() error {
+		clm_ret_0, clm_ret_1 = clm.client.Object(ctx, obj)
+		return clm_ret_1
+	})
+	return clm_ret_0, clm_ret_1
+}
@@ -0,0 +1,47 @@
+// Blueprint: auto-generated by ClientPool plugin
+package proc2
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"log"
+	"spec/services"
+	"strconv"
+	"time"
+)
+
+type LeafServiceImplClientpool struct {
+	pool *stdlib.ClientPool[*LeafServiceImplRetrier]
+}
+
+func NewLeafServiceImplClientpool(max_clients string, max_wait string, fn func() *LeafServiceImplRetrier) *LeafServiceImplClientpool {
+	max_clients_num, _ := strconv.ParseInt(max_clients, 10, 64)
+	pool := stdlib.NewClientPool[*LeafServiceImplRetrier](max_clients_num, fn)
+	max_wait_duration, err := time.ParseDuration(max_wait)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pool.MaxWait = max_wait_duration
+	return &LeafServiceImplClientpool{pool: pool}
+
+}
+
+func (cp *LeafServiceImplClientpool) Leaf(ctx context.Context, a int64) (int64, error) {
+	client, err := cp.pool.PopContext(ctx)
+	if err != nil {
+		var ret_0 int64
+		return ret_0, err
+	}
+	defer cp.pool.Push(client)
+	return client.Leaf(ctx, a)
+}
+
+func (cp *LeafServiceImplClientpool) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	client, err := cp.pool.PopContext(ctx)
+	if err != nil {
+		var ret_0 services.LeafObject
+		return ret_0, err
+	}
+	defer cp.pool.Push(client)
+	return client.Object(ctx, obj)
+}
@@ -0,0 +1,48 @@
+// Blueprint: auto-generated by Retry plugin
+package proc2
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"log"
+	"spec/services"
+)
+
+type LeafServiceImplRetrier struct {
+	client  *LeafServiceImplCircuitBreaker
+	retrier *stdlib.Retrier
+}
+
+func NewLeafServiceImplRetrier(client *LeafServiceImplCircuitBreaker, retrier *stdlib.Retrier) *LeafServiceImplRetrier {
+	return &LeafServiceImplRetrier{client: client, retrier: retrier}
+
+}
+
+func NewLeafServiceImplRetrierRetrier(max_retries string, backoff string, methods string) *stdlib.Retrier {
+	retrier, err := stdlib.ParseRetrier(map[string]string{"max_retries": max_retries, "backoff": backoff, "methods": methods})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return retrier
+
+}
+
+func (rm *LeafServiceImplRetrier) Leaf(ctx context.Context, a int64) (int64, error) {
+	var rm_ret_0 int64
+	var rm_ret_1 error
+	rm_ret_1 = rm.retrier.Do(ctx, "Leaf", func() error {
+		rm_ret_0, rm_ret_1 = rm.client.Leaf(ctx, a)
+		return rm_ret_1
+	})
+	return rm_ret_0, rm_ret_1
+}
+
+func (rm *LeafServiceImplRetrier) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	var rm_ret_0 services.LeafObject
+	var rm_ret_1 error
+	rm_ret_1 = rm.retrier.Do(ctx, "Object", func() error {
+		rm_ret_0, rm_ret_1 = rm.client.Object(ctx, obj)
+		return rm_ret_1
+	})
+	return rm_ret_0, rm_ret_1
+}
@@ -0,0 +1,48 @@
+// Blueprint: auto-generated by Timeout plugin
+package proc2
+
+import (
+	"context"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"log"
+	"spec/services"
+)
+
+type LeafServiceImplTimeout struct {
+	client  *LeafServiceImplClientConcurrencyLimiter
+	timeout *stdlib.Timeout
+}
+
+func NewLeafServiceImplTimeout(client *LeafServiceImplClientConcurrencyLimiter, timeout *stdlib.Timeout) *LeafServiceImplTimeout {
+	return &LeafServiceImplTimeout{client: client, timeout: timeout}
+
+}
+
+func NewLeafServiceImplTimeoutTimeout(timeout string, methods string) *stdlib.Timeout {
+	tm_timeout, tm_err := stdlib.ParseTimeout(map[string]string{"timeout": timeout, "methods": methods})
+	if tm_err != nil {
+		log.Fatal(tm_err)
+	}
+	return tm_timeout
+
+}
+
+func (tm *LeafServiceImplTimeout) Leaf(ctx context.Context, a int64) (int64, error) {
+	var tm_ret_0 int64
+	var tm_ret_1 error
+	tm_ret_1 = tm.timeout.Do(ctx, "Leaf", func(ctx context.Context) error {
+		tm_ret_0, tm_ret_1 = tm.client.Leaf(ctx, a)
+		return tm_ret_1
+	})
+	return tm_ret_0, tm_ret_1
+}
+
+func (tm *LeafServiceImplTimeout) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	var tm_ret_0 services.LeafObject
+	var tm_ret_1 error
+	tm_ret_1 = tm.timeout.Do(ctx, "Object", func(ctx context.Context) error {
+		tm_ret_0, tm_ret_1 = tm.client.Object(ctx, obj)
+		return tm_ret_1
+	})
+	return tm_ret_0, tm_ret_1
+}
@@ -0,0 +1,65 @@
+// Blueprint: auto-generated by WebServer plugin
+package proc2
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/gorilla/mux"
+	"log"
+	"net/http"
+	"os"
+)
+
+type NonLeafServiceImplHandler struct {
+	service *NonLeafServiceImpl
+	url     string
+}
+type NonLeafServiceImpl_Leaf_WebResponse struct {
+	Ret0 int64
+}
+
+func NewNonLeafServiceImplHandler(old_handler *NonLeafServiceImpl, framework string) *NonLeafServiceImplHandler {
+	handler := &NonLeafServiceImplHandler{service: old_handler, url: ""}
+	return handler
+}
+
+func (webhandler *NonLeafServiceImplHandler) Leaf(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx, cancel := stdlib.WithEncodedDeadline(stdlib.WithCallerAddr(r.Context(), r.RemoteAddr), r.Header.Get(stdlib.DeadlineHeader))
+	defer cancel()
+	var a int64
+	arg1 := r.FormValue("a")
+	if arg1 != "" {
+		err = json.Unmarshal([]byte(arg1), &a)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			log.Println(err)
+			log.Println(arg1, "a")
+			return
+		}
+	}
+	ret0, ret1 := webhandler.service.Leaf(ctx, a)
+	if ret1 != nil {
+		stdlib.WriteHTTPError(w, ret1)
+		return
+	}
+	response := NonLeafServiceImpl_Leaf_WebResponse{}
+	response.Ret0 = ret0
+	json.NewEncoder(w).Encode(response)
+
+}
+
+func (webhandler *NonLeafServiceImplHandler) Run() error {
+	addr := os.Getenv("nonleafService_ADDRESS")
+	port := os.Getenv("nonleafService_PORT")
+	if addr == "" || port == "" {
+		return errors.New("Address or Port were not set")
+	}
+	url := "http://" + addr + ":" + port
+	router := mux.NewRouter()
+	webhandler.url = url
+	router.Path("/Leaf").HandlerFunc(webhandler.Leaf)
+	log.Println("Launching Server")
+	return http.ListenAndServe(addr+":"+port, router)
+}
@@ -0,0 +1,29 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc2
+
+import "spec/services"
+
+func GetnonleafService() *NonLeafServiceImplHandler {
+	leafservice_leafserviceimplclientconcurrencylimiter_shared := NewLeafServiceImplClientConcurrencyLimiterLimiter("20", "aimd", "True", "{Object: {limit: 5}}")
+	leafservice_leafserviceimpltimeout_shared := NewLeafServiceImplTimeoutTimeout("500ms", "{Leaf: {timeout: 100ms}}")
+	leafservice_leafserviceimplcircuitbreaker_shared := NewLeafServiceImplCircuitBreakerBreaker("10s", "consecutive_failures", "5", "5s", "2", "True")
+	leafservice_leafserviceimplretrier_shared := NewLeafServiceImplRetrierRetrier("3", "jitter", "{Object: {max_retries: 1}}")
+	leafservice_leafserviceimplclientpool_fn := func() *LeafServiceImplRetrier {
+		leafservice_leafserviceimplwebclient_netclient, _ := NewLeafServiceImplWebClient()
+		leafservice_leafserviceimplclientconcurrencylimiter := NewLeafServiceImplClientConcurrencyLimiter(leafservice_leafserviceimplwebclient_netclient, leafservice_leafserviceimplclientconcurrencylimiter_shared)
+		leafservice_leafserviceimpltimeout := NewLeafServiceImplTimeout(leafservice_leafserviceimplclientconcurrencylimiter, leafservice_leafserviceimpltimeout_shared)
+		leafservice_leafserviceimplcircuitbreaker := NewLeafServiceImplCircuitBreaker(leafservice_leafserviceimpltimeout, leafservice_leafserviceimplcircuitbreaker_shared)
+		leafservice_leafserviceimplretrier := NewLeafServiceImplRetrier(leafservice_leafserviceimplcircuitbreaker, leafservice_leafserviceimplretrier_shared)
+		return leafservice_leafserviceimplretrier
+	}
+	leafservice_leafserviceimplclientpool := NewLeafServiceImplClientpool("10", "50ms", leafservice_leafserviceimplclientpool_fn)
+	leafservice_leafserviceimplclient := NewLeafServiceImplClient(leafservice_leafserviceimplclientpool)
+	spec_handler := services.NewNonLeafServiceImpl(leafservice_leafserviceimplclient)
+	nonleafserviceimpl := NewNonLeafServiceImpl(spec_handler)
+	nonleafserviceimplhandler := NewNonLeafServiceImplHandler(nonleafserviceimpl, "default")
+	return nonleafserviceimplhandler
+}
+
+func RunnonleafService(service *NonLeafServiceImplHandler) error {
+	return service.Run()
+}
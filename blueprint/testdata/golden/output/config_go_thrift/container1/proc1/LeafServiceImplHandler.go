@@ -0,0 +1,67 @@
+// Blueprint: auto-generated by RPCaiothrift plugin
+package proc1
+
+import (
+	"context"
+	"errors"
+	"gen-go/leaf"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jinzhu/copier"
+	"os"
+	"spec/services"
+)
+
+type LeafServiceImplHandler struct {
+	service *LeafServiceImpl
+}
+
+func NewLeafServiceImplHandler(old_handler *LeafServiceImpl, framework string) *LeafServiceImplHandler {
+	handler := &LeafServiceImplHandler{service: old_handler}
+	return handler
+}
+
+func (rpchandler *LeafServiceImplHandler) Leaf(ctx context.Context, a int64) (*leaf.LeafServiceImpl_LeafResponse, error) {
+	timeout, _ := thrift.GetHeader(ctx, stdlib.DeadlineHeader)
+	ctx, cancel := stdlib.WithEncodedDeadline(ctx, timeout)
+	defer cancel()
+	ret0, ret1 := rpchandler.service.Leaf(ctx, a)
+	response := leaf.NewLeafServiceImpl_LeafResponse()
+	response.RetVal0 = ret0
+	return response, ret1
+}
+
+func (rpchandler *LeafServiceImplHandler) Object(ctx context.Context, obj *leaf.LeafObject) (*leaf.LeafServiceImpl_ObjectResponse, error) {
+	timeout, _ := thrift.GetHeader(ctx, stdlib.DeadlineHeader)
+	ctx, cancel := stdlib.WithEncodedDeadline(ctx, timeout)
+	defer cancel()
+	var arg1 services.LeafObject
+	copier.Copy(&arg1, obj)
+	ret0, ret1 := rpchandler.service.Object(ctx, arg1)
+	ret_updated0 := leaf.NewLeafObject()
+	copier.Copy(ret_updated0, &ret0)
+	response := leaf.NewLeafServiceImpl_ObjectResponse()
+	response.RetVal0 = ret_updated0
+	return response, ret1
+}
+
+func (rpchandler *LeafServiceImplHandler) Run() error {
+	var protocolFactory thrift.TProtocolFactory
+	protocolFactory = thrift.NewTHeaderProtocolFactoryConf(&thrift.TConfiguration{})
+	var transportFactory thrift.TTransportFactory
+	transportFactory = thrift.NewTTransportFactory()
+	addr := os.Getenv("leafService_ADDRESS")
+	port := os.Getenv("leafService_PORT")
+	if addr == "" || port == "" {
+		return errors.New("Address or Port were not set")
+	}
+	var transport thrift.TServerTransport
+	var err error
+	transport, err = thrift.NewTServerSocket(addr + ":" + port)
+	if err != nil {
+		return err
+	}
+	processor := leaf.NewLeafServiceImplProcessor(rpchandler)
+	server := thrift.NewTSimpleServer4(processor, transport, transportFactory, protocolFactory)
+	return server.Serve()
+}
@@ -0,0 +1,51 @@
+// Blueprint: auto-generated by RPCaiothrift plugin
+package proc2
+
+import (
+	"context"
+	"errors"
+	"gen-go/leaf"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/apache/thrift/lib/go/thrift"
+	"os"
+)
+
+type NonLeafServiceImplHandler struct {
+	service *NonLeafServiceImpl
+}
+
+func NewNonLeafServiceImplHandler(old_handler *NonLeafServiceImpl, framework string) *NonLeafServiceImplHandler {
+	handler := &NonLeafServiceImplHandler{service: old_handler}
+	return handler
+}
+
+func (rpchandler *NonLeafServiceImplHandler) Leaf(ctx context.Context, a int64) (*leaf.NonLeafServiceImpl_LeafResponse, error) {
+	timeout, _ := thrift.GetHeader(ctx, stdlib.DeadlineHeader)
+	ctx, cancel := stdlib.WithEncodedDeadline(ctx, timeout)
+	defer cancel()
+	ret0, ret1 := rpchandler.service.Leaf(ctx, a)
+	response := leaf.NewNonLeafServiceImpl_LeafResponse()
+	response.RetVal0 = ret0
+	return response, ret1
+}
+
+func (rpchandler *NonLeafServiceImplHandler) Run() error {
+	var protocolFactory thrift.TProtocolFactory
+	protocolFactory = thrift.NewTHeaderProtocolFactoryConf(&thrift.TConfiguration{})
+	var transportFactory thrift.TTransportFactory
+	transportFactory = thrift.NewTTransportFactory()
+	addr := os.Getenv("nonleafService_ADDRESS")
+	port := os.Getenv("nonleafService_PORT")
+	if addr == "" || port == "" {
+		return errors.New("Address or Port were not set")
+	}
+	var transport thrift.TServerTransport
+	var err error
+	transport, err = thrift.NewTServerSocket(addr + ":" + port)
+	if err != nil {
+		return err
+	}
+	processor := leaf.NewNonLeafServiceImplProcessor(rpchandler)
+	server := thrift.NewTSimpleServer4(processor, transport, transportFactory, protocolFactory)
+	return server.Serve()
+}
@@ -0,0 +1,75 @@
+// Blueprint: auto-generated by RPCaiothrift plugin
+package proc3
+
+import (
+	"context"
+	"errors"
+	"gen-go/leaf"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jinzhu/copier"
+	"os"
+	"spec/services"
+)
+
+type LeafServiceImplRPCClient struct {
+	client *leaf.LeafServiceImplClient
+}
+
+func NewLeafServiceImplRPCClient() (*LeafServiceImplRPCClient, error) {
+	var transportFactory thrift.TTransportFactory
+	transportFactory = thrift.NewTTransportFactory()
+	var protocolFactory thrift.TProtocolFactory
+	protocolFactory = thrift.NewTHeaderProtocolFactoryConf(&thrift.TConfiguration{})
+	var transport thrift.TTransport
+	addr := os.Getenv("leafService_ADDRESS")
+	port := os.Getenv("leafService_PORT")
+	if addr == "" || port == "" {
+		return nil, errors.New("Address or port were not set")
+	}
+	var err error
+	transport, err = thrift.NewTSocket(addr + ":" + port)
+	if err != nil {
+		return nil, err
+	}
+	transport, err = transportFactory.GetTransport(transport)
+	if err != nil {
+		return nil, err
+	}
+	err = transport.Open()
+	if err != nil {
+		return nil, err
+	}
+	protocol := protocolFactory.GetProtocol(transport)
+	return &LeafServiceImplRPCClient{client: leaf.NewLeafServiceImplClient(thrift.NewTStandardClient(protocol, protocol))}, nil
+}
+
+func (rpcclient *LeafServiceImplRPCClient) Leaf(ctx context.Context, a int64) (int64, error) {
+	if timeout, ok := stdlib.EncodeDeadline(ctx); ok {
+		ctx = thrift.SetHeader(ctx, stdlib.DeadlineHeader, timeout)
+		ctx = thrift.SetWriteHeaderList(ctx, []string{stdlib.DeadlineHeader})
+	}
+	response, err := rpcclient.client.Leaf(ctx, a)
+	var ret0 int64
+	if err != nil {
+		return ret0, err
+	}
+	ret0 = response.RetVal0
+	return ret0, err
+}
+
+func (rpcclient *LeafServiceImplRPCClient) Object(ctx context.Context, obj services.LeafObject) (services.LeafObject, error) {
+	if timeout, ok := stdlib.EncodeDeadline(ctx); ok {
+		ctx = thrift.SetHeader(ctx, stdlib.DeadlineHeader, timeout)
+		ctx = thrift.SetWriteHeaderList(ctx, []string{stdlib.DeadlineHeader})
+	}
+	arg1 := leaf.NewLeafObject()
+	copier.Copy(arg1, &obj)
+	response, err := rpcclient.client.Object(ctx, arg1)
+	ret0 := services.LeafObject{}
+	if err != nil {
+		return ret0, err
+	}
+	copier.Copy(&ret0, response.RetVal0)
+	return ret0, err
+}
@@ -0,0 +1,95 @@
+// Blueprint: auto-generated by WebServer plugin
+package proc3
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+	"github.com/gorilla/mux"
+	"log"
+	"net/http"
+	"os"
+)
+
+type WebServiceImplHandler struct {
+	service *WebServiceImpl
+	url     string
+}
+type WebServiceImpl_Hello_WebResponse struct {
+	Ret0 string
+}
+type WebServiceImpl_Leaf_WebResponse struct {
+	Ret0 int64
+}
+
+func NewWebServiceImplHandler(old_handler *WebServiceImpl, framework string) *WebServiceImplHandler {
+	handler := &WebServiceImplHandler{service: old_handler, url: ""}
+	return handler
+}
+
+func (webhandler *WebServiceImplHandler) Hello(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx, cancel := stdlib.WithEncodedDeadline(stdlib.WithCallerAddr(r.Context(), r.RemoteAddr), r.Header.Get(stdlib.DeadlineHeader))
+	defer cancel()
+	var world string
+	arg1 := r.FormValue("world")
+	if arg1 != "" {
+		err = json.Unmarshal([]byte(arg1), &world)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			log.Println(err)
+			log.Println(arg1, "world")
+			return
+		}
+	}
+	ret0, ret1 := webhandler.service.Hello(ctx, world)
+	if ret1 != nil {
+		stdlib.WriteHTTPError(w, ret1)
+		return
+	}
+	response := WebServiceImpl_Hello_WebResponse{}
+	response.Ret0 = ret0
+	json.NewEncoder(w).Encode(response)
+
+}
+
+func (webhandler *WebServiceImplHandler) Leaf(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx, cancel := stdlib.WithEncodedDeadline(stdlib.WithCallerAddr(r.Context(), r.RemoteAddr), r.Header.Get(stdlib.DeadlineHeader))
+	defer cancel()
+	var a int64
+	arg1 := r.FormValue("a")
+	if arg1 != "" {
+		err = json.Unmarshal([]byte(arg1), &a)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			log.Println(err)
+			log.Println(arg1, "a")
+			return
+		}
+	}
+	ret0, ret1 := webhandler.service.Leaf(ctx, a)
+	if ret1 != nil {
+		stdlib.WriteHTTPError(w, ret1)
+		return
+	}
+	response := WebServiceImpl_Leaf_WebResponse{}
+	response.Ret0 = ret0
+	json.NewEncoder(w).Encode(response)
+
+}
+
+func (webhandler *WebServiceImplHandler) Run() error {
+	addr := os.Getenv("webService_ADDRESS")
+	port := os.Getenv("webService_PORT")
+	if addr == "" || port == "" {
+		return errors.New("Address or Port were not set")
+	}
+	url := "http://" + addr + ":" + port
+	router := mux.NewRouter()
+	webhandler.url = url
+	router.Path("/Hello").HandlerFunc(webhandler.Hello)
+	router.Path("/Leaf").HandlerFunc(webhandler.Leaf)
+	log.Println("Launching Server")
+	return http.ListenAndServe(addr+":"+port, router)
+}
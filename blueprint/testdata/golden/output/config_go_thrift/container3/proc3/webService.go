@@ -0,0 +1,31 @@
+// Blueprint: auto-generated by Blueprint Core
+package proc3
+
+import "spec/services"
+import "log"
+
+func GetwebService() *WebServiceImplHandler {
+	leafservice_leafserviceimplclientpool_fn := func() *LeafServiceImplRPCClient {
+		var leafservice_leafserviceimplrpcclient_neterr error
+		var leafservice_leafserviceimplrpcclient_netclient *LeafServiceImplRPCClient
+		for {
+			leafservice_leafserviceimplrpcclient_netclient, leafservice_leafserviceimplrpcclient_neterr = NewLeafServiceImplRPCClient()
+			if leafservice_leafserviceimplrpcclient_neterr == nil {
+				break
+			} else {
+				log.Println(leafservice_leafserviceimplrpcclient_neterr)
+			}
+		}
+		return leafservice_leafserviceimplrpcclient_netclient
+	}
+	leafservice_leafserviceimplclientpool := NewLeafServiceImplClientpool("100", leafservice_leafserviceimplclientpool_fn)
+	leafservice_leafserviceimplclient := NewLeafServiceImplClient(leafservice_leafserviceimplclientpool)
+	spec_handler := services.NewWebServiceImpl(leafservice_leafserviceimplclient)
+	webserviceimpl := NewWebServiceImpl(spec_handler)
+	webserviceimplhandler := NewWebServiceImplHandler(webserviceimpl, "default")
+	return webserviceimplhandler
+}
+
+func RunwebService(service *WebServiceImplHandler) error {
+	return service.Run()
+}
@@ -0,0 +1,36 @@
+struct LeafObject {
+	1: i64 ID;
+	2: string Name;
+}
+
+struct LeafServiceImpl_LeafResponse{
+	1: i64 RetVal0;
+}
+
+struct LeafServiceImpl_ObjectResponse{
+	1: LeafObject RetVal0;
+}
+
+struct NonLeafServiceImpl_LeafResponse{
+	1: i64 RetVal0;
+}
+
+
+service LeafServiceImpl{
+	LeafServiceImpl_LeafResponse Leaf(
+		1: i64 a;
+	)
+	
+	LeafServiceImpl_ObjectResponse Object(
+		1: LeafObject obj;
+	)
+	
+}
+
+service NonLeafServiceImpl{
+	NonLeafServiceImpl_LeafResponse Leaf(
+		1: i64 a;
+	)
+	
+}
+
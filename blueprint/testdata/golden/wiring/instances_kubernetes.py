@@ -0,0 +1,7 @@
+default_server_conn_opts : Modifier = WebServer(framework="default")
+default_deployer : Modifier = Deployer(framework="kubernetes")
+server_modifiers : List[Modifier] = [default_server_conn_opts, default_deployer]
+
+leafService : LeafService = LeafServiceImpl().WithServer(server_modifiers)
+
+nonleafService : NonLeafService = NonLeafServiceImpl(leafService=leafService).WithServer(server_modifiers)
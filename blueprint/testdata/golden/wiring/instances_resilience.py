@@ -0,0 +1,17 @@
+default_server_conn_opts : Modifier = WebServer(framework="default")
+default_deployer : Modifier = Deployer(framework="docker")
+server_modifiers : List[Modifier] = [default_server_conn_opts, default_deployer]
+limiter_opts : Modifier = RateLimiter(rate=100, burst=20, per_caller=True, methods={"Object": {"rate": 10}})
+server_limit_opts : Modifier = ConcurrencyLimiter(limit=50, adaptive="gradient", max_wait="20ms", max_queue=100)
+leaf_server_modifiers : List[Modifier] = [limiter_opts, server_limit_opts, default_server_conn_opts, default_deployer]
+
+retry_opts : Modifier = Retry(max_retries=3, backoff="jitter", methods={"Object": {"max_retries": 1}})
+breaker_opts : Modifier = CircuitBreaker(interval="10s", trip="consecutive_failures", threshold=5, open_timeout="5s", half_open_probes=2, per_method=True)
+timeout_opts : Modifier = Timeout(timeout="500ms", methods={"Leaf": {"timeout": "100ms"}})
+client_limit_opts : Modifier = ConcurrencyLimiter(limit=20, adaptive="aimd", per_method=True, methods={"Object": {"limit": 5}})
+cpool_opts : Modifier = ClientPool(max_clients=10, max_wait="50ms")
+client_modifiers : List[Modifier] = [retry_opts, breaker_opts, timeout_opts, client_limit_opts, cpool_opts]
+
+leafService : LeafService = LeafServiceImpl().WithServer(leaf_server_modifiers).WithClient(client_modifiers)
+
+nonleafService : NonLeafService = NonLeafServiceImpl(leafService=leafService).WithServer(server_modifiers)
@@ -0,0 +1,54 @@
+package services
+
+import "context"
+
+type Healthy interface {
+	Health(ctx context.Context) error
+}
+
+type StreamService interface {
+	Healthy
+	Subscribe(ctx context.Context, topic string, updates chan string) error
+	Lookup(id int64) (string, error)
+	Notify(ctx context.Context, callback func(string)) bool
+}
+
+type StreamServiceImpl struct{}
+
+func (s *StreamServiceImpl) Health(ctx context.Context) error {
+	return nil
+}
+
+func (s *StreamServiceImpl) Subscribe(ctx context.Context, topic string, updates chan string) error {
+	return nil
+}
+
+func (s *StreamServiceImpl) Lookup(id int64) (string, error) {
+	return "", nil
+}
+
+func (s *StreamServiceImpl) Notify(ctx context.Context, callback func(string)) bool {
+	return true
+}
+
+type Reader interface {
+	Read(ctx context.Context) error
+}
+
+type Writer interface {
+	Write(ctx context.Context) error
+}
+
+type FileImpl struct{}
+
+func NewFileImpl() *FileImpl {
+	return &FileImpl{}
+}
+
+func (f *FileImpl) Read(ctx context.Context) error {
+	return nil
+}
+
+func (f *FileImpl) Write(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/blueprint"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Runs "blueprint graph" and returns the exit code
+func runGraph(args []string) int {
+	flags := flag.NewFlagSet("graph", flag.ExitOnError)
+	configPtr := flags.String("config", "", "Path to the configuration file")
+	profilePtr := flags.String("profile", "", "Name of the config profile to apply, e.g. prod")
+	formatPtr := flags.String("format", "dot", "Output format: dot or mermaid")
+	outPtr := flags.String("o", "", "Path of the file to write the graph to, defaults to stdout")
+	verbosePtr := flags.Bool("verbose", false, "Print log output")
+	flags.Parse(args)
+
+	if *configPtr == "" || (*formatPtr != "dot" && *formatPtr != "mermaid") {
+		fmt.Fprintln(os.Stderr, "Usage: blueprint graph -config=<path to config.json> [-profile=<name>] [-format=dot|mermaid] [-o=<file>]")
+		return 2
+	}
+
+	logger := log.New(os.Stderr, "", log.Ldate|log.Ltime|log.Lshortfile)
+	if !(*verbosePtr) {
+		log.SetOutput(ioutil.Discard)
+		logger.SetOutput(ioutil.Discard)
+	}
+
+	config, err := parser.ParseConfigProfile(*configPtr, *profilePtr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	topology, err := blueprint.Graph(context.Background(), config, blueprint.Options{Logger: logger})
+	if err != nil {
+		var diagErr *blueprint.DiagnosticsError
+		if errors.As(err, &diagErr) {
+			diagErr.Diagnostics.Print(os.Stderr)
+			fmt.Fprintf(os.Stderr, "%d error(s) found\n", diagErr.Diagnostics.ErrorCount())
+		} else {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		return 1
+	}
+	graph := topology.DOT()
+	if *formatPtr == "mermaid" {
+		graph = topology.Mermaid()
+	}
+	if *outPtr == "" {
+		fmt.Print(graph)
+		return 0
+	}
+	if err := ioutil.WriteFile(*outPtr, []byte(graph), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
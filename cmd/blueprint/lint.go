@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/blueprint"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Runs "blueprint lint" and returns the exit code. The exit code is 1 if any errors were found.
+func runLint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	configPtr := flags.String("config", "", "Path to the configuration file")
+	profilePtr := flags.String("profile", "", "Name of the config profile to apply, e.g. prod")
+	formatPtr := flags.String("format", "text", "Output format: text or json")
+	verbosePtr := flags.Bool("verbose", false, "Print log output")
+	flags.Parse(args)
+
+	if *configPtr == "" || (*formatPtr != "text" && *formatPtr != "json") {
+		fmt.Fprintln(os.Stderr, "Usage: blueprint lint -config=<path to config.json> [-profile=<name>] [-format=text|json]")
+		return 2
+	}
+
+	logger := log.New(os.Stderr, "", log.Ldate|log.Ltime|log.Lshortfile)
+	if !(*verbosePtr) {
+		log.SetOutput(ioutil.Discard)
+		logger.SetOutput(ioutil.Discard)
+	}
+
+	config, err := parser.ParseConfigProfile(*configPtr, *profilePtr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	diags, err := blueprint.Lint(context.Background(), config, blueprint.Options{Logger: logger})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	if *formatPtr == "json" {
+		if err := diags.WriteJSON(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 1
+		}
+	} else {
+		diags.Print(os.Stdout)
+		fmt.Printf("%d error(s), %d warning(s) found\n", diags.ErrorCount(), len(diags.Items)-diags.ErrorCount())
+	}
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
@@ -1,22 +1,41 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 
-	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
-	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/deploy"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/blueprint"
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 
 	progressbar "github.com/schollz/progressbar/v3"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		os.Exit(runGraph(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "plugins" {
+		os.Exit(runPlugins(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "templates" {
+		os.Exit(runTemplates(os.Args[2:]))
+	}
+
 	configPtr := flag.String("config", "", "Path to the configuration file")
+	profilePtr := flag.String("profile", "", "Name of the config profile to apply, e.g. prod")
 	verbosePtr := flag.Bool("verbose", false, "Print log output")
+	dumpIRPtr := flag.String("dump-ir", "", "Path of a file to write the IR to as JSON")
+	dumpIRAfterPtr := flag.String("dump-ir-after", string(blueprint.StageWriteSourceCode), "Stage after which the IR is written to the -dump-ir file")
+	planPtr := flag.Bool("plan", false, "Print what would be generated and the diff against the output directory without writing to it")
+	checkPtr := flag.Bool("check", false, "Exit with a non-zero status if the output directory is out of date, without writing to it")
 
 	flag.Parse()
 
@@ -31,95 +50,61 @@ func main() {
 		logger.SetOutput(ioutil.Discard)
 	}
 
-	bar := progressbar.Default(18)
-
-	config, err := parser.ParseConfig(configFile)
+	config, err := parser.ParseConfigProfile(configFile, *profilePtr)
 	if err != nil {
-		logger.Fatal(err)
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
 	}
 
-	specParser := parser.NewSpecParser(config, logger)
-	specParser.ParseSpec()
-	bar.Add(1)
-
-	wiringParser := parser.NewWiringParser(config, logger)
-	wiringParser.ParseWiring()
-	bar.Add(1)
-
-	modregistry := generators.InitModifierRegistry(logger)
-	bar.Add(1)
-
-	generator := generators.NewGenerator(config, logger, specParser.Implementations, modregistry)
-	generator.ConvertSerializedRep(wiringParser.RootNode)
-	bar.Add(1)
-
-	printVisitor := generators.NewPrintVisitor(logger)
-	generator.RootNode.Accept(printVisitor)
-	printVisitor.Print()
-	bar.Add(1)
-
-	coLocatedServiceVisitor := generators.NewCoLocatedServiceInfosVisitor(logger)
-	generator.RootNode.Accept(coLocatedServiceVisitor)
-	bar.Add(1)
-
-	depGraphVisitor := generators.NewDependencyGraphVisitor(logger, specParser.Implementations, specParser.Services)
-	generator.RootNode.Accept(depGraphVisitor)
-	depGraph := depGraphVisitor.DepGraph
-	logger.Println("Dependency graph is as follows: \n" + depGraph.String())
-	depGraph.TopoSort(coLocatedServiceVisitor.CoLocatedServices)
-	bar.Add(1)
-
-	// Apply source code modifiers + generate network layer node files
-	fixRemoteTypeVisitor := generators.NewRemoteTypeVisitor(logger, specParser.RemoteTypes, specParser.PathPkgs, specParser.Implementations)
-	generator.RootNode.Accept(fixRemoteTypeVisitor)
-	bar.Add(1)
-
-	clientCollectorVisitor := generators.NewClientCollectorVisitor(logger, specParser.Implementations, specParser.PathPkgs, config.SrcDir, specParser.RemoteTypes, specParser.Services)
-	generator.RootNode.Accept(clientCollectorVisitor)
-	bar.Add(1)
-
-	generateVisitor := generators.NewGenerateSourceCodeVisitor(logger, modregistry, config.AppName, config.OutDir, specParser.RemoteTypes, clientCollectorVisitor.DefaultClientInfos, specParser.PathPkgs, specParser.Implementations, config.SrcDir, specParser.Enums)
-	generator.RootNode.Accept(generateVisitor)
-	bar.Add(1)
-
-	generateClientVisitor := generators.NewGenerateClientSourceCodeVisitor(logger, modregistry, config.AppName, config.OutDir, specParser.RemoteTypes, clientCollectorVisitor.DefaultClientInfos, specParser.PathPkgs, specParser.Implementations, config.SrcDir, specParser.Enums, specParser.Services, depGraph)
-	generator.RootNode.Accept(generateClientVisitor)
-	bar.Add(1)
-
-	// Addr + Port Resolution
-	portAuthority := deploy.NewPortAuthority(logger)
-	basicDeployVisitor := generators.NewBasicDeployVisitor(logger, config, portAuthority)
-	generator.RootNode.Accept(basicDeployVisitor)
-	bar.Add(1)
-
-	// Apply deployment modifiers
-	depModVisitor := generators.NewDeployModifierVisitor(logger, modregistry)
-	generator.RootNode.Accept(depModVisitor)
-	bar.Add(1)
-
-	// Collect addresses and ports
-	addrCollectorVisitor := generators.NewAddrCollectorVisitor(logger)
-	generator.RootNode.Accept(addrCollectorVisitor)
-	bar.Add(1)
-
-	specWriterVisitor := generators.NewSpecSourceWriterVisitor(logger, config.OutDir, config.AppName, config.SrcDir, specParser.RemoteTypes, specParser.Services, specParser.PathPkgs)
-	generator.RootNode.Accept(specWriterVisitor)
-	bar.Add(1)
-
-	localServicesInfoCollectorVisitor := generators.NewLocalServicesInfoCollectorVisitor(logger)
-	generator.RootNode.Accept(localServicesInfoCollectorVisitor)
-	bar.Add(1)
-
-	depgenfactory := deploy.GetDepGenFactory()
-	// Generate main functions, run scripts, container config files
-	mainVisitor := generators.NewMainVisitor(logger, config.OutDir, specParser.PathPkgs, specParser.Implementations, config.SrcDir, depgenfactory, addrCollectorVisitor.Addrs, config.Inventory, generateVisitor.Frameworks, depGraph, localServicesInfoCollectorVisitor.LocalServiceInfos)
-	generator.RootNode.Accept(mainVisitor)
-	bar.Add(1)
-
-	// Write Source Code to Files/Folders
-	writerVisitor := generators.NewSourceCodeWriterVisitor(logger, config.OutDir, config.AppName, config.SrcDir, specParser.RemoteTypes, specParser.Services, specParser.PathPkgs)
-	generator.RootNode.Accept(writerVisitor)
-	bar.Add(1)
+	bar := progressbar.Default(int64(len(blueprint.Stages)))
+	opts := blueprint.Options{Logger: logger, Progress: func(blueprint.Stage) { bar.Add(1) }}
+	if *dumpIRPtr != "" {
+		stage, ok := blueprint.ParseStage(*dumpIRAfterPtr)
+		if !ok {
+			fmt.Fprintln(os.Stderr, "error: unknown stage", *dumpIRAfterPtr)
+			os.Exit(2)
+		}
+		opts.Hooks = map[blueprint.Stage][]blueprint.Hook{stage: {blueprint.DumpIRHook(*dumpIRPtr)}}
+	}
+	var result *blueprint.Result
+	var plan *blueprint.Plan
+	if *planPtr || *checkPtr {
+		plan, err = blueprint.DryRun(context.Background(), config, opts)
+	} else {
+		result, err = blueprint.Compile(context.Background(), config, opts)
+	}
+	if err != nil {
+		bar.Clear()
+		var diagErr *blueprint.DiagnosticsError
+		if errors.As(err, &diagErr) {
+			// Errors in the specification and wiring are reported together
+			diagErr.Diagnostics.Print(os.Stderr)
+			fmt.Fprintf(os.Stderr, "%d error(s) found\n", diagErr.Diagnostics.ErrorCount())
+		} else {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
 
+	if plan != nil {
+		bar.Clear()
+		plan.Diagnostics.Print(os.Stderr)
+		if *planPtr {
+			plan.Write(os.Stdout, true)
+		}
+		if *checkPtr {
+			outOfDate := plan.OutOfDate()
+			for _, file := range outOfDate {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", file.Path, file.Status)
+			}
+			if len(outOfDate) > 0 {
+				fmt.Fprintf(os.Stderr, "%d file(s) in %s are out of date\n", len(outOfDate), config.OutDir)
+				os.Exit(1)
+			}
+			fmt.Println("Generated output is up to date")
+		}
+		return
+	}
+	result.Diagnostics.Print(os.Stderr)
 	fmt.Println("SUCCESS: Generated System!")
 }
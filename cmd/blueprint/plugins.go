@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators"
+)
+
+// Runs "blueprint plugins" and returns the exit code
+func runPlugins(args []string) int {
+	flags := flag.NewFlagSet("plugins", flag.ExitOnError)
+	flags.Parse(args)
+	printPlugins(os.Stdout)
+	return 0
+}
+
+// Lists the registered modifiers and components with the parameters they accept
+func printPlugins(w io.Writer) {
+	fmt.Fprintln(w, "Modifiers:")
+	for _, plugin := range generators.ModifierPlugins() {
+		fmt.Fprintf(w, "  %s - %s\n", plugin.Name, plugin.Description)
+		printParams(w, plugin.Params)
+	}
+	fmt.Fprintln(w, "\nComponents:")
+	for _, plugin := range generators.ComponentPlugins() {
+		fmt.Fprintf(w, "  %s : %s - %s\n", plugin.Type, plugin.AbstractType, plugin.Description)
+		printParams(w, plugin.Params)
+	}
+}
+
+func printParams(w io.Writer, params []generators.ParamInfo) {
+	for _, param := range params {
+		required := ""
+		if param.Required {
+			required = " (required)"
+		}
+		fmt.Fprintf(w, "      %s%s: %s\n", param.Name, required, param.Description)
+	}
+}
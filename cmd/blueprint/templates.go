@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Runs "blueprint templates" and returns the exit code
+func runTemplates(args []string) int {
+	flags := flag.NewFlagSet("templates", flag.ExitOnError)
+	outPtr := flags.String("o", "", "Directory to write the default templates to, the names are listed if not set")
+	flags.Parse(args)
+
+	if *outPtr == "" {
+		for _, name := range parser.TemplateNames() {
+			fmt.Println(name)
+		}
+		return 0
+	}
+	if err := parser.WriteDefaultTemplates(*outPtr); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
@@ -75,3 +75,9 @@ func (v *AddrCollectorVisitor) VisitConsulNode(_ Visitor, n *ConsulNode) {
 	cinfo := ConnInfo{Address: n.DepInfo.Address, Port: n.DepInfo.Port, Hostname: n.DepInfo.Hostname}
 	v.Addrs[n.Name] = cinfo
 }
+
+func (v *AddrCollectorVisitor) VisitComponentNode(_ Visitor, n ComponentNode) {
+	depInfo := n.GetDeployInfo()
+	cinfo := ConnInfo{Address: depInfo.Address, Port: depInfo.Port, Hostname: depInfo.Hostname}
+	v.Addrs[n.GetName()] = cinfo
+}
@@ -219,3 +219,20 @@ func (v *BasicDeployVisitor) VisitConsulNode(_ Visitor, n *ConsulNode) {
 	}
 	v.logger.Println("Assigned address:", n.DepInfo.Address, ":", n.DepInfo.Port, "to", n.Name)
 }
+
+func (v *BasicDeployVisitor) VisitComponentNode(_ Visitor, n ComponentNode) {
+	depInfo := n.GetDeployInfo()
+	v.modifyEnvMap(n.GetName(), depInfo.EnvVars)
+	if addr, ok := v.addresses[n.GetName()]; ok {
+		depInfo.Address = addr.Address
+		depInfo.Hostname = addr.Hostname
+		depInfo.Port = v.portAuthority.GetAvailablePort(addr.Address, addr.Port)
+	} else {
+		defaultAddress := "localhost"
+		depInfo.Hostname = defaultAddress
+		defaultPort := n.GetImage().Port
+		depInfo.Address = defaultAddress
+		depInfo.Port = v.portAuthority.GetAvailablePort(defaultAddress, defaultPort)
+	}
+	v.logger.Println("Assigned address:", depInfo.Address, ":", depInfo.Port, "to", n.GetName())
+}
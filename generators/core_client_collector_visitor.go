@@ -73,8 +73,13 @@ func (v *ClientCollectorVisitor) generateClientNode(server_node *ServiceImplInfo
 
 func (v *ClientCollectorVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServiceNode) {
 	v.logger.Println("Finding default modifiers for service", n.Name)
-	all_modifiers := make([]Modifier, len(n.ServerModifiers))
-	copy(all_modifiers, n.ServerModifiers)
+	var all_modifiers []Modifier
+	for _, modifier := range n.ServerModifiers {
+		if plugin, ok := modifierPlugins[modifier.GetPluginName()]; ok && plugin.ServerOnly {
+			continue
+		}
+		all_modifiers = append(all_modifiers, modifier)
+	}
 	all_modifiers = append(all_modifiers, n.ClientModifiers...)
 
 	v.DefaultClientInfos[n.Name] = &ClientInfo{ClientModifiers: all_modifiers, ClientNode: v.generateClientNode(n.ASTServerNodes[0]), IsService: true}
@@ -177,6 +182,16 @@ func (v *ClientCollectorVisitor) VisitConsulNode(_ Visitor, n *ConsulNode) {
 	v.DefaultClientInfos[n.Name] = &ClientInfo{ClientModifiers: all_modifiers, ClientNode: n.ASTNodes[0], IsComponent: true}
 }
 
+func (v *ClientCollectorVisitor) VisitComponentNode(_ Visitor, n ComponentNode) {
+	v.logger.Println("Finding default modifiers for service", n.GetName())
+	all_modifiers := make([]Modifier, len(n.GetServerModifiers()))
+	copy(all_modifiers, n.GetServerModifiers())
+	all_modifiers = append(all_modifiers, n.GetClientModifiers()...)
+	impl_info := v.impls[n.GetTypeName()]
+	client_node := n.GenerateClientNode(impl_info)
+	v.DefaultClientInfos[n.GetName()] = &ClientInfo{ClientModifiers: all_modifiers, ClientNode: client_node, IsComponent: true}
+}
+
 func NewClientCollectorVisitor(logger *log.Logger, impls map[string]*parser.ImplInfo, pathpkgs map[string]string, specDir string, remoteTypes map[string]*parser.ImplInfo, services map[string]*parser.ServiceInfo) *ClientCollectorVisitor {
 	return &ClientCollectorVisitor{DefaultVisitor{}, logger, make(map[string]*ClientInfo), impls, pathpkgs, specDir, remoteTypes, services}
 }
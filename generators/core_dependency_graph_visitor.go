@@ -2,20 +2,24 @@ package generators
 
 import (
 	"log"
+	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/deploy"
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
 
 type DependencyGraph struct {
-	edges   map[string][]string
-	visited map[string]bool
-	order   []string
-	Order   map[string][]string // Stores the ordering of
+	edges      map[string][]string
+	visited    map[string]bool
+	order      []string
+	Order      map[string][]string // Stores the ordering of
+	containers map[string]string   // Maps every deployed instance to the container it runs in
 }
 
 func NewDependencyGraph() *DependencyGraph {
-	return &DependencyGraph{edges: make(map[string][]string), visited: make(map[string]bool), Order: make(map[string][]string)}
+	return &DependencyGraph{edges: make(map[string][]string), visited: make(map[string]bool), Order: make(map[string][]string), containers: make(map[string]string)}
 }
 
 func (g *DependencyGraph) AddEdge(to string, from string) {
@@ -46,7 +50,7 @@ func (g *DependencyGraph) topoSortHelper(root string, is_root bool, localService
 
 func (g *DependencyGraph) TopoSort(coLocatedServices map[string][]string) {
 	// Run a topo sort from all nodes
-	for node := range g.edges {
+	for _, node := range parser.SortedKeys(g.edges) {
 		localServices := make(map[string]bool)
 		coLocated := coLocatedServices[node]
 		for _, service := range coLocated {
@@ -59,10 +63,115 @@ func (g *DependencyGraph) TopoSort(coLocatedServices map[string][]string) {
 	}
 }
 
+// Records that instance is deployed in container
+func (g *DependencyGraph) SetContainer(instance string, container string) {
+	g.containers[instance] = container
+}
+
+// Cycles returns every dependency cycle of the graph as the path of instance names that leads from an instance
+// back to itself, e.g. [a b a]. Each cycle starts at its smallest instance name.
+func (g *DependencyGraph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[string]int)
+	var path []string
+	var cycles [][]string
+	seen := make(map[string]bool)
+	var visit func(node string)
+	visit = func(node string) {
+		state[node] = onPath
+		path = append(path, node)
+		targets := append([]string{}, g.edges[node]...)
+		sort.Strings(targets)
+		for _, target := range targets {
+			switch state[target] {
+			case unvisited:
+				visit(target)
+			case onPath:
+				start := len(path) - 1
+				for path[start] != target {
+					start--
+				}
+				cycle := rotateCycle(path[start:])
+				if key := strings.Join(cycle, " "); !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[node] = done
+	}
+	for _, node := range g.nodes() {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+	return cycles
+}
+
+// Rotates the nodes of a cycle to start at the smallest name and closes it with that name
+func rotateCycle(nodes []string) []string {
+	first := 0
+	for i, node := range nodes {
+		if node < nodes[first] {
+			first = i
+		}
+	}
+	cycle := append(append([]string{}, nodes[first:]...), nodes[:first]...)
+	return append(cycle, cycle[0])
+}
+
+// Returns the sorted names of all instances of the graph
+func (g *DependencyGraph) nodes() []string {
+	names := make(map[string]bool)
+	for from, targets := range g.edges {
+		names[from] = true
+		for _, to := range targets {
+			names[to] = true
+		}
+	}
+	var nodes []string
+	for name := range names {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// StartOrder returns the instances in an order in which every instance comes after all of its dependencies
+func (g *DependencyGraph) StartOrder() []string {
+	return deploy.StartOrder(g.edges)
+}
+
+// ContainerDependencies returns the sorted names of the containers that the instances of container depend on
+func (g *DependencyGraph) ContainerDependencies(container string) []string {
+	deps := make(map[string]bool)
+	for from, targets := range g.edges {
+		if g.containers[from] != container {
+			continue
+		}
+		for _, to := range targets {
+			if target, ok := g.containers[to]; ok && target != container {
+				deps[target] = true
+			}
+		}
+	}
+	var names []string
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (g *DependencyGraph) String() string {
 	out_string := ""
-	for src, dsts := range g.edges {
-		out_string += src + " -> {" + strings.Join(dsts, ", ") + "}\n"
+	for _, src := range parser.SortedKeys(g.edges) {
+		out_string += src + " -> {" + strings.Join(g.edges[src], ", ") + "}\n"
 	}
 	return out_string
 }
@@ -81,8 +190,34 @@ func (v *DependencyGraphVisitor) VisitMillenialNode(_ Visitor, n *MillenialNode)
 	v.logger.Println("Finished building the Dependency Graph")
 }
 
+func (v *DependencyGraphVisitor) VisitDockerContainerNode(_ Visitor, n *DockerContainerNode) {
+	for _, child := range n.Children {
+		if process, ok := child.(*ProcessNode); ok {
+			for _, instance := range process.Children {
+				v.DepGraph.SetContainer(instanceName(instance), n.Name)
+			}
+		} else {
+			v.DepGraph.SetContainer(instanceName(child), n.Name)
+		}
+	}
+	v.DefaultVisitor.VisitDockerContainerNode(v, n)
+}
+
+// Returns the value of the Name field of an instance node
+func instanceName(n Node) string {
+	value := reflect.Indirect(reflect.ValueOf(n))
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+	if name := value.FieldByName("Name"); name.IsValid() && name.Kind() == reflect.String {
+		return name.String()
+	}
+	return ""
+}
+
 func (v *DependencyGraphVisitor) addDependencies(deps map[string]Dependency, name string) {
-	for _, dependency := range deps {
+	for _, dep_name := range parser.SortedKeys(deps) {
+		dependency := deps[dep_name]
 		v.DepGraph.AddEdge(dependency.InstanceName, name)
 	}
 }
@@ -74,7 +74,7 @@ func (v *GenerateClientSourceCodeVisitor) getClientNodes(name string, dependenci
 	for _, dependency := range topo_ordering {
 		v.logger.Println("Applying client modifiers for", dependency)
 		if cinfo, ok := v.cinfos[dependency]; !ok {
-			v.logger.Fatal("Could not find instance with name", dependency)
+			parser.Abort("Could not find instance with name", dependency)
 		} else {
 			modifiers := make([]Modifier, len(cinfo.ClientModifiers))
 			copy(modifiers, cinfo.ClientModifiers)
@@ -109,17 +109,19 @@ func (v *GenerateClientSourceCodeVisitor) getClientNodes(name string, dependenci
 				if cinfo.IsService {
 					new_client_node, err = modifier.ModifyClient(prev_client_node)
 					if err != nil {
-						v.logger.Fatal(err)
+						v.modregistry.Abort(modifier, err)
 					}
 				} else if cinfo.IsQueue {
 					new_client_node, err = modifier.ModifyQueue(prev_client_node)
 					if err != nil {
-						v.logger.Fatal(err)
+						v.modregistry.Abort(modifier, err)
 					}
 				}
+				v.modregistry.ReportWarnings(modifier)
 				// TODO: Add ModifyComponent
 				if new_client_node != nil {
 					new_client_node.PluginName = modifier.GetPluginName()
+					new_client_node.Generator = getType(modifier)
 					new_client_node.ModifierParams = modifier.GetParams()
 					for _, mod_param := range new_client_node.ModifierParams {
 						switch ptype := mod_param.(type) {
@@ -153,7 +155,7 @@ func (v *GenerateClientSourceCodeVisitor) getClientNodes(name string, dependenci
 			continue
 		}
 		if cinfo, ok := v.cinfos[name]; !ok {
-			v.logger.Fatal("Could not find instance with name ", name)
+			parser.Abort("Could not find instance with name ", name)
 		} else {
 			var client_nodes []*ServiceImplInfo
 			client_nodes = append(client_nodes, copyServiceImplInfo(cinfo.ClientNode))
@@ -35,18 +35,18 @@ func (v *GenerateSourceCodeVisitor) VisitMillenialNode(_ Visitor, n *MillenialNo
 		rpc_node := node.(*RPCServerModifier)
 		framework, netgenerator, err := rpc_node.GetFrameworkInfo()
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 		if _, ok := seen_frameworks[framework]; !ok {
 			v.logger.Println("Converting remote types for NetworkGenerator framework", framework)
 			netgenerator.SetAppName(v.appName)
 			err := netgenerator.ConvertRemoteTypes(v.remoteTypes)
 			if err != nil {
-				v.logger.Fatal(err)
+				parser.Abort(err)
 			}
 			err = netgenerator.ConvertEnumTypes(v.enums)
 			if err != nil {
-				v.logger.Fatal(err)
+				parser.Abort(err)
 			}
 			v.Frameworks[framework] = netgenerator
 		}
@@ -58,13 +58,13 @@ func (v *GenerateSourceCodeVisitor) VisitMillenialNode(_ Visitor, n *MillenialNo
 		rpc_node := node.(*RPCServerModifier)
 		framework, netgenerator, err := rpc_node.GetFrameworkInfo()
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 		if _, ok := generated_files[framework]; !ok {
 			v.logger.Println("Generating files for NetworkGenerator framework", framework)
 			err := netgenerator.GenerateFiles(v.outdir)
 			if err != nil {
-				v.logger.Fatal(err)
+				parser.Abort(err)
 			}
 			generated_files[framework] = true
 		}
@@ -116,10 +116,12 @@ func (v *GenerateSourceCodeVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServi
 		v.logger.Println("Applying Modifier:", modifier.GetName())
 		new_server_node, err := modifier.ModifyServer(prev_server_node)
 		if err != nil {
-			v.logger.Fatal(err)
+			v.modregistry.Abort(modifier, err)
 		}
+		v.modregistry.ReportWarnings(modifier)
 		if new_server_node != nil {
 			new_server_node.PluginName = modifier.GetPluginName()
+			new_server_node.Generator = getType(modifier)
 			new_server_node.ModifierParams = modifier.GetParams()
 			for _, param := range new_server_node.ModifierParams {
 				switch ptype := param.(type) {
@@ -139,7 +141,7 @@ func (v *GenerateSourceCodeVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServi
 
 	for name, _ := range instances {
 		if cinfo, ok := v.cinfos[name]; !ok {
-			v.logger.Fatal("Could not find instance with name ", name)
+			parser.Abort("Could not find instance with name ", name)
 		} else {
 			var client_nodes []*ServiceImplInfo
 			client_nodes = append(client_nodes, copyServiceImplInfo(cinfo.ClientNode))
@@ -1,11 +1,13 @@
 package generators
 
 import (
+	"go/token"
 	"log"
 	"reflect"
 	"strings"
 
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/deploy"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/netgen"
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
 
@@ -159,6 +161,7 @@ type ServiceImplInfo struct {
 	HasReturnDefinedObjs bool
 	BaseImports          []parser.ImportInfo
 	PluginName           string
+	Generator            string // Type of the modifier that generated this ServiceImplInfo, e.g. RetryModifier
 }
 
 type ServiceNode struct {
@@ -250,26 +253,39 @@ func (n *QueueServiceNode) GetNodes(nodeType string) []Node {
 
 var modreg *ModifierRegistry
 
+// ResetState discards the state that the generators keep in package variables, so that compiling an application
+// again in the same process generates the same output
+func ResetState() {
+	modreg = nil
+	replHandler = nil
+	generated = false
+	netgen.ResetNetGenFactory()
+	deploy.ResetDepGenFactory()
+}
+
 type Generator struct {
 	config       *parser.Config
 	logger       *log.Logger
+	diags        *parser.Diagnostics
 	RootNode     *MillenialNode
 	Registry     *IRExtensionRegistry
 	ModRegistry  *ModifierRegistry
 	ServiceImpls map[string]*parser.ImplInfo
 }
 
-func NewGenerator(config *parser.Config, logger *log.Logger, serviceImpls map[string]*parser.ImplInfo, modregistry *ModifierRegistry) *Generator {
-	registry := InitIRRegistry(logger)
+func NewGenerator(config *parser.Config, logger *log.Logger, serviceImpls map[string]*parser.ImplInfo, modregistry *ModifierRegistry, diags *parser.Diagnostics) *Generator {
+	registry := InitIRRegistry(logger, diags)
 	modreg = modregistry
-	return &Generator{config: config, logger: logger, RootNode: nil, Registry: registry, ModRegistry: modreg, ServiceImpls: serviceImpls}
+	return &Generator{config: config, logger: logger, diags: diags, RootNode: nil, Registry: registry, ModRegistry: modreg, ServiceImpls: serviceImpls}
 }
 
 func convert_argument_node(node parser.ArgumentNode) Parameter {
 	if node.IsService {
 		var modifiers []Modifier
 		for _, modifier := range node.ClientModifiers {
-			modifiers = append(modifiers, convert_modifier_node(modifier))
+			if mod := convert_modifier_node(modifier); mod != nil {
+				modifiers = append(modifiers, mod)
+			}
 		}
 		return &InstanceParameter{Name: node.Name, KeywordName: node.KeywordName, ClientModifiers: modifiers}
 	} else {
@@ -287,11 +303,20 @@ func (g *Generator) convert_detail_node(node parser.DetailNode) (Node, *parser.M
 		var deployer_node parser.ModifierNode
 		for _, child := range node.Children {
 			child_node, deployer := g.convert_detail_node(child)
+			if child_node == nil {
+				continue
+			}
 			children = append(children, child_node)
-			if deployer != nil {
+			if deployer != nil && deployer.ModifierType != "" {
 				deployer_node = *deployer
 			}
 		}
+		if len(children) == 0 {
+			return nil, nil
+		}
+		if deployer_node.ModifierType == "" {
+			deployer_node.Pos = node.Pos
+		}
 		return &ProcessNode{Name: node.Name, Children: children}, &deployer_node
 	} else if node.AbsType == "Service" || node.AbsType == "QueueService" || strings.HasSuffix(node.AbsType, "Service") {
 		var params []Parameter
@@ -302,14 +327,21 @@ func (g *Generator) convert_detail_node(node parser.DetailNode) (Node, *parser.M
 			params = append(params, convert_argument_node(arg))
 		}
 		for _, modifier := range node.ClientModifiers {
-			cmodifiers = append(cmodifiers, convert_modifier_node(modifier))
+			if mod := convert_modifier_node(modifier); mod != nil {
+				cmodifiers = append(cmodifiers, mod)
+			}
 		}
 		for _, modifier := range node.ServerModifiers {
 			if modifier.ModifierType == "Deployer" {
 				deployer_node = modifier
 				continue
 			}
-			smodifiers = append(smodifiers, convert_modifier_node(modifier))
+			if mod := convert_modifier_node(modifier); mod != nil {
+				smodifiers = append(smodifiers, mod)
+			}
+		}
+		if deployer_node.ModifierType == "" {
+			deployer_node.Pos = node.Pos
 		}
 		var serverASTNodes []*ServiceImplInfo
 		if implInfo, ok := g.ServiceImpls[node.Type]; ok {
@@ -319,7 +351,8 @@ func (g *Generator) convert_detail_node(node parser.DetailNode) (Node, *parser.M
 			serverASTNodes = append(serverASTNodes, info)
 			g.logger.Println("Found impl info for", node.Type, "for service instance", node.Name)
 		} else {
-			g.logger.Fatal("Implementation info not found for", node.Type)
+			g.diags.Errorf(node.Pos, "Implementation info not found for %s", node.Type)
+			return nil, nil
 		}
 		snode := ServiceNode{Name: node.Name, Type: node.Type, Params: params, ClientModifiers: cmodifiers, ServerModifiers: smodifiers, ASTServerNodes: serverASTNodes, ParamClientNodes: make(map[string][]*ServiceImplInfo), ModifierClientNodes: make(map[string][]*ServiceImplInfo), DepInfo: deploy.NewDeployInfo(), AbstractType: node.AbsType}
 		if strings.HasSuffix(node.AbsType, "QueueService") {
@@ -339,44 +372,66 @@ func (g *Generator) convert_detail_node(node parser.DetailNode) (Node, *parser.M
 			}
 		}
 		if deployer_idx == -1 {
-			g.logger.Fatal("No deployer attached to service instance ", node.Name)
+			g.diags.Errorf(node.Pos, "No deployer attached to service instance %s", node.Name)
+			return nil, nil
 		}
 		serverModifiers := node.ServerModifiers[:deployer_idx]
 		serverModifiers = append(serverModifiers, node.ServerModifiers[deployer_idx+1:]...)
 		node.ServerModifiers = serverModifiers
-		return g.Registry.GetNode(node), deployer_node
+		if component := g.Registry.GetNode(node); component != nil {
+			return component, deployer_node
+		}
+		return nil, nil
 	}
 	return nil, nil
 }
 
+// Returns nil if the container could not be converted. The problem has been reported to the diagnostics.
 func (g *Generator) create_container_node(node parser.ContainerNode) Node {
 	var children []Node
 	var deployer_node *parser.ModifierNode
 	for _, child := range node.Children {
 		child_node, deployer := g.convert_detail_node(child)
+		if child_node == nil {
+			continue
+		}
 		children = append(children, child_node)
 		if deployer != nil {
 			if deployer_node != nil {
-				g.logger.Fatal("Multiple deployer options provided")
+				g.diags.Errorf(deployer.Pos, "Multiple deployer options provided for %s", node.Name)
 			} else {
 				deployer_node = deployer
 			}
 		}
 	}
-	if deployer_node == nil {
-		g.logger.Fatal("No deployer option specified")
+	if len(children) == 0 {
+		return nil
+	}
+	if deployer_node == nil || deployer_node.ModifierType == "" {
+		var pos token.Position
+		if deployer_node != nil {
+			pos = deployer_node.Pos
+		}
+		g.diags.Errorf(pos, "No deployer option specified for %s", node.Name)
+		return nil
 	}
 	ctrNode, err := GetDeployerNode(*deployer_node, node.Name, children)
 	if err != nil {
-		g.logger.Fatal(err)
+		g.diags.Errorf(deployer_node.Pos, "%v", err)
+		return nil
 	}
 	return ctrNode
 }
 
+// Converts the serialized representation produced by the wiring parser into the IR.
+// Problems are reported to the diagnostics and the offending containers are left out of the IR.
 func (g *Generator) ConvertSerializedRep(node *parser.MillenialNode) {
 	var children []Node
 	for _, child := range node.Children {
 		ctr_node := g.create_container_node(child)
+		if ctr_node == nil {
+			continue
+		}
 		children = append(children, ctr_node)
 	}
 	rootNode := MillenialNode{Children: children}
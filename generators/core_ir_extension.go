@@ -9,25 +9,18 @@ import (
 type IRExtensionRegistry struct {
 	Registry map[string]func(parser.DetailNode) Node
 	logger   *log.Logger
+	diags    *parser.Diagnostics
 }
 
-func InitIRRegistry(logger *log.Logger) *IRExtensionRegistry {
+func InitIRRegistry(logger *log.Logger, diags *parser.Diagnostics) *IRExtensionRegistry {
 	reg := make(map[string]func(parser.DetailNode) Node)
 
-	// This is where you add Node generating functions to the registry
-	reg["JaegerTracer"] = GenerateJaegerNode
-	reg["ZipkinTracer"] = GenerateZipkinNode
-	reg["LocalMetricCollector"] = GenerateLocalMetricNode
-	reg["XTracerImpl"] = GenerateXTraceNode
-	reg["Memcached"] = GenerateMemcachedNode
-	reg["RedisCache"] = GenerateRedisNode
-	reg["MongoDB"] = GenerateMongoDBNode
-	reg["RabbitMQ"] = GenerateRabbitMQNode
-	reg["MySqlDB"] = GenerateMySqlDBNode
-	reg["LoadBalancer"] = GenerateLoadBalancerNode
-	reg["ConsulRegistry"] = GenerateConsulNode
-
-	return &IRExtensionRegistry{Registry: reg, logger: logger}
+	// Components register themselves with RegisterComponent
+	for typeName, plugin := range componentPlugins {
+		reg[typeName] = plugin.Generate
+	}
+
+	return &IRExtensionRegistry{Registry: reg, logger: logger, diags: diags}
 }
 
 type ExtraScriptGenerator interface {
@@ -36,9 +29,12 @@ type ExtraScriptGenerator interface {
 
 func (r *IRExtensionRegistry) GetNode(node parser.DetailNode) Node {
 	if fn, ok := r.Registry[node.Type]; ok {
+		if plugin, ok := componentPlugins[node.Type]; ok {
+			checkParams(r.diags, node.Type, node.Pos, plugin.Params, node.Arguments)
+		}
 		return fn(node)
 	}
 
-	r.logger.Fatal("No registered IR Node generator found for type:", node.Type)
+	r.diags.Errorf(node.Pos, "No registered IR Node generator found for type: %s", node.Type)
 	return nil
 }
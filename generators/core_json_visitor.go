@@ -0,0 +1,567 @@
+package generators
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// Version of the JSON document written by MarshalIR. It changes whenever documents of older versions can no longer
+// be loaded.
+const IRJSONVersion = 1
+
+// The JSON form of the IR is generated from the exported fields of the nodes:
+//   - Values stored in Node, Modifier and Parameter fields carry the name of their type in "@type".
+//   - Embedded structs, e.g. the ServiceNode of a FuncServiceNode, are flattened into the embedding object.
+//   - Objects that are referenced several times, e.g. the ModifierNode of a ServiceImplInfo, are written once with an
+//     "@id" and every other reference is written as {"@ref": id}.
+//   - Map keys are sorted, nil slices and maps are written as null.
+//
+// Unexported fields and fields tagged with `json:"-"` are not part of the document.
+type irDocument struct {
+	Version int         `json:"version"`
+	Root    interface{} `json:"root"`
+}
+
+// Types that can be stored in the interface fields of the IR, by irTypeName
+var irTypes = make(map[string]reflect.Type)
+
+// Returns the name of the struct type t in "@type". Types of other packages are qualified with their package path,
+// so that plugins can't collide with each other.
+func irTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.PkgPath() == reflect.TypeOf(MillenialNode{}).PkgPath() {
+		return t.Name()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// RegisterIRType makes the type of node known to UnmarshalIR. Every node, modifier and parameter type must be
+// registered, registering a type twice panics. The types of modifiers and components are registered by
+// RegisterModifier and RegisterComponent, the core types of the IR are registered by default.
+func RegisterIRType(node Node) {
+	t := reflect.TypeOf(node)
+	name := irTypeName(t)
+	if _, ok := irTypes[name]; ok {
+		panic("generators: IR type " + name + " is registered twice")
+	}
+	irTypes[name] = t
+}
+
+func init() {
+	nodes := []Node{
+		&MillenialNode{}, &AnsibleContainerNode{}, &DockerContainerNode{}, &KubernetesContainerNode{}, &NoOpContainerNode{},
+		&ProcessNode{}, &FuncServiceNode{}, &QueueServiceNode{}, &InstanceParameter{}, &ValueParameter{},
+		&DefaultModifier{},
+	}
+	for _, node := range nodes {
+		RegisterIRType(node)
+	}
+}
+
+// JSONVisitor serializes the IR it is accepted by into a JSON document, see MarshalIR
+type JSONVisitor struct {
+	DefaultVisitor
+	Data []byte
+	Err  error
+}
+
+func NewJSONVisitor() *JSONVisitor {
+	return &JSONVisitor{}
+}
+
+func (v *JSONVisitor) VisitMillenialNode(_ Visitor, n *MillenialNode) {
+	v.Data, v.Err = MarshalIR(n)
+}
+
+// MarshalIR returns the JSON document of the IR rooted at root. The document only depends on the IR, serializing
+// the same IR twice gives the same bytes.
+func MarshalIR(root *MillenialNode) ([]byte, error) {
+	e := &irEncoder{counts: make(map[irPointer]int), ids: make(map[irPointer]int)}
+	e.count(reflect.ValueOf(root))
+	encoded, err := e.encode(reflect.ValueOf(root), "root")
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(irDocument{Version: IRJSONVersion, Root: encoded}, "", "  ")
+}
+
+// UnmarshalIR reconstructs the IR from a document written by MarshalIR
+func UnmarshalIR(data []byte) (*MillenialNode, error) {
+	var doc struct {
+		Version int         `json:"version"`
+		Root    interface{} `json:"root"`
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.Version != IRJSONVersion {
+		return nil, fmt.Errorf("unsupported IR document version %d, expected %d", doc.Version, IRJSONVersion)
+	}
+	d := &irDecoder{ids: make(map[int]reflect.Value)}
+	root := &MillenialNode{}
+	if err := d.decode(doc.Root, reflect.ValueOf(&root).Elem(), "root"); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// Identifies a struct by its address and type, a struct and its first field share the same address
+type irPointer struct {
+	addr uintptr
+	t    reflect.Type
+}
+
+func pointerOf(v reflect.Value) irPointer {
+	return irPointer{addr: v.Pointer(), t: v.Type()}
+}
+
+// Returns the serialized fields and the embedded structs of a struct type
+func irFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("json") == "-" {
+			continue
+		}
+		if isEmbeddedStruct(field) || field.PkgPath == "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func isEmbeddedStruct(field reflect.StructField) bool {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return field.Anonymous && t.Kind() == reflect.Struct
+}
+
+// Only pointers to structs with a size keep their identity, Go may allocate every empty struct at the same address
+func isShareable(v reflect.Value) bool {
+	return v.Elem().Kind() == reflect.Struct && v.Type().Elem().Size() > 0
+}
+
+func sortedMapKeys(v reflect.Value) ([]string, map[string]reflect.Value, error) {
+	keys := make(map[string]reflect.Value)
+	var names []string
+	for _, key := range v.MapKeys() {
+		var name string
+		switch key.Kind() {
+		case reflect.String:
+			name = key.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			name = strconv.FormatInt(key.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			name = strconv.FormatUint(key.Uint(), 10)
+		default:
+			return nil, nil, fmt.Errorf("unsupported map key type %s", key.Type())
+		}
+		names = append(names, name)
+		keys[name] = key
+	}
+	sort.Strings(names)
+	return names, keys, nil
+}
+
+type irEncoder struct {
+	counts map[irPointer]int // Number of references to every struct
+	ids    map[irPointer]int
+	nextID int
+}
+
+// Counts the references to every struct that is reachable from v
+func (e *irEncoder) count(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			e.count(v.Elem())
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if isShareable(v) {
+			ptr := pointerOf(v)
+			e.counts[ptr] += 1
+			if e.counts[ptr] > 1 {
+				return
+			}
+		}
+		e.count(v.Elem())
+	case reflect.Struct:
+		for _, field := range irFields(v.Type()) {
+			value := v.FieldByIndex(field.Index)
+			if isEmbeddedStruct(field) && value.Kind() == reflect.Ptr {
+				// Embedded structs are flattened into the embedding one, they can't be shared
+				value = value.Elem()
+			}
+			e.count(value)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			e.count(v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			e.count(iter.Value())
+		}
+	}
+}
+
+func (e *irEncoder) encode(v reflect.Value, path string) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		elem := v.Elem()
+		name := irTypeName(elem.Type())
+		if irTypes[name] != elem.Type() {
+			return nil, fmt.Errorf("%s: type %s is not registered with RegisterIRType", path, elem.Type())
+		}
+		encoded, err := e.encode(elem, path)
+		if obj, ok := encoded.(map[string]interface{}); ok && obj["@ref"] == nil {
+			obj["@type"] = name
+		}
+		return encoded, err
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Elem().Kind() != reflect.Struct {
+			return e.encode(v.Elem(), path)
+		}
+		if !isShareable(v) {
+			obj := make(map[string]interface{})
+			return obj, e.encodeStruct(v.Elem(), obj, path)
+		}
+		ptr := pointerOf(v)
+		if id, ok := e.ids[ptr]; ok {
+			return map[string]interface{}{"@ref": id}, nil
+		}
+		obj := make(map[string]interface{})
+		if e.counts[ptr] > 1 {
+			e.nextID += 1
+			e.ids[ptr] = e.nextID
+			obj["@id"] = e.nextID
+		}
+		return obj, e.encodeStruct(v.Elem(), obj, path)
+	case reflect.Struct:
+		obj := make(map[string]interface{})
+		return obj, e.encodeStruct(v, obj, path)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			elem, err := e.encode(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = elem
+		}
+		return list, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		names, keys, err := sortedMapKeys(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		obj := make(map[string]interface{})
+		for _, name := range names {
+			elem, err := e.encode(v.MapIndex(keys[name]), path+"["+strconv.Quote(name)+"]")
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = elem
+		}
+		return obj, nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	}
+	return nil, fmt.Errorf("%s: values of type %s cannot be serialized", path, v.Type())
+}
+
+// Adds the fields of the struct v to obj, embedded structs are flattened
+func (e *irEncoder) encodeStruct(v reflect.Value, obj map[string]interface{}, path string) error {
+	for _, field := range irFields(v.Type()) {
+		value := v.FieldByIndex(field.Index)
+		if isEmbeddedStruct(field) {
+			if value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if err := e.encodeStruct(value, obj, path); err != nil {
+				return err
+			}
+			continue
+		}
+		encoded, err := e.encode(value, path+"."+field.Name)
+		if err != nil {
+			return err
+		}
+		obj[field.Name] = encoded
+	}
+	return nil
+}
+
+type irDecoder struct {
+	ids map[int]reflect.Value
+}
+
+// Looks up the value that an {"@ref": id} object refers to
+func (d *irDecoder) reference(obj map[string]interface{}, t reflect.Type, path string) (reflect.Value, bool, error) {
+	ref, ok := obj["@ref"]
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+	id, err := ref.(json.Number).Int64()
+	if err != nil {
+		return reflect.Value{}, true, fmt.Errorf("%s: invalid reference %v", path, ref)
+	}
+	value, ok := d.ids[int(id)]
+	if !ok {
+		return reflect.Value{}, true, fmt.Errorf("%s: reference to unknown object %d", path, id)
+	}
+	if !value.Type().AssignableTo(t) {
+		return reflect.Value{}, true, fmt.Errorf("%s: reference to %s where %s is expected", path, value.Type(), t)
+	}
+	return value, true, nil
+}
+
+// Allocates the struct an object describes and decodes its fields
+func (d *irDecoder) decodeObject(obj map[string]interface{}, t reflect.Type, path string) (reflect.Value, error) {
+	ptr := reflect.New(t.Elem())
+	if id, ok := obj["@id"]; ok {
+		num, err := id.(json.Number).Int64()
+		if err != nil {
+			return ptr, fmt.Errorf("%s: invalid id %v", path, id)
+		}
+		// Registered before the fields are decoded so that cyclic references can be resolved
+		d.ids[int(num)] = ptr
+	}
+	used := map[string]bool{"@id": true, "@type": true}
+	if err := d.decodeStruct(obj, ptr.Elem(), used, path); err != nil {
+		return ptr, err
+	}
+	for _, key := range parser.SortedKeys(obj) {
+		if !used[key] {
+			return ptr, fmt.Errorf("%s: unknown field %s of %s", path, key, t.Elem().Name())
+		}
+	}
+	return ptr, nil
+}
+
+func (d *irDecoder) decodeStruct(obj map[string]interface{}, v reflect.Value, used map[string]bool, path string) error {
+	for _, field := range irFields(v.Type()) {
+		value := v.FieldByIndex(field.Index)
+		if isEmbeddedStruct(field) {
+			if value.Kind() == reflect.Ptr {
+				value.Set(reflect.New(field.Type.Elem()))
+				value = value.Elem()
+			}
+			if err := d.decodeStruct(obj, value, used, path); err != nil {
+				return err
+			}
+			continue
+		}
+		elem, ok := obj[field.Name]
+		if !ok {
+			continue
+		}
+		used[field.Name] = true
+		if err := d.decode(elem, value, path+"."+field.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *irDecoder) decode(data interface{}, v reflect.Value, path string) error {
+	if data == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	mismatch := fmt.Errorf("%s: cannot decode %T into %s", path, data, v.Type())
+	switch v.Kind() {
+	case reflect.Interface:
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return mismatch
+		}
+		if value, ok, err := d.reference(obj, v.Type(), path); ok {
+			if err == nil {
+				v.Set(value)
+			}
+			return err
+		}
+		name, _ := obj["@type"].(string)
+		t, ok := irTypes[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown IR type %q", path, name)
+		}
+		if !t.AssignableTo(v.Type()) {
+			return fmt.Errorf("%s: %s does not implement %s", path, name, v.Type())
+		}
+		ptr, err := d.decodeObject(obj, t, path)
+		v.Set(ptr)
+		return err
+	case reflect.Ptr:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			v.Set(reflect.New(v.Type().Elem()))
+			return d.decode(data, v.Elem(), path)
+		}
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return mismatch
+		}
+		if value, ok, err := d.reference(obj, v.Type(), path); ok {
+			if err == nil {
+				v.Set(value)
+			}
+			return err
+		}
+		ptr, err := d.decodeObject(obj, v.Type(), path)
+		v.Set(ptr)
+		return err
+	case reflect.Struct:
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return mismatch
+		}
+		return d.decodeStruct(obj, v, make(map[string]bool), path)
+	case reflect.Slice:
+		list, ok := data.([]interface{})
+		if !ok {
+			return mismatch
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(list), len(list)))
+		for i, elem := range list {
+			if err := d.decode(elem, v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return mismatch
+		}
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(obj)))
+		var names []string
+		for name := range obj {
+			names = append(names, name)
+		}
+		// Decoded in the order in which they were encoded so that references are defined before they are used
+		sort.Strings(names)
+		for _, name := range names {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.decodeMapKey(name, key); err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(obj[name], elem, path+"["+strconv.Quote(name)+"]"); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+		return nil
+	case reflect.Bool:
+		b, ok := data.(bool)
+		if !ok {
+			return mismatch
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.String:
+		s, ok := data.(string)
+		if !ok {
+			return mismatch
+		}
+		v.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, ok := data.(json.Number)
+		if !ok {
+			return mismatch
+		}
+		i, err := num.Int64()
+		if err != nil || v.OverflowInt(i) {
+			return fmt.Errorf("%s: %s is not a valid %s", path, num, v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		num, ok := data.(json.Number)
+		if !ok {
+			return mismatch
+		}
+		u, err := strconv.ParseUint(num.String(), 10, 64)
+		if err != nil || v.OverflowUint(u) {
+			return fmt.Errorf("%s: %s is not a valid %s", path, num, v.Type())
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		num, ok := data.(json.Number)
+		if !ok {
+			return mismatch
+		}
+		f, err := num.Float64()
+		if err != nil {
+			return fmt.Errorf("%s: %s is not a valid %s", path, num, v.Type())
+		}
+		v.SetFloat(f)
+		return nil
+	}
+	return errors.New(path + ": values of type " + v.Type().String() + " cannot be deserialized")
+}
+
+func (d *irDecoder) decodeMapKey(name string, key reflect.Value) error {
+	switch key.Kind() {
+	case reflect.String:
+		key.SetString(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(name, 10, 64)
+		if err != nil || key.OverflowInt(i) {
+			return fmt.Errorf("invalid map key %q", name)
+		}
+		key.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(name, 10, 64)
+		if err != nil || key.OverflowUint(u) {
+			return fmt.Errorf("invalid map key %q", name)
+		}
+		key.SetUint(u)
+	default:
+		return fmt.Errorf("unsupported map key type %s", key.Type())
+	}
+	return nil
+}
@@ -43,6 +43,7 @@ type MainVisitor struct {
 	nextClientNode   *ServiceImplInfo
 	curClientNode    *ServiceImplInfo
 	curBody          string
+	sharedBody       string // Creates the state of the clients that the clients created by a ClientPool share
 	prev_client_name string
 	client_names     map[string]string
 	client_imports   []parser.ImportInfo
@@ -82,48 +83,43 @@ func (v *MainVisitor) modifySpecModFile() {
 	v.logger.Println("Reading mod file:", out_mod_file)
 	data, err := ioutil.ReadFile(out_mod_file)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	f, err := modfile.ParseLax(out_mod_file, data, nil)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	f.Module.Mod.Path = "spec"
 	f.Module.Syntax.Token = []string{"module", "spec"}
 	err = f.AddRequire("github.com/alifarahbakhsh/forked-legacy-blueprint-compiler", VERSION)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	bytes, err := f.Format()
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
-	outf, err := os.OpenFile(out_mod_file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	err = parser.WriteFile(out_mod_file, bytes, parser.FileMode)
 	if err != nil {
-		v.logger.Fatal(err)
-	}
-	defer outf.Close()
-	_, err = outf.Write(bytes)
-	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	prev_dir, err := os.Getwd()
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	err = os.Chdir(outspec_dir)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	cmd := exec.Command("go", "mod", "tidy")
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		v.logger.Fatal(string(out))
+		parser.Abort(string(out))
 	}
 	v.logger.Println(string(out))
 	err = os.Chdir(prev_dir)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 }
 
@@ -140,25 +136,26 @@ func (v *MainVisitor) VisitMillenialNode(_ Visitor, n *MillenialNode) {
 	v.logger.Println("Ending MainVisitor visit")
 	// Generate docker-compose file + other config files (Kubernetes, OpenShift)
 
-	for _, depgen := range v.depgenfactory.Generators {
+	for _, name := range parser.SortedKeys(v.depgenfactory.Generators) {
+		depgen := v.depgenfactory.Generators[name]
 		fp, err := filepath.Abs(v.out_dir)
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 		err = depgen.GenerateConfigFiles(fp)
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 	}
 
 	for _, generator := range v.scriptGenerators {
 		fp, err := filepath.Abs(v.out_dir)
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 		err = generator.Generate(fp)
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 	}
 }
@@ -169,8 +166,9 @@ func (v *MainVisitor) VisitAnsibleContainerNode(_ Visitor, n *AnsibleContainerNo
 	depgen, err := v.depgenfactory.GetGenerator("ansible")
 
 	depgen.(*deploy.AnsibleDeployerGenerator).SetInventory(v.inventory)
+	depgen.(*deploy.AnsibleDeployerGenerator).SetLogger(v.logger)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 
 	v.deployInfo.Hostname = v.hostname
@@ -183,10 +181,15 @@ func (v *MainVisitor) VisitAnsibleContainerNode(_ Visitor, n *AnsibleContainerNo
 
 func (v *MainVisitor) VisitKubernetesContainerNode(_ Visitor, n *KubernetesContainerNode) {
 	v.DefaultVisitor.VisitKubernetesContainerNode(v, n)
-	// Ensure that the generator is initialized for config file generation
-	_, err := v.depgenfactory.GetGenerator("kubernetes")
+	depgen, err := v.depgenfactory.GetGenerator("kubernetes")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
+	}
+	// The docker-compose file that kompose converts is written by the kubernetes generator after the docker one
+	if !v.isservice {
+		depgen.AddChoice(n.Name, v.deployInfo)
+	} else {
+		depgen.AddService(n.Name, v.deployInfo)
 	}
 }
 
@@ -208,7 +211,7 @@ func (v *MainVisitor) VisitDockerContainerNode(_ Visitor, n *DockerContainerNode
 	docker_dir := path.Join(v.curDir, "docker")
 	err := os.MkdirAll(docker_dir, 0755)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	v.generateModFile(v.curDir, n)
 	v.generateDockerFile(docker_dir, n)
@@ -221,11 +224,11 @@ func (v *MainVisitor) VisitDockerContainerNode(_ Visitor, n *DockerContainerNode
 	}
 
 	if !v.isservice {
-		dockerInfo := &deploy.DeployInfo{Address: v.address, Port: v.port, DockerPath: "", ImageName: v.imageName, EnvVars: v.cur_env_vars, PublicPorts: v.public_ports, Command: v.commands, Entrypoint: v.entrypoint, Volumes: v.volumes}
+		dockerInfo := &deploy.DeployInfo{Address: v.address, Port: v.port, DockerPath: "", ImageName: v.imageName, EnvVars: v.cur_env_vars, PublicPorts: v.public_ports, Command: v.commands, Entrypoint: v.entrypoint, Volumes: v.volumes, DependsOn: v.DepGraph.ContainerDependencies(n.Name)}
 		v.deployInfo = dockerInfo
 		depgen, err := v.depgenfactory.GetGenerator("docker")
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 		depgen.AddChoice(n.Name, dockerInfo)
 	}
@@ -243,19 +246,20 @@ func (v *MainVisitor) generateModFile(ctr_dir string, n *DockerContainerNode) {
 	data := []byte("module " + mod_name + "\n\ngo 1.18\n\n")
 	f, err := modfile.ParseLax(filename, data, nil)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	err = f.AddRequire("github.com/alifarahbakhsh/forked-legacy-blueprint-compiler", VERSION)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	err = f.AddRequire("spec", "v1.0.0")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	// Add Require for generated folders (THRIFT)
 	var requires []parser.RequireInfo
-	for name, netgenerator := range v.frameworks {
+	for _, name := range parser.SortedKeys(v.frameworks) {
+		netgenerator := v.frameworks[name]
 		v.logger.Println("Getting requirementes for ", name)
 		framework_requires := netgenerator.GetRequirements()
 		requires = append(requires, framework_requires...)
@@ -264,7 +268,7 @@ func (v *MainVisitor) generateModFile(ctr_dir string, n *DockerContainerNode) {
 		v.logger.Println("Adding require ", require.Name)
 		err = f.AddRequire(require.Name, require.Version)
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 	}
 	for _, replace := range requires {
@@ -273,37 +277,32 @@ func (v *MainVisitor) generateModFile(ctr_dir string, n *DockerContainerNode) {
 		}
 		err = f.AddReplace(replace.Name, replace.Version, replace.Path, "")
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 	}
 	err = f.AddReplace("spec", "", "../spec", "")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	bytes, err := f.Format()
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
-	outf, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	err = parser.WriteFile(filename, bytes, parser.FileMode)
 	if err != nil {
-		v.logger.Fatal(err)
-	}
-	defer outf.Close()
-	_, err = outf.Write(bytes)
-	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	prev_dir, err := os.Getwd()
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	err = os.Chdir(ctr_dir)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	err = os.Chdir(prev_dir)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 }
 
@@ -314,10 +313,6 @@ func (v *MainVisitor) generateDockerFile(docker_dir string, n *DockerContainerNo
 		return
 	}
 	dockerfile := path.Join(docker_dir, "Dockerfile")
-	outf, err := os.OpenFile(dockerfile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		v.logger.Fatal(err)
-	}
 	name := strings.ToLower(n.Name)
 	docker_string := ""
 	docker_string += "FROM golang:1.18-buster AS build\n\n"
@@ -335,16 +330,16 @@ func (v *MainVisitor) generateDockerFile(docker_dir string, n *DockerContainerNo
 	docker_string += "COPY --from=build " + name + " " + name + "\n"
 	docker_string += "ENTRYPOINT [\"/" + name + "\"]\n\n"
 
-	_, err = outf.WriteString(docker_string)
+	err := parser.WriteFile(dockerfile, []byte(docker_string), parser.FileMode)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 
-	dockerInfo := &deploy.DeployInfo{Address: v.address, Port: v.port, DockerPath: path.Join(name, "docker"), ImageName: v.imageName, EnvVars: v.cur_env_vars, PublicPorts: v.public_ports, NumReplicas: v.deployInfo.NumReplicas}
+	dockerInfo := &deploy.DeployInfo{Address: v.address, Port: v.port, DockerPath: path.Join(name, "docker"), ImageName: v.imageName, EnvVars: v.cur_env_vars, PublicPorts: v.public_ports, NumReplicas: v.deployInfo.NumReplicas, DependsOn: v.DepGraph.ContainerDependencies(n.Name)}
 	v.deployInfo = dockerInfo
 	depgen, err := v.depgenfactory.GetGenerator("docker")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	depgen.AddService(n.Name, dockerInfo)
 }
@@ -353,28 +348,26 @@ func (v *MainVisitor) generateMainFile() {
 	out_dir := path.Join(v.curDir, "app")
 	err := os.MkdirAll(out_dir, 0755)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	out_file := path.Join(out_dir, "main.go")
-	outf, err := os.OpenFile(out_file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		v.logger.Fatal(err)
-	}
-	defer outf.Close()
+	outf := parser.NewOutputFile(out_file, parser.FileMode)
+	defer closeOutputFile(outf)
 	_, err = outf.WriteString("// Blueprint: auto-generated by Blueprint core\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	_, err = outf.WriteString("package main\n\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	_, err = outf.WriteString("import \"" + v.ctrName + "/" + v.pkgName + "\"\nimport \"sync\"\nimport \"log\"\n\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	func_body := "func main() {\n"
-	for name, arg := range v.ProcInfo.InstanceTypes {
+	for _, name := range parser.SortedKeys(v.ProcInfo.InstanceTypes) {
+		arg := v.ProcInfo.InstanceTypes[name]
 		func_body += "\tvar " + name + " *" + v.pkgName + "." + arg.String() + "\n"
 	}
 	for _, name := range v.ProcInfo.Order {
@@ -418,7 +411,7 @@ func (v *MainVisitor) generateMainFile() {
 
 	_, err = outf.WriteString(func_body)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 }
 
@@ -451,18 +444,16 @@ func (v *MainVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServiceNode) {
 	v.cur_env_vars[n.Name+"_PORT"] = strconv.Itoa(v.port)
 	// Generate a function that starts the server for this service!
 	out_file := path.Join(v.curDir, n.Name+".go")
-	outf, err := os.OpenFile(out_file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		v.logger.Fatal(err)
-	}
-	defer outf.Close()
+	outf := parser.NewOutputFile(out_file, parser.FileMode)
+	defer closeOutputFile(outf)
+	var err error
 	_, err = outf.WriteString("// Blueprint: auto-generated by Blueprint Core\n")
 	if err != nil {
-		v.logger.Fatal()
+		parser.Abort()
 	}
 	_, err = outf.WriteString("package " + v.pkgName + "\n\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	func_name := "Get" + n.Name
 	args := []parser.ArgInfo{}
@@ -471,13 +462,15 @@ func (v *MainVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServiceNode) {
 	v.curClientNode = nil
 	v.nextClientNode = nil
 	v.curBody = ""
+	v.sharedBody = ""
 	v.prev_client_name = ""
 	v.client_imports = []parser.ImportInfo{}
 	v.added_imports = make(map[string]bool)
 	// Maps the instanceName to its corresponding variable name
 	client_names := make(map[string]string)
 	v.copyEnvVars(n.DepInfo.EnvVars)
-	for name, client_nodes := range n.ModifierClientNodes {
+	for _, name := range parser.SortedKeys(n.ModifierClientNodes) {
+		client_nodes := n.ModifierClientNodes[name]
 		conn_info := v.addrs[name]
 		v.cur_env_vars[name+"_ADDRESS"] = conn_info.Address
 		v.cur_env_vars[name+"_PORT"] = strconv.Itoa(conn_info.Port)
@@ -561,8 +554,9 @@ func (v *MainVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServiceNode) {
 			v.curBody += client_name + " := " + def_client_node.Constructors[0].Name + "(" + strings.Join(arg_strings, ", ") + ")\n"
 			v.prev_client_name = client_name
 		}
-		body += v.curBody
+		body += v.sharedBody + v.curBody
 		v.curBody = ""
+		v.sharedBody = ""
 		client_names[name] = v.prev_client_name
 	}
 
@@ -590,7 +584,7 @@ func (v *MainVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServiceNode) {
 	}
 	_, err = outf.WriteString(import_string)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 
 	main_handler_string := "spec_handler := " + pkg_name + "." + handler_node.Constructors[0].Name + "(" + strings.Join(harg_strings, ", ") + ")\n"
@@ -646,7 +640,7 @@ func (v *MainVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServiceNode) {
 	func_string += "\n}\n"
 	_, err = outf.WriteString(func_string + "\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	if has_run_func {
 		func_name := "Run" + n.Name
@@ -665,7 +659,7 @@ func (v *MainVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServiceNode) {
 		func_string += "}\n"
 		_, err = outf.WriteString(func_string + "\n")
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 		n.RunFuncName = func_name
 		v.runNames = append(v.runNames, func_name)
@@ -683,14 +677,12 @@ func (v *MainVisitor) VisitQueueServiceNode(_ Visitor, n *QueueServiceNode) {
 	v.cur_env_vars[n.Name+"_PORT"] = strconv.Itoa(v.port)
 	// Generate a function that starts the server for this service!
 	out_file := path.Join(v.curDir, n.Name+".go")
-	outf, err := os.OpenFile(out_file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		v.logger.Fatal(err)
-	}
-	defer outf.Close()
+	outf := parser.NewOutputFile(out_file, parser.FileMode)
+	defer closeOutputFile(outf)
+	var err error
 	_, err = outf.WriteString("package " + v.pkgName + "\n\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	func_name := "Run" + n.Name
 	args := []parser.ArgInfo{}
@@ -699,13 +691,15 @@ func (v *MainVisitor) VisitQueueServiceNode(_ Visitor, n *QueueServiceNode) {
 	v.curClientNode = nil
 	v.nextClientNode = nil
 	v.curBody = ""
+	v.sharedBody = ""
 	v.prev_client_name = ""
 	v.client_imports = []parser.ImportInfo{}
 	v.added_imports = make(map[string]bool)
 	client_names := make(map[string]string)
 
 	v.copyEnvVars(n.DepInfo.EnvVars)
-	for name, client_nodes := range n.ModifierClientNodes {
+	for _, name := range parser.SortedKeys(n.ModifierClientNodes) {
+		client_nodes := n.ModifierClientNodes[name]
 		conn_info := v.addrs[name]
 		v.cur_env_vars[name+"_ADDRESS"] = conn_info.Address
 		v.cur_env_vars[name+"_PORT"] = strconv.Itoa(conn_info.Port)
@@ -785,8 +779,9 @@ func (v *MainVisitor) VisitQueueServiceNode(_ Visitor, n *QueueServiceNode) {
 			v.curBody += client_name + " := " + def_client_node.Constructors[0].Name + "(" + strings.Join(arg_strings, ", ") + ")\n"
 			v.prev_client_name = client_name
 		}
-		body += v.curBody
+		body += v.sharedBody + v.curBody
 		v.curBody = ""
+		v.sharedBody = ""
 		client_names[name] = v.prev_client_name
 	}
 
@@ -814,7 +809,7 @@ func (v *MainVisitor) VisitQueueServiceNode(_ Visitor, n *QueueServiceNode) {
 	}
 	_, err = outf.WriteString(import_string)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 
 	main_handler_string := "spec_handler := " + pkg_name + "." + handler_node.Constructors[0].Name + "(" + strings.Join(harg_strings, ", ") + ")\n"
@@ -840,25 +835,36 @@ func (v *MainVisitor) VisitQueueServiceNode(_ Visitor, n *QueueServiceNode) {
 	func_string += "\n}\n"
 	_, err = outf.WriteString(func_string + "\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	n.RunFuncName = func_name
 	v.runNames = append(v.runNames, func_name)
 	v.deployInfo = n.DepInfo
 }
 
+// A client that has a second constructor shares the state it creates with the clients of the same chain, e.g. the
+// clients created by a ClientPool. The second constructor takes the parameters of the modifier and its result is
+// passed to the first constructor after the next client.
 func (v *MainVisitor) defaultClientConstructorGeneration(m Modifier) {
 	m.AddClientConstructor(v.curClientNode, v.nextClientNode)
 	// Use construtor params
 	arg_strings := []string{v.prev_client_name}
 	client_name := v.getVariableName(v.curClientNode)
+	var value_strings []string
 	for _, value := range v.curClientNode.Values {
 		if val, ok := v.client_names[value]; !ok {
-			arg_strings = append(arg_strings, "\""+value+"\"")
+			value_strings = append(value_strings, "\""+value+"\"")
 		} else {
-			arg_strings = append(arg_strings, val)
+			value_strings = append(value_strings, val)
 		}
 	}
+	if len(v.curClientNode.Constructors) > 1 {
+		shared_name := client_name + "_shared"
+		v.sharedBody += shared_name + " := " + v.curClientNode.Constructors[1].Name + "(" + strings.Join(value_strings, ", ") + ")\n"
+		arg_strings = append(arg_strings, shared_name)
+	} else {
+		arg_strings = append(arg_strings, value_strings...)
+	}
 	v.curBody += client_name + " := " + v.curClientNode.Constructors[0].Name + "(" + strings.Join(arg_strings, ", ") + ")\n"
 	v.prev_client_name = client_name
 }
@@ -910,9 +916,11 @@ func (v *MainVisitor) VisitClientPoolModifier(_ Visitor, n *ClientPoolModifier)
 	var body string
 	fn_name := client_name + "_fn"
 	v.logger.Println("Inside clientpool: Previous client name is", v.prev_client_name)
-	body = fn_name + " := func()*" + v.nextClientNode.Name + "{\n\t" + strings.ReplaceAll(v.curBody, "\n", "\n\t")
+	// The shared state is created once, outside of the function that creates the pooled clients
+	body = v.sharedBody + fn_name + " := func()*" + v.nextClientNode.Name + "{\n\t" + strings.ReplaceAll(v.curBody, "\n", "\n\t")
 	body += "return " + v.prev_client_name + "\n}\n"
 	v.curBody = body
+	v.sharedBody = ""
 	var arg_strings []string
 	for _, value := range v.curClientNode.Values {
 		if val, ok := v.client_names[value]; !ok {
@@ -934,6 +942,12 @@ func (v *MainVisitor) VisitCircuitBreakerModifier(_ Visitor, n *CircuitBreakerMo
 	v.defaultClientConstructorGeneration(n)
 }
 
+func (v *MainVisitor) VisitModifier(_ Visitor, n Modifier) {
+	if n.IsSourceCodeModifier() {
+		v.defaultClientConstructorGeneration(n)
+	}
+}
+
 func (v *MainVisitor) VisitJaegerNode(_ Visitor, n *JaegerNode) {
 	v.copyEnvVars(n.DepInfo.EnvVars)
 	v.isservice = false
@@ -1036,6 +1050,30 @@ func (v *MainVisitor) VisitMongoDBNode(_ Visitor, n *MongoDBNode) {
 	}
 }
 
+func (v *MainVisitor) VisitComponentNode(_ Visitor, n ComponentNode) {
+	depInfo := n.GetDeployInfo()
+	image := n.GetImage()
+	v.copyEnvVars(depInfo.EnvVars)
+	v.isservice = false
+	v.address = depInfo.Address
+	v.hostname = depInfo.Hostname
+	v.port = depInfo.Port
+	v.cur_env_vars[n.GetName()+"_ADDRESS"] = v.address
+	v.cur_env_vars[n.GetName()+"_PORT"] = strconv.Itoa(v.port)
+	v.public_ports[v.port] = image.Port
+	for _, port := range image.Ports {
+		v.public_ports[port] = port
+	}
+	v.imageName = image.Name
+	for _, arg := range image.Command {
+		v.commands = append(v.commands, strconv.Quote(arg))
+	}
+	for _, arg := range image.Entrypoint {
+		v.entrypoint = append(v.entrypoint, strconv.Quote(arg))
+	}
+	v.volumes = append(v.volumes, image.Volumes...)
+}
+
 func (v *MainVisitor) VisitRabbitMQNode(_ Visitor, n *RabbitMQNode) {
 	v.copyEnvVars(n.DepInfo.EnvVars)
 	v.isservice = false
@@ -1,46 +1,37 @@
 package generators
 
 import (
+	"errors"
+	"fmt"
+	"go/token"
 	"log"
+	"strings"
 
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/deploy"
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
 
 type ModifierRegistry struct {
-	Registry   map[string]func(parser.ModifierNode) Modifier
-	Boundaries map[string]bool
-	Starts     map[string]bool
-	logger     *log.Logger
+	Registry  map[string]func(parser.ModifierNode) Modifier
+	Orders    map[string]ModifierOrder
+	logger    *log.Logger
+	diags     *parser.Diagnostics
+	names     map[Modifier]string // Names of the generated modifiers in the wiring file
+	positions map[Modifier]token.Position
+	reported  map[string]bool
 }
 
-func InitModifierRegistry(logger *log.Logger) *ModifierRegistry {
+func InitModifierRegistry(logger *log.Logger, diags *parser.Diagnostics) *ModifierRegistry {
 	reg := make(map[string]func(parser.ModifierNode) Modifier)
-	boundaries := make(map[string]bool)
-	starts := make(map[string]bool)
+	orders := make(map[string]ModifierOrder)
 
-	// This is where you add Modifier generating functions to the registry
-	reg["TracerModifier"] = GenerateTracerModifier
-	reg["RPCServer"] = GenerateRPCServerModifier
-	reg["ClientPool"] = GenerateClientPoolModifier
-	reg["MetricModifier"] = GenerateMetricModifier
-	reg["XTraceModifier"] = GenerateXTraceModifier
-	reg["WebServer"] = GenerateWebServerModifier
-	reg["PlatformReplication"] = GeneratePlaformReplicationModifier
-	reg["Retry"] = GenerateRetryModifier
-	reg["LoadBalancer"] = GenerateLoadBalancerModifier
-	reg["CircuitBreaker"] = GenerateCircuitBreakerModifier
-	reg["HealthChecker"] = GenerateHealthCheckModifier
-	reg["ConsulModifier"] = GenerateConsulModifier
-
-	// Modifiers that are at network boundaries
-	boundaries["RPCServer"] = true
-	boundaries["WebServer"] = true
-
-	// Modifiers that are at the server-client boundaries
-	starts["ClientPool"] = true
+	// Modifiers register themselves with RegisterModifier
+	for name, plugin := range modifierPlugins {
+		reg[name] = plugin.Generate
+		orders[name] = plugin.Order
+	}
 
-	return &ModifierRegistry{Registry: reg, Boundaries: boundaries, Starts: starts, logger: logger}
+	return &ModifierRegistry{Registry: reg, Orders: orders, logger: logger, diags: diags, names: make(map[Modifier]string), positions: make(map[Modifier]token.Position), reported: make(map[string]bool)}
 }
 
 func get_params(node parser.ModifierNode) []Parameter {
@@ -51,33 +42,292 @@ func get_params(node parser.ModifierNode) []Parameter {
 	return params
 }
 
+// Returns the values of the value parameters by keyword
+func valueParams(params []Parameter) map[string]string {
+	values := make(map[string]string)
+	for _, param := range params {
+		if value, ok := param.(*ValueParameter); ok {
+			values[value.KeywordName] = value.Value
+		}
+	}
+	return values
+}
+
 func (r *ModifierRegistry) GetModifier(node parser.ModifierNode) Modifier {
 	if fn, ok := r.Registry[node.ModifierType]; ok {
-		return fn(node)
+		plugin, registered := modifierPlugins[node.ModifierType]
+		if registered {
+			checkParams(r.diags, "Modifier "+node.ModifierType, node.Pos, plugin.Params, node.ModifierParams)
+		}
+		modifier := fn(node)
+		if registered && plugin.Validate != nil && modifier != nil {
+			if err := plugin.Validate(valueParams(modifier.GetParams())); err != nil {
+				r.diags.Errorf(node.Pos, "Modifier %s: %v", node.ModifierType, err)
+			}
+		}
+		if modifier != nil {
+			r.names[modifier] = node.ModifierType
+			r.positions[modifier] = node.Pos
+		}
+		return modifier
 	}
 
-	r.logger.Fatal("No registered Modifier generator found for:", node.ModifierType)
-
+	r.diags.Errorf(node.Pos, "No registered Modifier generator found for: %s", node.ModifierType)
 	return nil
 }
 
+// Returns the name of the modifier in the wiring file
+func (r *ModifierRegistry) modifierName(modifier Modifier) string {
+	if name, ok := r.names[modifier]; ok {
+		return name
+	}
+	return modifier.GetName()
+}
+
+// Returns 0 for innermost modifiers, 2 for outermost modifiers and 1 for all others
+func (r *ModifierRegistry) rank(name string) int {
+	order := r.Orders[name]
+	if order.Innermost {
+		return 0
+	} else if order.Outermost {
+		return 2
+	}
+	return 1
+}
+
+// Returns true if the modifier called outer has to be closer to the network than the modifier called inner
+func (r *ModifierRegistry) mustWrap(outer string, inner string) bool {
+	for _, name := range r.Orders[outer].Outside {
+		if name == inner {
+			return true
+		}
+	}
+	for _, name := range r.Orders[inner].Inside {
+		if name == outer {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderModifiers orders a chain of modifiers from the application code to the network so that every ModifierOrder
+// is satisfied. Modifiers that aren't constrained keep the order of the wiring file. Constraints that can't be
+// satisfied are reported as errors and the modifiers involved keep the order of the wiring file.
 func (r *ModifierRegistry) OrderModifiers(modifiers []Modifier) []Modifier {
-	var nwo_modifiers []Modifier
-	var lo_modifiers []Modifier
-	var fo_modifiers []Modifier
-	for _, modifier := range modifiers {
-		name := modifier.GetName()
-		if _, ok := r.Boundaries[name]; ok {
-			lo_modifiers = append(lo_modifiers, modifier)
-		} else if _, ok2 := r.Starts[name]; ok2 {
-			fo_modifiers = append(fo_modifiers, modifier)
-		} else {
-			nwo_modifiers = append(nwo_modifiers, modifier)
-		}
-	}
-	nwo_modifiers = append(nwo_modifiers, lo_modifiers...)
-	fo_modifiers = append(fo_modifiers, nwo_modifiers...)
-	return fo_modifiers
+	names := make([]string, len(modifiers))
+	for idx, modifier := range modifiers {
+		names[idx] = r.modifierName(modifier)
+	}
+	// inner[i][j] is true if modifier i has to be applied before modifier j
+	inner := make([][]bool, len(modifiers))
+	incoming := make([]int, len(modifiers))
+	for i := range modifiers {
+		inner[i] = make([]bool, len(modifiers))
+	}
+	for i := range modifiers {
+		for j := range modifiers {
+			if names[i] == names[j] {
+				continue
+			}
+			if r.rank(names[i]) < r.rank(names[j]) || r.mustWrap(names[j], names[i]) {
+				inner[i][j] = true
+				incoming[j] += 1
+			}
+		}
+	}
+
+	var ordered []Modifier
+	done := make([]bool, len(modifiers))
+	for len(ordered) < len(modifiers) {
+		next := -1
+		for idx := range modifiers {
+			if !done[idx] && incoming[idx] == 0 {
+				next = idx
+				break
+			}
+		}
+		if next == -1 {
+			r.reportCycle(modifiers, names, inner, done)
+			for idx, modifier := range modifiers {
+				if !done[idx] {
+					ordered = append(ordered, modifier)
+				}
+			}
+			break
+		}
+		done[next] = true
+		ordered = append(ordered, modifiers[next])
+		for idx := range modifiers {
+			if inner[next][idx] {
+				incoming[idx] -= 1
+			}
+		}
+	}
+	return ordered
+}
+
+// Reports a cycle among the modifiers that couldn't be ordered
+func (r *ModifierRegistry) reportCycle(modifiers []Modifier, names []string, inner [][]bool, done []bool) {
+	var path []int
+	on_path := make(map[int]int)
+	current := -1
+	for idx := range modifiers {
+		if !done[idx] {
+			current = idx
+			break
+		}
+	}
+	// Every remaining modifier has to be applied after another remaining modifier, so walking backwards from any of
+	// them has to run into a cycle
+	for {
+		if start, ok := on_path[current]; ok {
+			path = path[start:]
+			break
+		}
+		on_path[current] = len(path)
+		path = append(path, current)
+		for idx := range modifiers {
+			if !done[idx] && inner[idx][current] {
+				current = idx
+				break
+			}
+		}
+	}
+	var cycle []string
+	for _, idx := range path {
+		cycle = append(cycle, names[idx])
+	}
+	cycle = append(cycle, names[path[0]])
+	r.report(r.positions[modifiers[path[0]]], "conflicting modifier order: %s", strings.Join(cycle, " inside "))
+}
+
+// CheckOrders reports the chains of modifiers in root that can't be ordered, as well as modifiers that are listed
+// in an order that contradicts their constraints
+func (r *ModifierRegistry) CheckOrders(root Node) {
+	var services []*ServiceNode
+	for _, node := range root.GetNodes("FuncServiceNode") {
+		services = append(services, &node.(*FuncServiceNode).ServiceNode)
+	}
+	for _, node := range root.GetNodes("QueueServiceNode") {
+		services = append(services, &node.(*QueueServiceNode).ServiceNode)
+	}
+	for _, service := range services {
+		r.checkListedOrder(service.ServerModifiers)
+		r.checkListedOrder(service.ClientModifiers)
+		var chain []Modifier
+		chain = append(chain, service.ServerModifiers...)
+		chain = append(chain, service.ClientModifiers...)
+		r.OrderModifiers(chain)
+	}
+	for _, node := range root.GetNodes("InstanceParameter") {
+		r.checkListedOrder(node.(*InstanceParameter).ClientModifiers)
+	}
+}
+
+// Implemented by the modifiers that serve a service over a network framework, e.g. RPCServer
+type frameworkModifier interface {
+	getFrameworkName() (string, error)
+}
+
+// CheckFrameworks reports the server modifiers in root that don't work with the network framework of their server
+func (r *ModifierRegistry) CheckFrameworks(root Node) {
+	for _, node := range root.GetNodes("FuncServiceNode") {
+		modifiers := node.(*FuncServiceNode).ServerModifiers
+		framework := ""
+		for _, modifier := range modifiers {
+			if server, ok := modifier.(frameworkModifier); ok {
+				framework, _ = server.getFrameworkName()
+			}
+		}
+		if framework == "" {
+			continue
+		}
+		for _, modifier := range modifiers {
+			name := r.modifierName(modifier)
+			plugin, ok := modifierPlugins[name]
+			if !ok || len(plugin.Frameworks) == 0 {
+				continue
+			}
+			supported := false
+			for _, f := range plugin.Frameworks {
+				supported = supported || f == framework
+			}
+			if !supported {
+				r.report(r.positions[modifier], "Modifier %s doesn't work with the framework %s, only with %s", name, framework, strings.Join(plugin.Frameworks, ", "))
+			}
+		}
+	}
+}
+
+// Modifiers that are listed later in the wiring file are closer to the network than the ones listed before them
+func (r *ModifierRegistry) checkListedOrder(modifiers []Modifier) {
+	for i := range modifiers {
+		for j := i + 1; j < len(modifiers); j++ {
+			inner, outer := r.modifierName(modifiers[i]), r.modifierName(modifiers[j])
+			if inner != outer && r.mustWrap(inner, outer) {
+				r.report(r.positions[modifiers[j]], "Modifier %s is listed after %s and would be closer to the network, but %s has to be closer to the network than %s", outer, inner, inner, outer)
+			}
+		}
+	}
+}
+
+// Reports an error once, chains are ordered by several visitors
+func (r *ModifierRegistry) report(pos token.Position, format string, args ...interface{}) {
+	key := pos.String() + fmt.Sprintf(format, args...)
+	if r.reported[key] {
+		return
+	}
+	r.reported[key] = true
+	r.diags.Errorf(pos, format, args...)
+}
+
+// Modifiers that find problems while they modify a node, that the generated code can't handle, return them as
+// warnings. The warnings are reported at the position of the modifier in the wiring file.
+type Warner interface {
+	TakeWarnings() []string
+}
+
+// Reports the warnings of modifier once, clients are modified for every service that calls them
+func (r *ModifierRegistry) ReportWarnings(modifier Modifier) {
+	warner, ok := modifier.(Warner)
+	if !ok {
+		return
+	}
+	pos := r.positions[modifier]
+	for _, warning := range warner.TakeWarnings() {
+		key := "warning:" + pos.String() + warning
+		if r.reported[key] {
+			continue
+		}
+		r.reported[key] = true
+		r.diags.Warnf(pos, "%s", warning)
+	}
+}
+
+// Error of a modifier about a method of the specification, reported at the declaration of the method
+type methodError struct {
+	pos token.Position
+	msg string
+}
+
+func (e *methodError) Error() string {
+	return e.msg
+}
+
+func methodErrorf(method parser.FuncInfo, format string, args ...interface{}) error {
+	return &methodError{pos: method.Pos, msg: fmt.Sprintf(format, args...)}
+}
+
+// Abort reports err, returned by modifier while it modified a node, and stops the compilation. Errors about a
+// method are reported at its declaration, others at the position of the modifier in the wiring file.
+func (r *ModifierRegistry) Abort(modifier Modifier, err error) {
+	pos := r.positions[modifier]
+	var merr *methodError
+	if errors.As(err, &merr) && merr.pos.IsValid() {
+		pos = merr.pos
+	}
+	r.diags.Errorf(pos, "%v", err)
+	parser.Abort(err)
 }
 
 type DefaultModifier struct {
@@ -0,0 +1,238 @@
+package generators
+
+import (
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/generators/deploy"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// ParamInfo describes a keyword parameter that a modifier or component accepts in the wiring file
+type ParamInfo struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// ModifierPlugin describes a modifier that can be used in wiring files
+type ModifierPlugin struct {
+	Name        string // Name of the modifier in the wiring file, e.g. Retry
+	Description string
+	Params      []ParamInfo
+	Generate    func(node parser.ModifierNode) Modifier
+	IRType      Modifier // Value of the type returned by Generate, registered with RegisterIRType
+	Order       ModifierOrder
+	Validate    func(values map[string]string) error // Checks the values of the keyword arguments, optional
+	ServerOnly  bool                                 // Among the server modifiers, leaves the clients of the service alone
+	Frameworks  []string                             // Network frameworks of the servers the modifier works with, all if empty
+}
+
+// ModifierOrder constrains the position of a modifier in a chain of modifiers. Chains run from the application code
+// to the network: on the server side the first modifier wraps the service, on the client side the caller calls the
+// first modifier, and outer modifiers are closer to the network on both sides. Modifiers are named as in the wiring
+// file.
+type ModifierOrder struct {
+	Outermost bool     // The modifier is the closest to the network, e.g. RPCServer and its clients
+	Innermost bool     // The modifier is the closest to the application code, e.g. ClientPool
+	Inside    []string // Modifiers that have to be closer to the network than this one
+	Outside   []string // Modifiers that have to be closer to the application code than this one
+}
+
+// ComponentPlugin describes a component, e.g. a tracer or a cache, that can be instantiated in wiring files
+type ComponentPlugin struct {
+	Type         string // Type of the component in the wiring file, e.g. JaegerTracer
+	AbstractType string // Annotation of its instances in the wiring file, e.g. Tracer
+	Description  string
+	Params       []ParamInfo
+	Generate     func(node parser.DetailNode) Node
+	IRType       Node // Value of the type returned by Generate, registered with RegisterIRType
+}
+
+var modifierPlugins = make(map[string]ModifierPlugin)
+var componentPlugins = make(map[string]ComponentPlugin)
+
+// RegisterModifier makes a modifier available to wiring files. It is meant to be called from the init function of
+// the package that implements the modifier, registering the same name twice panics.
+func RegisterModifier(plugin ModifierPlugin) {
+	if plugin.Name == "" || plugin.Generate == nil {
+		panic("generators: a modifier plugin needs a name and a generate function")
+	}
+	if _, ok := modifierPlugins[plugin.Name]; ok {
+		panic("generators: modifier " + plugin.Name + " is registered twice")
+	}
+	if plugin.Order.Innermost && plugin.Order.Outermost {
+		panic("generators: modifier " + plugin.Name + " can't be both innermost and outermost")
+	}
+	if plugin.IRType != nil {
+		RegisterIRType(plugin.IRType)
+	}
+	modifierPlugins[plugin.Name] = plugin
+}
+
+// RegisterComponent makes a component type available to wiring files, see RegisterModifier. The nodes generated
+// for plugins outside of this package implement ComponentNode and are visited with VisitComponentNode.
+func RegisterComponent(plugin ComponentPlugin) {
+	if plugin.Type == "" || plugin.AbstractType == "" || plugin.Generate == nil {
+		panic("generators: a component plugin needs a type, an abstract type and a generate function")
+	}
+	if _, ok := componentPlugins[plugin.Type]; ok {
+		panic("generators: component " + plugin.Type + " is registered twice")
+	}
+	if plugin.IRType != nil {
+		RegisterIRType(plugin.IRType)
+	}
+	componentPlugins[plugin.Type] = plugin
+	parser.WiringComponents[plugin.AbstractType] = true
+}
+
+// ModifierPlugins returns the registered modifiers sorted by name
+func ModifierPlugins() []ModifierPlugin {
+	var plugins []ModifierPlugin
+	for _, plugin := range modifierPlugins {
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// ComponentPlugins returns the registered components sorted by type
+func ComponentPlugins() []ComponentPlugin {
+	var plugins []ComponentPlugin
+	for _, plugin := range componentPlugins {
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Type < plugins[j].Type })
+	return plugins
+}
+
+// Reports the keyword arguments that are not described by params and the required params that are missing
+func checkParams(diags *parser.Diagnostics, subject string, pos token.Position, params []ParamInfo, args []parser.ArgumentNode) {
+	known := make(map[string]bool)
+	var names []string
+	for _, param := range params {
+		known[param.Name] = true
+		names = append(names, param.Name)
+	}
+	given := make(map[string]bool)
+	for idx, arg := range args {
+		if arg.KeywordName == "" {
+			// Positional arguments are matched with the parameters in the order in which they are described
+			if idx < len(params) {
+				given[params[idx].Name] = true
+			}
+			continue
+		}
+		given[arg.KeywordName] = true
+		if !known[arg.KeywordName] {
+			accepted := "no parameters"
+			if len(names) > 0 {
+				accepted = "parameters: " + strings.Join(names, ", ")
+			}
+			diags.Warnf(arg.Pos, "%s does not accept parameter %s (accepted %s)", subject, arg.KeywordName, accepted)
+		}
+	}
+	for _, param := range params {
+		if param.Required && !given[param.Name] {
+			diags.Errorf(pos, "%s requires parameter %s: %s", subject, param.Name, param.Description)
+		}
+	}
+}
+
+// ComponentNode is implemented by the component nodes of plugins. Their Accept method calls
+// v.VisitComponentNode(v, n), the visitors of the compiler handle them through this interface.
+type ComponentNode interface {
+	Node
+	GetName() string
+	GetTypeName() string
+	GetParams() []Parameter
+	GetClientModifiers() []Modifier
+	GetServerModifiers() []Modifier
+	GetDeployInfo() *deploy.DeployInfo
+	GetImage() ComponentImage
+	// Generates the client used to call the component, info is the implementation of its type
+	GenerateClientNode(info *parser.ImplInfo) *ServiceImplInfo
+}
+
+// ComponentImage describes the third-party container image that a component is deployed with
+type ComponentImage struct {
+	Name       string // e.g. jaegertracing/all-in-one:latest
+	Port       int    // Port the component listens on in the container, also used as its default port
+	Ports      []int  // Further ports of the container that are published as they are
+	Command    []string
+	Entrypoint []string
+	Volumes    []string
+}
+
+// ComponentBase implements the accessors of ComponentNode. Plugins embed it in their node and implement Accept,
+// GetNodes (see GetComponentNodes), GetImage and GenerateClientNode.
+type ComponentBase struct {
+	Name            string
+	TypeName        string
+	Params          []Parameter
+	ClientModifiers []Modifier
+	ServerModifiers []Modifier
+	DepInfo         *deploy.DeployInfo
+}
+
+// NewComponentBase converts the arguments and modifiers of a component instance of the wiring file
+func NewComponentBase(node parser.DetailNode) ComponentBase {
+	base := ComponentBase{Name: node.Name, TypeName: node.Type, DepInfo: deploy.NewDeployInfo()}
+	for _, arg := range node.Arguments {
+		base.Params = append(base.Params, convert_argument_node(arg))
+	}
+	for _, modifier := range node.ClientModifiers {
+		if mod := convert_modifier_node(modifier); mod != nil {
+			base.ClientModifiers = append(base.ClientModifiers, mod)
+		}
+	}
+	for _, modifier := range node.ServerModifiers {
+		if mod := convert_modifier_node(modifier); mod != nil {
+			base.ServerModifiers = append(base.ServerModifiers, mod)
+		}
+	}
+	return base
+}
+
+func (n *ComponentBase) GetName() string {
+	return n.Name
+}
+
+func (n *ComponentBase) GetTypeName() string {
+	return n.TypeName
+}
+
+func (n *ComponentBase) GetParams() []Parameter {
+	return n.Params
+}
+
+func (n *ComponentBase) GetClientModifiers() []Modifier {
+	return n.ClientModifiers
+}
+
+func (n *ComponentBase) GetServerModifiers() []Modifier {
+	return n.ServerModifiers
+}
+
+func (n *ComponentBase) GetDeployInfo() *deploy.DeployInfo {
+	return n.DepInfo
+}
+
+// GetComponentNodes implements GetNodes for a component node
+func GetComponentNodes(n ComponentNode, nodeType string) []Node {
+	var nodes []Node
+	if getType(n) == nodeType {
+		nodes = append(nodes, n)
+	}
+	for _, child := range n.GetParams() {
+		nodes = append(nodes, child.GetNodes(nodeType)...)
+	}
+	for _, child := range n.GetClientModifiers() {
+		nodes = append(nodes, child.GetNodes(nodeType)...)
+	}
+	for _, child := range n.GetServerModifiers() {
+		nodes = append(nodes, child.GetNodes(nodeType)...)
+	}
+	return nodes
+}
@@ -201,6 +201,10 @@ func (v *PrintVisitor) VisitConsulModifier(_ Visitor, n *ConsulModifier) {
 	v.modifier_str(v.getIndentString(), "ConsulModifier", n.Params)
 }
 
+func (v *PrintVisitor) VisitModifier(_ Visitor, n Modifier) {
+	v.modifier_str(v.getIndentString(), n.GetName(), n.GetParams())
+}
+
 func (v *PrintVisitor) component_str(name string, node_name string, params []Parameter, ClientModifiers []Modifier, ServerModifiers []Modifier) {
 	v.printString += v.getIndentString() + name + " = " + node_name + "("
 	for idx, param := range params {
@@ -246,6 +250,10 @@ func (v *PrintVisitor) VisitXTraceNode(_ Visitor, n *XTraceNode) {
 	v.component_str(n.Name, "XTraceServerNode", n.Params, n.ClientModifiers, n.ServerModifiers)
 }
 
+func (v *PrintVisitor) VisitComponentNode(_ Visitor, n ComponentNode) {
+	v.component_str(n.GetName(), getType(n), n.GetParams(), n.GetClientModifiers(), n.GetServerModifiers())
+}
+
 func (v *PrintVisitor) VisitMemcachedNode(_ Visitor, n *MemcachedNode) {
 	v.component_str(n.Name, "MemcachedNode", n.Params, n.ClientModifiers, n.ServerModifiers)
 }
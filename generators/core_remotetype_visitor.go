@@ -30,9 +30,21 @@ func (v *RemoteTypeVisitor) fixRemoteTypes(node *ServiceImplInfo) {
 	has_remote_objs := false
 	has_return_remote_objs := false
 	var base_imports []parser.ImportInfo
-	for name, fInfo := range node.Methods {
+	// Types declared outside of the specification, e.g. time.Time, need their own imports
+	pkg_imports := make(map[string]bool)
+	add_pkg_imports := func(t parser.TypeInfo) {
+		for _, pkgPath := range t.PkgPaths() {
+			if !pkg_imports[pkgPath] {
+				pkg_imports[pkgPath] = true
+				base_imports = append(base_imports, parser.ImportInfo{ImportName: "", FullName: pkgPath})
+			}
+		}
+	}
+	for _, name := range parser.SortedKeys(node.Methods) {
+		fInfo := node.Methods[name]
 		var new_args []parser.ArgInfo
 		for _, arg := range fInfo.Args {
+			add_pkg_imports(arg.Type)
 			if arg.Type.IsUserDefined() {
 				has_remote_objs = true
 				arg.Type = parser.PrependPackageName(pkgName, arg.Type)
@@ -41,6 +53,7 @@ func (v *RemoteTypeVisitor) fixRemoteTypes(node *ServiceImplInfo) {
 		}
 		var rets []parser.ArgInfo
 		for _, arg := range fInfo.Return {
+			add_pkg_imports(arg.Type)
 			if arg.Type.IsUserDefined() {
 				arg.Type = parser.PrependPackageName(pkgName, arg.Type)
 				has_return_remote_objs = true
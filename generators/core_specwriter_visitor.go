@@ -6,7 +6,6 @@ import (
 	"path"
 	"strings"
 
-	"github.com/otiai10/copy"
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
 
@@ -32,11 +31,11 @@ func (v *SpecSourceWriterVisitor) VisitMillenialNode(_ Visitor, n *MillenialNode
 	specDir := path.Join(v.curDir, "spec")
 	err := os.MkdirAll(specDir, 0755)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
-	err = copy.Copy(v.specDir, specDir)
+	err = parser.CopyDir(v.specDir, specDir)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	v.DefaultVisitor.VisitMillenialNode(v, n)
 	v.logger.Println("Ending SpecSourceWriter visit")
@@ -47,7 +46,7 @@ func (v *SpecSourceWriterVisitor) VisitDockerContainerNode(_ Visitor, n *DockerC
 	new_dir := path.Join(oldPath, strings.ToLower(n.Name))
 	err := os.MkdirAll(new_dir, 0755)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	v.curDir = new_dir
 	v.DefaultVisitor.VisitDockerContainerNode(v, n)
@@ -59,7 +58,7 @@ func (v *SpecSourceWriterVisitor) VisitProcessNode(_ Visitor, n *ProcessNode) {
 	new_dir := path.Join(oldPath, strings.ToLower(n.Name))
 	err := os.MkdirAll(new_dir, 0755)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	v.curDir = new_dir
 	v.pkgName = strings.ToLower(n.Name)
@@ -44,7 +44,7 @@ func (v *SourceCodeWriterVisitor) VisitProcessNode(_ Visitor, n *ProcessNode) {
 	new_dir := path.Join(oldPath, strings.ToLower(n.Name))
 	err := os.MkdirAll(new_dir, 0755)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	v.curDir = new_dir
 	v.pkgName = strings.ToLower(n.Name)
@@ -53,20 +53,26 @@ func (v *SourceCodeWriterVisitor) VisitProcessNode(_ Visitor, n *ProcessNode) {
 	v.curDir = oldPath
 }
 
-func (v *SourceCodeWriterVisitor) WriteFile(info *ServiceImplInfo) {
-	go_file := path.Join(v.curDir, info.Name+".go")
-	outf, err := os.OpenFile(go_file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+// Writes a generated file once it is complete
+func closeOutputFile(outf *parser.OutputFile) {
+	err := outf.Close()
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
-	defer outf.Close()
+}
+
+func (v *SourceCodeWriterVisitor) WriteFile(info *ServiceImplInfo) {
+	go_file := path.Join(v.curDir, info.Name+".go")
+	outf := parser.NewOutputFile(go_file, parser.FileMode)
+	defer closeOutputFile(outf)
+	var err error
 	_, err = outf.WriteString("// Blueprint: auto-generated by " + info.PluginName + " plugin\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	_, err = outf.WriteString("package " + v.pkgName + "\n\n")
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 	// Write imports
 	var import_string string
@@ -93,7 +99,7 @@ func (v *SourceCodeWriterVisitor) WriteFile(info *ServiceImplInfo) {
 	import_string += ")\n\n"
 	_, err = outf.WriteString(import_string)
 	if err != nil {
-		v.logger.Fatal(err)
+		parser.Abort(err)
 	}
 
 	// Write Type
@@ -106,7 +112,7 @@ func (v *SourceCodeWriterVisitor) WriteFile(info *ServiceImplInfo) {
 		type_string += "}\n"
 		_, err = outf.WriteString(type_string)
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 	}
 
@@ -114,6 +120,9 @@ func (v *SourceCodeWriterVisitor) WriteFile(info *ServiceImplInfo) {
 	if len(info.Structs) > 0 {
 		struct_string := ""
 		for _, sinfo := range info.Structs {
+			if sinfo.Doc != "" {
+				struct_string += "// " + strings.ReplaceAll(sinfo.Doc, "\n", "\n// ") + "\n"
+			}
 			struct_string += "type " + sinfo.Name + " struct {\n"
 			v.logger.Println(sinfo.Fields)
 			for _, field := range sinfo.Fields {
@@ -123,7 +132,7 @@ func (v *SourceCodeWriterVisitor) WriteFile(info *ServiceImplInfo) {
 		}
 		_, err = outf.WriteString(struct_string)
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 	}
 
@@ -150,12 +159,13 @@ func (v *SourceCodeWriterVisitor) WriteFile(info *ServiceImplInfo) {
 		func_string += "\n}\n"
 		_, err := outf.WriteString(func_string + "\n")
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 	}
 
 	// Write function informations
-	for name, method := range info.Methods {
+	for _, name := range parser.SortedKeys(info.Methods) {
+		method := info.Methods[name]
 		body := info.MethodBodies[name]
 		var arg_strings []string
 		for _, arg := range method.Args {
@@ -176,7 +186,7 @@ func (v *SourceCodeWriterVisitor) WriteFile(info *ServiceImplInfo) {
 		func_string += "\n}\n"
 		_, err := outf.WriteString(func_string + "\n")
 		if err != nil {
-			v.logger.Fatal(err)
+			parser.Abort(err)
 		}
 	}
 }
@@ -188,15 +198,15 @@ func (v *SourceCodeWriterVisitor) VisitFuncServiceNode(_ Visitor, n *FuncService
 	}
 
 	// Write Client Nodes
-	for _, param_nodes := range n.ParamClientNodes {
-		for _, client_node := range param_nodes {
+	for _, name := range parser.SortedKeys(n.ParamClientNodes) {
+		for _, client_node := range n.ParamClientNodes[name] {
 			v.WriteFile(client_node)
 		}
 	}
 
 	// Write Modifier Instance Nodes
-	for _, param_nodes := range n.ModifierClientNodes {
-		for _, client_node := range param_nodes {
+	for _, name := range parser.SortedKeys(n.ModifierClientNodes) {
+		for _, client_node := range n.ModifierClientNodes[name] {
 			v.WriteFile(client_node)
 		}
 	}
@@ -205,14 +215,14 @@ func (v *SourceCodeWriterVisitor) VisitFuncServiceNode(_ Visitor, n *FuncService
 func (v *SourceCodeWriterVisitor) VisitQueueServiceNode(_ Visitor, n *QueueServiceNode) {
 	v.logger.Println("Generating source code files for", n.Name)
 
-	for _, param_nodes := range n.ParamClientNodes {
-		for _, client_node := range param_nodes {
+	for _, name := range parser.SortedKeys(n.ParamClientNodes) {
+		for _, client_node := range n.ParamClientNodes[name] {
 			v.WriteFile(client_node)
 		}
 	}
 
-	for _, param_nodes := range n.ModifierClientNodes {
-		for _, client_node := range param_nodes {
+	for _, name := range parser.SortedKeys(n.ModifierClientNodes) {
+		for _, client_node := range n.ModifierClientNodes[name] {
 			v.WriteFile(client_node)
 		}
 	}
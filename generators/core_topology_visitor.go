@@ -0,0 +1,373 @@
+package generators
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of instances in a Topology
+const (
+	TopologyService   = "service"   // Services of the specification
+	TopologyBackend   = "backend"   // Caches, databases and queues
+	TopologyComponent = "component" // Tracers, metric collectors, load balancers and registries
+)
+
+// Topology is the deployed structure of a compiled application: containers and processes with the instances they
+// host, and the calls between the instances.
+type Topology struct {
+	Name     string
+	Clusters []*TopologyCluster
+	Nodes    []*TopologyNode // Instances that are not placed in any container
+	Edges    []TopologyEdge
+}
+
+// A container or a process
+type TopologyCluster struct {
+	Name     string
+	Type     string // e.g. DockerContainer or Process
+	Clusters []*TopologyCluster
+	Nodes    []*TopologyNode
+}
+
+// A service instance or a backend choice
+type TopologyNode struct {
+	Name string
+	Type string // Implementation or choice, e.g. LeafServiceImpl or RedisCache
+	Kind string
+}
+
+// A call from one instance to another. Edges of dependencies that are arguments of an instance carry the client
+// modifier chain of the call, edges of dependencies that are arguments of a modifier carry the name of the modifier.
+type TopologyEdge struct {
+	From      string
+	To        string
+	Modifiers []string
+	Modifier  bool // The dependency is an argument of a modifier, e.g. the tracer of a TracerModifier
+}
+
+type TopologyVisitor struct {
+	DefaultVisitor
+	logger      *log.Logger
+	modregistry *ModifierRegistry
+	Topology    *Topology
+	clusters    []*TopologyCluster
+	instances   map[string]*TopologyNode
+	clientMods  map[string][]Modifier // Client modifiers declared by every instance with WithClient
+	deps        []topologyDependency
+}
+
+// A dependency of an instance, resolved once every instance has been visited
+type topologyDependency struct {
+	from      string
+	to        string
+	modifiers []Modifier
+	modifier  string
+}
+
+func NewTopologyVisitor(logger *log.Logger, modregistry *ModifierRegistry, name string) *TopologyVisitor {
+	return &TopologyVisitor{logger: logger, modregistry: modregistry, Topology: &Topology{Name: name}, instances: make(map[string]*TopologyNode), clientMods: make(map[string][]Modifier)}
+}
+
+func (v *TopologyVisitor) VisitMillenialNode(_ Visitor, n *MillenialNode) {
+	v.logger.Println("Building the topology")
+	v.DefaultVisitor.VisitMillenialNode(v, n)
+	for _, dep := range v.deps {
+		v.addEdge(dep)
+	}
+}
+
+func (v *TopologyVisitor) visitCluster(name string, typeName string, children []Node) {
+	cluster := &TopologyCluster{Name: name, Type: typeName}
+	if len(v.clusters) == 0 {
+		v.Topology.Clusters = append(v.Topology.Clusters, cluster)
+	} else {
+		parent := v.clusters[len(v.clusters)-1]
+		parent.Clusters = append(parent.Clusters, cluster)
+	}
+	v.clusters = append(v.clusters, cluster)
+	for _, child := range children {
+		child.Accept(v)
+	}
+	v.clusters = v.clusters[:len(v.clusters)-1]
+}
+
+func (v *TopologyVisitor) VisitAnsibleContainerNode(_ Visitor, n *AnsibleContainerNode) {
+	v.visitCluster(n.Name, "AnsibleContainer", n.Children)
+}
+
+func (v *TopologyVisitor) VisitDockerContainerNode(_ Visitor, n *DockerContainerNode) {
+	v.visitCluster(n.Name, "DockerContainer", n.Children)
+}
+
+func (v *TopologyVisitor) VisitKubernetesContainerNode(_ Visitor, n *KubernetesContainerNode) {
+	v.visitCluster(n.Name, "KubernetesContainer", n.Children)
+}
+
+func (v *TopologyVisitor) VisitNoOpContainerNode(_ Visitor, n *NoOpContainerNode) {
+	v.visitCluster(n.Name, "Container", n.Children)
+}
+
+func (v *TopologyVisitor) VisitProcessNode(_ Visitor, n *ProcessNode) {
+	v.visitCluster(n.Name, "Process", n.Children)
+}
+
+// Returns the instances passed as arguments
+func instanceDependencies(params []Parameter) map[string]Dependency {
+	dependencies := make(map[string]Dependency)
+	for _, param := range params {
+		if instance, ok := param.(*InstanceParameter); ok {
+			dependencies[instance.Name] = Dependency{InstanceName: instance.Name, ClientModifiers: instance.ClientModifiers}
+		}
+	}
+	return dependencies
+}
+
+func (v *TopologyVisitor) addInstance(name string, typeName string, kind string, deps map[string]Dependency, clientModifiers []Modifier, serverModifiers []Modifier) {
+	node := &TopologyNode{Name: name, Type: typeName, Kind: kind}
+	if len(v.clusters) == 0 {
+		v.Topology.Nodes = append(v.Topology.Nodes, node)
+	} else {
+		cluster := v.clusters[len(v.clusters)-1]
+		cluster.Nodes = append(cluster.Nodes, node)
+	}
+	v.instances[name] = node
+	v.clientMods[name] = clientModifiers
+	var depNames []string
+	for depName := range deps {
+		depNames = append(depNames, depName)
+	}
+	sort.Strings(depNames)
+	for _, depName := range depNames {
+		v.deps = append(v.deps, topologyDependency{from: name, to: depName, modifiers: deps[depName].ClientModifiers})
+	}
+	// Instances used by modifiers, e.g. tracers and metric collectors, are called by the modified instance
+	for _, modifier := range append(append([]Modifier{}, serverModifiers...), clientModifiers...) {
+		for _, param := range modifier.GetParams() {
+			if instance, ok := param.(*InstanceParameter); ok {
+				v.deps = append(v.deps, topologyDependency{from: name, to: instance.Name, modifier: modifier.GetName()})
+			}
+		}
+	}
+}
+
+// Returns the client modifiers of a call: the ones declared by the callee, replaced or extended by the ones given
+// with the argument, in the order in which they are applied
+func (v *TopologyVisitor) callModifiers(to string, overrides []Modifier) []Modifier {
+	modifiers := append([]Modifier{}, v.clientMods[to]...)
+	for _, modifier := range overrides {
+		found := false
+		for idx, def_modifier := range modifiers {
+			if def_modifier.GetName() == modifier.GetName() {
+				modifiers[idx] = modifier
+				found = true
+			}
+		}
+		if !found {
+			modifiers = append(modifiers, modifier)
+		}
+	}
+	return v.modregistry.OrderModifiers(modifiers)
+}
+
+func (v *TopologyVisitor) addEdge(dep topologyDependency) {
+	if _, ok := v.instances[dep.to]; !ok {
+		v.logger.Println("Topology: unknown instance", dep.to, "used by", dep.from)
+		return
+	}
+	edge := TopologyEdge{From: dep.from, To: dep.to}
+	if dep.modifier != "" {
+		edge.Modifiers = []string{dep.modifier}
+		edge.Modifier = true
+	} else {
+		for _, modifier := range v.callModifiers(dep.to, dep.modifiers) {
+			edge.Modifiers = append(edge.Modifiers, modifier.GetName())
+		}
+	}
+	for _, existing := range v.Topology.Edges {
+		if existing.From == edge.From && existing.To == edge.To && existing.Modifier == edge.Modifier && strings.Join(existing.Modifiers, ",") == strings.Join(edge.Modifiers, ",") {
+			return
+		}
+	}
+	v.Topology.Edges = append(v.Topology.Edges, edge)
+}
+
+func (v *TopologyVisitor) VisitFuncServiceNode(_ Visitor, n *FuncServiceNode) {
+	v.addInstance(n.Name, n.Type, TopologyService, n.GetDependencies(), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitQueueServiceNode(_ Visitor, n *QueueServiceNode) {
+	v.addInstance(n.Name, n.Type, TopologyService, n.GetDependencies(), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitLoadBalancerNode(_ Visitor, n *LoadBalancerNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyComponent, n.GetDependencies(), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitJaegerNode(_ Visitor, n *JaegerNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyComponent, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitZipkinNode(_ Visitor, n *ZipkinNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyComponent, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitLocalMetricNode(_ Visitor, n *LocalMetricNode) {
+	v.addInstance(n.Name, "LocalMetricCollector", TopologyComponent, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitXTraceNode(_ Visitor, n *XTraceNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyComponent, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitMemcachedNode(_ Visitor, n *MemcachedNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyBackend, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitRedisNode(_ Visitor, n *RedisNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyBackend, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitMongoDBNode(_ Visitor, n *MongoDBNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyBackend, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitRabbitMQNode(_ Visitor, n *RabbitMQNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyBackend, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitMySqlDBNode(_ Visitor, n *MySqlDBNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyBackend, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitConsulNode(_ Visitor, n *ConsulNode) {
+	v.addInstance(n.Name, n.TypeName, TopologyComponent, instanceDependencies(n.Params), n.ClientModifiers, n.ServerModifiers)
+}
+
+func (v *TopologyVisitor) VisitComponentNode(_ Visitor, n ComponentNode) {
+	v.addInstance(n.GetName(), n.GetTypeName(), TopologyComponent, instanceDependencies(n.GetParams()), n.GetClientModifiers(), n.GetServerModifiers())
+}
+
+func (n *TopologyNode) label() string {
+	if n.Type == "" {
+		return n.Name
+	}
+	return n.Name + "\n" + n.Type
+}
+
+func (c *TopologyCluster) label() string {
+	return c.Name + " (" + c.Type + ")"
+}
+
+// DOT renders the topology in the Graphviz DOT language
+func (t *Topology) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph " + strconv.Quote(t.Name) + " {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	clusterID := 0
+	var writeNode func(indent string, n *TopologyNode)
+	writeNode = func(indent string, n *TopologyNode) {
+		attrs := "label=" + strconv.Quote(n.label())
+		switch n.Kind {
+		case TopologyBackend:
+			attrs += ", shape=cylinder"
+		case TopologyComponent:
+			attrs += ", shape=ellipse"
+		}
+		b.WriteString(indent + strconv.Quote(n.Name) + " [" + attrs + "];\n")
+	}
+	var writeCluster func(indent string, c *TopologyCluster)
+	writeCluster = func(indent string, c *TopologyCluster) {
+		b.WriteString(fmt.Sprintf("%ssubgraph cluster_%d {\n", indent, clusterID))
+		clusterID += 1
+		b.WriteString(indent + "\tlabel=" + strconv.Quote(c.label()) + ";\n")
+		for _, child := range c.Clusters {
+			writeCluster(indent+"\t", child)
+		}
+		for _, n := range c.Nodes {
+			writeNode(indent+"\t", n)
+		}
+		b.WriteString(indent + "}\n")
+	}
+	for _, c := range t.Clusters {
+		writeCluster("\t", c)
+	}
+	for _, n := range t.Nodes {
+		writeNode("\t", n)
+	}
+	for _, e := range t.Edges {
+		var attrs []string
+		if len(e.Modifiers) > 0 {
+			attrs = append(attrs, "label="+strconv.Quote(strings.Join(e.Modifiers, ", ")))
+		}
+		if e.Modifier {
+			attrs = append(attrs, "style=dashed")
+		}
+		line := "\t" + strconv.Quote(e.From) + " -> " + strconv.Quote(e.To)
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		b.WriteString(line + ";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Escapes a label for Mermaid, which doesn't support backslash escapes in quoted strings
+func mermaidLabel(s string) string {
+	s = strings.ReplaceAll(s, "\"", "#quot;")
+	return "\"" + strings.ReplaceAll(s, "\n", "<br/>") + "\""
+}
+
+// Mermaid renders the topology as a Mermaid flowchart
+func (t *Topology) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := make(map[string]string)
+	clusterID := 0
+	var writeNode func(indent string, n *TopologyNode)
+	writeNode = func(indent string, n *TopologyNode) {
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[n.Name] = id
+		switch n.Kind {
+		case TopologyBackend:
+			b.WriteString(indent + id + "[(" + mermaidLabel(n.label()) + ")]\n")
+		case TopologyComponent:
+			b.WriteString(indent + id + "([" + mermaidLabel(n.label()) + "])\n")
+		default:
+			b.WriteString(indent + id + "[" + mermaidLabel(n.label()) + "]\n")
+		}
+	}
+	var writeCluster func(indent string, c *TopologyCluster)
+	writeCluster = func(indent string, c *TopologyCluster) {
+		b.WriteString(fmt.Sprintf("%ssubgraph c%d[%s]\n", indent, clusterID, mermaidLabel(c.label())))
+		clusterID += 1
+		for _, child := range c.Clusters {
+			writeCluster(indent+"\t", child)
+		}
+		for _, n := range c.Nodes {
+			writeNode(indent+"\t", n)
+		}
+		b.WriteString(indent + "end\n")
+	}
+	for _, c := range t.Clusters {
+		writeCluster("\t", c)
+	}
+	for _, n := range t.Nodes {
+		writeNode("\t", n)
+	}
+	for _, e := range t.Edges {
+		arrow := "-->"
+		if e.Modifier {
+			arrow = "-.->"
+		}
+		if len(e.Modifiers) > 0 {
+			arrow += "|" + mermaidLabel(strings.Join(e.Modifiers, ", ")) + "|"
+		}
+		b.WriteString("\t" + ids[e.From] + " " + arrow + " " + ids[e.To] + "\n")
+	}
+	return b.String()
+}
@@ -16,7 +16,7 @@ func copyMap(m map[string]parser.FuncInfo) map[string]parser.FuncInfo {
 		copy(args, v.Args)
 		rets := make([]parser.ArgInfo, len(v.Return))
 		copy(rets, v.Return)
-		cp[k] = parser.FuncInfo{Name: v.Name, Args: args, Return: rets, Public: true}
+		cp[k] = parser.FuncInfo{Name: v.Name, Args: args, Return: rets, Public: true, Pos: v.Pos}
 	}
 
 	return cp
@@ -47,11 +47,16 @@ func copyServiceImplInfo(siInfo *ServiceImplInfo) *ServiceImplInfo {
 	new_info.BaseImports = make([]parser.ImportInfo, len(siInfo.BaseImports))
 	copy(new_info.BaseImports, siInfo.BaseImports)
 	new_info.PluginName = siInfo.PluginName
+	new_info.Generator = siInfo.Generator
 	return &new_info
 }
 
 func combineMethodInfo(funcInfo *parser.FuncInfo, prev_node *ServiceImplInfo) {
 	funcInfo.Args = append(funcInfo.Args, prev_node.NextNodeMethodArgs...)
+	if len(funcInfo.Return) == 0 {
+		funcInfo.Return = append(funcInfo.Return, prev_node.NextNodeMethodReturn...)
+		return
+	}
 	last_return := funcInfo.Return[len(funcInfo.Return)-1]
 	funcInfo.Return = append(funcInfo.Return[:len(funcInfo.Return)-1], prev_node.NextNodeMethodReturn...)
 	funcInfo.Return = append(funcInfo.Return, last_return)
@@ -49,6 +49,10 @@ type Visitor interface {
 	VisitRabbitMQNode(v Visitor, n *RabbitMQNode)
 	VisitMySqlDBNode(v Visitor, n *MySqlDBNode)
 	VisitConsulNode(v Visitor, n *ConsulNode)
+
+	// Nodes of plugins that are registered with RegisterModifier and RegisterComponent
+	VisitModifier(v Visitor, n Modifier)
+	VisitComponentNode(v Visitor, n ComponentNode)
 }
 
 type DefaultVisitor struct{}
@@ -332,3 +336,21 @@ func (_ *DefaultVisitor) VisitConsulNode(v Visitor, n *ConsulNode) {
 		node.Accept(v)
 	}
 }
+
+func (_ *DefaultVisitor) VisitModifier(v Visitor, n Modifier) {
+	for _, node := range n.GetParams() {
+		node.Accept(v)
+	}
+}
+
+func (_ *DefaultVisitor) VisitComponentNode(v Visitor, n ComponentNode) {
+	for _, node := range n.GetParams() {
+		node.Accept(v)
+	}
+	for _, node := range n.GetClientModifiers() {
+		node.Accept(v)
+	}
+	for _, node := range n.GetServerModifiers() {
+		node.Accept(v)
+	}
+}
@@ -0,0 +1,178 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+// A wrapper generates the code of a modifier that wraps every method of a client, or of a service, in a call of an
+// object of the standard library, e.g. Retry wraps the calls of a client in stdlib.Retrier.Do. The object is created
+// from the options of the modifier. The constructor of a generated server creates it, a generated client has a
+// second constructor that creates it, so that the clients created by a ClientPool share it. Both the constructors
+// and the methods are generated from templates, which are executed with wrapperData.
+type wrapper struct {
+	Plugin      string // Name of the modifier in the wiring file
+	Suffix      string // Appended to the name of the service to name the generated client or server, e.g. Retrier
+	Receiver    string // Receiver of the generated methods
+	Field       string // Field holding the object of the standard library
+	Type        string // Type of the object, e.g. stdlib.Retrier
+	Constructor string // Template of the constructor of a server, or of the object of a client, e.g. retry.retrier
+	Method      string // Template of the methods, e.g. retry.client_method
+}
+
+// Variable holding a result of a method in the templates of a wrapper
+type wrapperResult struct {
+	Name string
+	Type string
+}
+
+// Data of the templates of a wrapper
+type wrapperData struct {
+	Name        string          // Name of the generated client or server, not set for the methods
+	Receiver    string          // Receiver of the generated methods
+	Next        string          // Field holding the next client, or the wrapped service
+	Instance    string          // Name of the instance, which names the metrics of the object
+	Options     []string        // Keyword arguments of the modifier, passed to the constructor as strings
+	Method      parser.FuncInfo // Method of the service, not set for the constructor
+	Context     string          // Context of the call, context.Background() for methods without a context
+	ContextArg  string          // Name of the context argument of Method, _ for methods without a context
+	ArgNames    []string        // Names of the arguments of Method
+	Results     []wrapperResult // Variables holding the results of Method, named apart from its arguments
+	ResultNames []string
+	Err         string // Variable holding the error returned by Method
+}
+
+// Registers the default text of the templates of the constructor and of the methods
+func (w *wrapper) register(constructor string, method string) {
+	parser.RegisterTemplate(w.Constructor, constructor)
+	parser.RegisterTemplate(w.Method, method)
+}
+
+func (w *wrapper) generateMethodBody(next string, method parser.FuncInfo) (string, error) {
+	data := wrapperData{Receiver: w.Receiver, Next: next, Method: method, Context: "context.Background()", ContextArg: "_"}
+	for idx, arg := range method.Args {
+		if idx == 0 && arg.Type.String() == "context.Context" {
+			data.Context = arg.Name
+			data.ContextArg = arg.Name
+		}
+		data.ArgNames = append(data.ArgNames, arg.Name)
+	}
+	for idx, ret := range method.Return {
+		ret_name := method.LocalName(fmt.Sprintf("%s_ret_%d", w.Receiver, idx))
+		data.Results = append(data.Results, wrapperResult{Name: ret_name, Type: ret.String()})
+		data.ResultNames = append(data.ResultNames, ret_name)
+		data.Err = ret_name
+	}
+	return parser.ExecuteTemplate(w.Method, data)
+}
+
+// Returns the methods of prev_node wrapped by the object. The object decides from the error of a method whether the
+// call failed, so every method has to return an error as its last result. Checks that the methods with options of
+// their own exist.
+func (w *wrapper) wrapMethods(prev_node *ServiceImplInfo, next string, combine bool, methods []string) (map[string]parser.FuncInfo, map[string]string, error) {
+	for _, method := range methods {
+		if _, ok := prev_node.Methods[method]; !ok {
+			return nil, nil, fmt.Errorf("%s: %s has no method %s", w.Plugin, prev_node.BaseName, method)
+		}
+	}
+	bodies := make(map[string]string)
+	newMethods := copyMap(prev_node.Methods)
+	for _, name := range parser.SortedKeys(newMethods) {
+		method := newMethods[name]
+		if last := len(method.Return) - 1; last < 0 || method.Return[last].Type.BaseType != parser.BASIC || method.Return[last].Type.Detail.TypeName != parser.ERROR {
+			return nil, nil, methodErrorf(method, "%s can't wrap %s.%s, which doesn't return an error as its last result", w.Plugin, prev_node.BaseName, name)
+		}
+		if combine {
+			combineMethodInfo(&method, prev_node)
+		}
+		body, err := w.generateMethodBody(next, method)
+		if err != nil {
+			return nil, nil, err
+		}
+		bodies[name] = body
+		newMethods[name] = method
+	}
+	return newMethods, bodies, nil
+}
+
+func (w *wrapper) getImports() []parser.ImportInfo {
+	var imports []parser.ImportInfo
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "context"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "log"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: MODULE_ROOT + "/stdlib"})
+	return imports
+}
+
+func (w *wrapper) getFields(next string, next_node_name string) []parser.ArgInfo {
+	var fields []parser.ArgInfo
+	fields = append(fields, parser.GetPointerArg(next, next_node_name))
+	fields = append(fields, parser.GetPointerArg(w.Field, w.Type))
+	return fields
+}
+
+// Returns the arguments that take the options of the modifier, and the names of the options
+func (w *wrapper) getOptions(params []Parameter) ([]parser.ArgInfo, []string) {
+	var args []parser.ArgInfo
+	var options []string
+	for _, param := range params {
+		switch ptype := param.(type) {
+		case *ValueParameter:
+			args = append(args, parser.GetBasicArg(ptype.KeywordName, "string"))
+			options = append(options, ptype.KeywordName)
+		}
+	}
+	return args, options
+}
+
+func (w *wrapper) getConstructor(name string, instance string, next string, next_node *ServiceImplInfo, params []Parameter) (parser.FuncInfo, string, error) {
+	func_name := "New" + name
+	ret_args := []parser.ArgInfo{parser.GetPointerArg("", name)}
+	option_args, options := w.getOptions(params)
+	args := append([]parser.ArgInfo{parser.GetPointerArg(next, next_node.Name)}, option_args...)
+	body, err := parser.ExecuteTemplate(w.Constructor, wrapperData{Name: name, Receiver: w.Receiver, Next: next, Instance: instance, Options: options})
+	return parser.FuncInfo{Name: func_name, Args: args, Return: ret_args}, body, err
+}
+
+// Returns the client that wraps the calls of the next client, methods are the methods with options of their own
+func (w *wrapper) modifyClient(prev_node *ServiceImplInfo, methods []string) (*ServiceImplInfo, error) {
+	newMethods, bodies, err := w.wrapMethods(prev_node, "client", true, methods)
+	if err != nil {
+		return nil, err
+	}
+	next_node_args := []parser.ArgInfo{}
+	name := prev_node.BaseName + w.Suffix
+	return &ServiceImplInfo{Name: name, ReceiverName: w.Receiver, Methods: newMethods, MethodBodies: bodies, BaseName: prev_node.BaseName, Imports: w.getImports(), InstanceName: prev_node.InstanceName, NextNodeMethodArgs: next_node_args}, nil
+}
+
+// Adds the constructors of a client. The second one creates the object, which the clients created by a ClientPool
+// share, and the first one takes it.
+func (w *wrapper) addClientConstructor(node *ServiceImplInfo, next_node *ServiceImplInfo, params []Parameter) {
+	constructor := parser.FuncInfo{Name: "New" + node.Name, Args: []parser.ArgInfo{parser.GetPointerArg("client", next_node.Name), parser.GetPointerArg(w.Field, w.Type)}, Return: []parser.ArgInfo{parser.GetPointerArg("", node.Name)}}
+	option_args, options := w.getOptions(params)
+	object_constructor := parser.FuncInfo{Name: "New" + node.Name + strings.ToUpper(w.Field[:1]) + w.Field[1:], Args: option_args, Return: []parser.ArgInfo{parser.GetPointerArg("", w.Type)}}
+	object_body, err := parser.ExecuteTemplate(w.Constructor, wrapperData{Name: node.Name, Receiver: w.Receiver, Instance: node.InstanceName, Options: options})
+	if err != nil {
+		parser.Abort(err)
+	}
+	node.MethodBodies[constructor.Name] = "return &" + node.Name + "{client: client, " + w.Field + ": " + w.Field + "}\n"
+	node.MethodBodies[object_constructor.Name] = object_body
+	node.Constructors = []parser.FuncInfo{constructor, object_constructor}
+	node.Fields = w.getFields("client", next_node.Name)
+}
+
+// Returns the server that wraps the calls to the service, methods are the methods with options of their own
+func (w *wrapper) modifyServer(prev_node *ServiceImplInfo, methods []string, params []Parameter) (*ServiceImplInfo, error) {
+	newMethods, bodies, err := w.wrapMethods(prev_node, "service", false, methods)
+	if err != nil {
+		return nil, err
+	}
+	name := prev_node.BaseName + w.Suffix
+	constructor, body, err := w.getConstructor(name, prev_node.InstanceName, "service", prev_node, params)
+	if err != nil {
+		return nil, err
+	}
+	bodies[constructor.Name] = body
+	return &ServiceImplInfo{Name: name, ReceiverName: w.Receiver, Methods: newMethods, MethodBodies: bodies, BaseName: prev_node.BaseName, Imports: w.getImports(), Fields: w.getFields("service", prev_node.Name), Constructors: []parser.FuncInfo{constructor}, InstanceName: prev_node.InstanceName, BaseImports: prev_node.BaseImports}, nil
+}
@@ -2,6 +2,7 @@ package deploy
 
 import (
 	"errors"
+	"sort"
 )
 
 type DeployInfo struct {
@@ -16,12 +17,23 @@ type DeployInfo struct {
 	Command     []string
 	Entrypoint  []string
 	Volumes     []string
+	DependsOn   []string // Names of the containers that have to be started before this one
 }
 
 func NewDeployInfo() *DeployInfo {
 	return &DeployInfo{EnvVars: make(map[string]string), PublicPorts: make(map[int]int)}
 }
 
+// Returns the host ports of PublicPorts in increasing order
+func (d *DeployInfo) SortedPublicPorts() []int {
+	var ports []int
+	for port := range d.PublicPorts {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
+}
+
 type NoOpDeployerGenerator struct{}
 
 func (n *NoOpDeployerGenerator) AddService(name string, depInfo *DeployInfo) {}
@@ -51,6 +63,11 @@ func GetDepGenFactory() *DeployerGeneratorFactory {
 	return factory
 }
 
+// ResetDepGenFactory discards the deployer generators and the services added to them
+func ResetDepGenFactory() {
+	factory = nil
+}
+
 func (df *DeployerGeneratorFactory) GetGenerator(framework string) (DeployerGenerator, error) {
 	if gen, ok := df.Generators[framework]; !ok {
 		if gen_func, ok2 := df.GeneratorFuncs[framework]; ok2 {
@@ -2,6 +2,8 @@ package deploy
 
 import (
 	"log"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
 
 const (
@@ -45,7 +47,7 @@ func (pa *PortAuthority) GetAvailablePort(hostname string, port int) int {
 	for p >= MIN_PORT_NUMBER && p <= MAX_PORT_NUMBER {
 		if p == port {
 			// Well, we have circled all the way
-			pa.logger.Fatal("Hostname:", hostname, "has no free port available for assignment")
+			parser.Abort("Hostname:", hostname, "has no free port available for assignment")
 		}
 		if pa.isPortAvailable(hostname, p) {
 			break
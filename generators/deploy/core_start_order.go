@@ -0,0 +1,41 @@
+package deploy
+
+import "sort"
+
+// StartOrder orders the keys and dependencies of deps so that every name comes after the names it depends on. Names
+// that don't depend on each other are ordered by name, so the order is the same on every run. A dependency cycle
+// can't be ordered, it is broken at the dependency that closes it.
+func StartOrder(deps map[string][]string) []string {
+	names := make(map[string]bool)
+	for name, targets := range deps {
+		names[name] = true
+		for _, target := range targets {
+			names[target] = true
+		}
+	}
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var order []string
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		targets := append([]string{}, deps[name]...)
+		sort.Strings(targets)
+		for _, target := range targets {
+			visit(target)
+		}
+		order = append(order, name)
+	}
+	for _, name := range sorted {
+		visit(name)
+	}
+	return order
+}
@@ -1,12 +1,13 @@
 package deploy
 
 import (
-	"fmt"
+	"io/ioutil"
+	"log"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 
-	cp "github.com/otiai10/copy"
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
 
@@ -14,7 +15,7 @@ import (
 type InstanceDepInfo struct {
 	*DeployInfo
 	ThirdParty bool
-	// Name string
+	Name       string
 }
 
 //-----------------------------------------------------------------
@@ -29,6 +30,8 @@ type AnsibleDeployerGenerator struct {
 	outDir          string
 	instanceData    []InstanceDepInfo
 	inventoryData   []parser.Node
+	hostOrder       []string // Hostnames in the order in which their containers are started
+	logger          *log.Logger
 }
 
 //-----------------------------------------------------------------
@@ -64,19 +67,24 @@ type Host struct {
 type HostMap map[string]*Host
 
 func NewAnsibleDeployerGenerator() DeployerGenerator {
-	return &AnsibleDeployerGenerator{}
+	return &AnsibleDeployerGenerator{logger: log.New(ioutil.Discard, "", 0)}
 }
 
 func (adg *AnsibleDeployerGenerator) SetInventory(inventory []parser.Node) {
 	adg.inventoryData = inventory
 }
 
+// SetLogger sets the logger of the compiler stage that generates the playbooks
+func (adg *AnsibleDeployerGenerator) SetLogger(logger *log.Logger) {
+	adg.logger = logger
+}
+
 func (adg *AnsibleDeployerGenerator) AddService(name string, depInfo *DeployInfo) {
 	depInfo.ImageName = name
 	adg.instanceData = append(adg.instanceData, InstanceDepInfo{
 		DeployInfo: depInfo,
 		ThirdParty: false,
-		// Name: name,
+		Name:       name,
 	})
 }
 
@@ -84,7 +92,7 @@ func (adg *AnsibleDeployerGenerator) AddChoice(name string, depInfo *DeployInfo)
 	adg.instanceData = append(adg.instanceData, InstanceDepInfo{
 		DeployInfo: depInfo,
 		ThirdParty: true,
-		// Name: name,
+		Name:       name,
 	})
 }
 
@@ -96,24 +104,13 @@ func (adg *AnsibleDeployerGenerator) WriteFile(name, data string) error {
 	}
 
 	outfile := path.Join(adg.outDir, name)
-	outf, err := os.OpenFile(outfile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		return err
-	}
-
-	_, err = outf.WriteString(data)
-
-	return err
+	return parser.WriteFile(outfile, []byte(data), parser.FileMode)
 }
 
 func (adg *AnsibleDeployerGenerator) CreateInventory(hosts HostMap) error {
-	fmt.Println("Generating Inventory..")
+	adg.logger.Println("Generating Inventory..")
 	adg.inventory += "[all]\n"
-	nameList := []string{}
-
-	for k, _ := range hosts {
-		nameList = append(nameList, k)
-	}
+	nameList := adg.hostOrder
 
 	var registryNode string
 	for _, v := range nameList {
@@ -146,7 +143,7 @@ func (adg *AnsibleDeployerGenerator) CreateInventory(hosts HostMap) error {
 
 func (adg *AnsibleDeployerGenerator) CreateImagePlaybook(hosts HostMap) error {
 	prefix := "  "
-	fmt.Println("Generating Images Playbook..")
+	adg.logger.Println("Generating Images Playbook..")
 	adg.imagePlaybook += "---\n"
 	adg.imagePlaybook += "- name: Building..\n"
 	adg.imagePlaybook += prefix + "debug:\n" + prefix + prefix + "msg: \"Now building: {{image.name}} at {{image.rel_path}}\"\n\n"
@@ -171,7 +168,7 @@ func (adg *AnsibleDeployerGenerator) CreateBuildPlaybook(images []BuildImage) er
 	// ! Prepare build playbook
 
 	prefix := "  "
-	fmt.Println("Generating Builds Playbook..")
+	adg.logger.Println("Generating Builds Playbook..")
 	adg.buildPlaybook += "---\n"
 	adg.buildPlaybook += "- hosts: registry\n"
 	adg.buildPlaybook += prefix + "vars:\n"
@@ -217,7 +214,7 @@ func (adg *AnsibleDeployerGenerator) CreateBuildPlaybook(images []BuildImage) er
 func (adg *AnsibleDeployerGenerator) CreateMainPlaybook(hosts HostMap, noBuild bool) error {
 
 	prefix := "  "
-	fmt.Println("Generating Main Playbook..")
+	adg.logger.Println("Generating Main Playbook..")
 	adg.mainPlaybook += "---\n"
 
 	if !noBuild {
@@ -225,7 +222,7 @@ func (adg *AnsibleDeployerGenerator) CreateMainPlaybook(hosts HostMap, noBuild b
 		adg.mainPlaybook += prefix + "import_playbook: build.yaml\n\n"
 	}
 
-	for addr, _ := range hosts {
+	for _, addr := range adg.hostOrder {
 		//create entry for playbook with name 'addr.yaml'
 		adg.mainPlaybook += "- name: Running " + addr + "\n"
 		adg.mainPlaybook += prefix + "import_playbook: " + addr + ".yaml\n"
@@ -236,11 +233,11 @@ func (adg *AnsibleDeployerGenerator) CreateMainPlaybook(hosts HostMap, noBuild b
 
 func (adg *AnsibleDeployerGenerator) CreateRunnerPlaybooks(hosts HostMap) error {
 
-	fmt.Println("Generating runners..")
+	adg.logger.Println("Generating runners..")
 	prefix := "  "
 	var err error
-	// //! Iteration order in map is not defined; that's why we need to maintain host Indexes explicitly
-	for addr, h := range hosts {
+	for _, addr := range adg.hostOrder {
+		h := hosts[addr]
 		//create entry for playbook with name 'addr.yaml'
 
 		playbook := "---\n"
@@ -311,13 +308,48 @@ func (adg *AnsibleDeployerGenerator) CreateRunnerPlaybooks(hosts HostMap) error
 	return nil
 }
 
+// Returns the instances ordered so that every instance comes after the instances it depends on
+func (adg *AnsibleDeployerGenerator) startOrder() []InstanceDepInfo {
+	deps := make(map[string][]string)
+	for _, instance := range adg.instanceData {
+		deps[instance.Name] = instance.DependsOn
+	}
+	rank := make(map[string]int)
+	for idx, name := range StartOrder(deps) {
+		rank[name] = idx
+	}
+	ordered := append([]InstanceDepInfo{}, adg.instanceData...)
+	sort.SliceStable(ordered, func(i, j int) bool { return rank[ordered[i].Name] < rank[ordered[j].Name] })
+	return ordered
+}
+
+// Orders the hosts so that a host comes after the hosts running the instances its instances depend on
+func hostStartOrder(instances []InstanceDepInfo) []string {
+	hostnames := make(map[string]string)
+	for _, instance := range instances {
+		hostnames[instance.Name] = instance.Hostname
+	}
+	deps := make(map[string][]string)
+	for _, instance := range instances {
+		if _, ok := deps[instance.Hostname]; !ok {
+			deps[instance.Hostname] = nil
+		}
+		for _, dep := range instance.DependsOn {
+			if hostname, ok := hostnames[dep]; ok && hostname != instance.Hostname {
+				deps[instance.Hostname] = append(deps[instance.Hostname], hostname)
+			}
+		}
+	}
+	return StartOrder(deps)
+}
+
 func (adg *AnsibleDeployerGenerator) CopySetupFiles() error {
 
-	fmt.Println("Copying setup files..")
+	adg.logger.Println("Copying setup files..")
 	pwd, _ := os.Getwd()
 	setupFilesPath := path.Join(pwd, "generators/deploy/cluster_setup")
 	outPath := path.Join(adg.outDir, "cluster_setup")
-	err := cp.Copy(setupFilesPath, outPath)
+	err := parser.CopyDir(setupFilesPath, outPath)
 	return err
 }
 
@@ -331,7 +363,7 @@ func (adg *AnsibleDeployerGenerator) GenerateConfigFiles(out_dir string) error {
 	}
 
 	adg.srcDir = out_dir
-	plac := adg.instanceData
+	plac := adg.startOrder()
 
 	hosts := make(HostMap)
 	images := []BuildImage{}
@@ -349,15 +381,16 @@ func (adg *AnsibleDeployerGenerator) GenerateConfigFiles(out_dir string) error {
 
 		var ports []Port
 		var envs []Env
-		for h, c := range pl.PublicPorts {
-
+		for _, h := range pl.SortedPublicPorts() {
+			c := pl.PublicPorts[h]
 			ports = append(ports, Port{
 				Host:      h,
 				Container: c,
 			})
 		}
 
-		for e1, e2 := range pl.EnvVars {
+		for _, e1 := range parser.SortedKeys(pl.EnvVars) {
+			e2 := pl.EnvVars[e1]
 			envs = append(envs, Env{
 				Name:  e1,
 				Value: e2,
@@ -393,6 +426,12 @@ func (adg *AnsibleDeployerGenerator) GenerateConfigFiles(out_dir string) error {
 		}
 	}
 
+	adg.hostOrder = hostStartOrder(plac)
+	for idx, hostname := range adg.hostOrder {
+		// Runner playbooks address their host by its position in the inventory
+		hosts[hostname].Idx = uint16(idx)
+	}
+
 	err = adg.CreateInventory(hosts)
 	if err != nil {
 		return nil
@@ -425,6 +464,6 @@ func (adg *AnsibleDeployerGenerator) GenerateConfigFiles(out_dir string) error {
 		return err
 	}
 
-	fmt.Println("DONE")
+	adg.logger.Println("DONE")
 	return nil
 }
@@ -1,10 +1,11 @@
 package deploy
 
 import (
-	"os"
 	"path"
 	"strconv"
 	"strings"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
 
 type DockerComposeDeployerGenerator struct {
@@ -30,15 +31,18 @@ func (d *DockerComposeDeployerGenerator) AddService(name string, depInfo *Deploy
 	}
 	d.composeString += prefix + prefix + "ports:\n"
 	d.composeString += prefix + prefix + prefix + "- \"" + strconv.Itoa(depInfo.Port) + ":" + strconv.Itoa(depInfo.Port) + "\"\n"
-	for port1, port2 := range depInfo.PublicPorts {
+	for _, port1 := range depInfo.SortedPublicPorts() {
+		port2 := depInfo.PublicPorts[port1]
 		d.composeString += prefix + prefix + prefix + "- \"" + strconv.Itoa(port1) + ":" + strconv.Itoa(port2) + "\"\n"
 	}
 	if len(depInfo.EnvVars) != 0 {
 		d.composeString += prefix + prefix + "environment:\n"
 	}
-	for key, val := range depInfo.EnvVars {
+	for _, key := range parser.SortedKeys(depInfo.EnvVars) {
+		val := depInfo.EnvVars[key]
 		d.composeString += prefix + prefix + prefix + "- " + key + "=" + val + "\n"
 	}
+	d.addDependsOn(depInfo)
 	d.composeString += prefix + prefix + "restart: always\n\n"
 }
 
@@ -57,13 +61,15 @@ func (d *DockerComposeDeployerGenerator) AddChoice(name string, depInfo *DeployI
 	}
 	if len(depInfo.PublicPorts) != 0 {
 		d.composeString += prefix + prefix + "ports:\n"
-		for port1, port2 := range depInfo.PublicPorts {
+		for _, port1 := range depInfo.SortedPublicPorts() {
+			port2 := depInfo.PublicPorts[port1]
 			d.composeString += prefix + prefix + prefix + "- \"" + strconv.Itoa(port1) + ":" + strconv.Itoa(port2) + "\"\n"
 		}
 	}
 	if len(depInfo.EnvVars) != 0 {
 		d.composeString += prefix + prefix + "environment:\n"
-		for key, val := range depInfo.EnvVars {
+		for _, key := range parser.SortedKeys(depInfo.EnvVars) {
+			val := depInfo.EnvVars[key]
 			d.composeString += prefix + prefix + prefix + "- " + key + "=" + val + "\n"
 		}
 	}
@@ -74,16 +80,24 @@ func (d *DockerComposeDeployerGenerator) AddChoice(name string, depInfo *DeployI
 	if len(depInfo.Entrypoint) != 0 {
 		d.composeString += prefix + prefix + "entrypoint: [" + strings.Join(depInfo.Entrypoint, ", ") + "]\n"
 	}
+	d.addDependsOn(depInfo)
 	d.composeString += prefix + prefix + "restart: always\n\n"
 }
 
+// Lets docker compose start the containers a container depends on first
+func (d *DockerComposeDeployerGenerator) addDependsOn(depInfo *DeployInfo) {
+	prefix := "  "
+	if len(depInfo.DependsOn) == 0 {
+		return
+	}
+	d.composeString += prefix + prefix + "depends_on:\n"
+	for _, dep := range depInfo.DependsOn {
+		d.composeString += prefix + prefix + prefix + "- " + strings.ToLower(dep) + "\n"
+	}
+}
+
 func (d *DockerComposeDeployerGenerator) GenerateConfigFiles(out_dir string) error {
 	outfile := path.Join(out_dir, "docker-compose.yml")
-	outf, err := os.OpenFile(outfile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		return err
-	}
 	d.composeString = "version: '3'\nservices:\n" + d.composeString
-	_, err = outf.WriteString(d.composeString)
-	return err
+	return parser.WriteFile(outfile, []byte(d.composeString), parser.FileMode)
 }
@@ -59,6 +59,11 @@ func GetNetGenFactory() *NetworkGeneratorFactory {
 	return factory
 }
 
+// ResetNetGenFactory discards the network generators and the types and services converted by them
+func ResetNetGenFactory() {
+	factory = nil
+}
+
 func (nf *NetworkGeneratorFactory) GetGenerator(framework string) (NetworkGenerator, error) {
 	if gen, ok := nf.Generators[framework]; !ok {
 		if gen_func, ok2 := nf.GeneratorFuncs[framework]; ok2 {
@@ -0,0 +1,56 @@
+package netgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+func testEnums() map[string]*parser.EnumInfo {
+	return map[string]*parser.EnumInfo{
+		"Status": {Name: "Status", Type: "int", ValNames: []string{"Pending", "Shipped", "Delivered"}, Values: []int64{1, 2, 12}, Doc: "Status of an order"},
+	}
+}
+
+func TestGrpcEnumValues(t *testing.T) {
+	g := NewGRPCGenerator().(*GRPCGenerator)
+	if err := g.ConvertEnumTypes(testEnums()); err != nil {
+		t.Fatal(err)
+	}
+	expected := "// Status of an order\nenum Status {\n\tStatus_UNSPECIFIED = 0;\n\tPending = 1;\n\tShipped = 2;\n\tDelivered = 12;\n}"
+	if val := g.remoteTypes["Status"].Val; val != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, val)
+	}
+}
+
+func TestThriftEnumValues(t *testing.T) {
+	g := NewThriftGenerator().(*ThriftGenerator)
+	if err := g.ConvertEnumTypes(testEnums()); err != nil {
+		t.Fatal(err)
+	}
+	expected := "// Status of an order\nenum Status {\n\tPending = 1,\n\tShipped = 2,\n\tDelivered = 12\n}"
+	if val := g.remoteTypes["Status"].Val; val != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, val)
+	}
+}
+
+func TestIDLMethodComments(t *testing.T) {
+	method := parser.FuncInfo{Name: "Cancel", Args: []parser.ArgInfo{parser.GetContextArg("ctx"), parser.GetBasicArg("id", "int64")}, Return: []parser.ArgInfo{parser.GetErrorArg("")}, Doc: "Cancels the order.\nIdempotent."}
+	g := NewGRPCGenerator().(*GRPCGenerator)
+	g.SetAppName("app")
+	if _, err := g.GenerateServerMethods("handler", "OrderService", map[string]parser.FuncInfo{"Cancel": method}, false, "orders"); err != nil {
+		t.Fatal(err)
+	}
+	if val := g.serviceTypes["OrderService"].Methods[0].Val; !strings.HasPrefix(val, "// Cancels the order.\n// Idempotent.\nrpc Cancel") {
+		t.Errorf("missing comment in %q", val)
+	}
+	th := NewThriftGenerator().(*ThriftGenerator)
+	th.SetAppName("app")
+	if _, err := th.GenerateServerMethods("handler", "OrderService", map[string]parser.FuncInfo{"Cancel": method}, false, "orders"); err != nil {
+		t.Fatal(err)
+	}
+	if val := th.serviceTypes["OrderService"].Methods[0].Val; !strings.HasPrefix(val, "// Cancels the order.\n// Idempotent.\nBaseRPCResponse Cancel(") {
+		t.Errorf("missing comment in %q", val)
+	}
+}
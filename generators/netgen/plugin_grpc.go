@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"os/exec"
 	"path"
@@ -43,6 +44,9 @@ func (g *GRPCGenerator) GetImports(hasUserDefinedObjs bool) []parser.ImportInfo
 	var imports []parser.ImportInfo
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "gen-go/" + g.appName})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "google.golang.org/grpc"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "google.golang.org/grpc/codes"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "google.golang.org/grpc/status"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "context"})
 	if hasUserDefinedObjs {
 		imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "github.com/jinzhu/copier"})
@@ -53,10 +57,8 @@ func (g *GRPCGenerator) GetImports(hasUserDefinedObjs bool) []parser.ImportInfo
 func (g *GRPCGenerator) GenerateFiles(outdir string) error {
 	// Generate pb file
 	grpc_file := path.Join(outdir, g.appName+".proto")
-	f, err := os.Create(grpc_file)
-	if err != nil {
-		return err
-	}
+	f := parser.NewOutputFile(grpc_file, parser.FileMode)
+	var err error
 	head_string := "syntax=\"proto3\";\n"
 	head_string += "option go_package=\"gen-go/" + g.appName + "\";\n"
 	head_string += "package " + g.appName + ";\n"
@@ -65,7 +67,8 @@ func (g *GRPCGenerator) GenerateFiles(outdir string) error {
 		return err
 	}
 	var rtype_string string
-	for _, rtype := range g.remoteTypes {
+	for _, name := range parser.SortedKeys(g.remoteTypes) {
+		rtype := g.remoteTypes[name]
 		rtype_string += rtype.Val + "\n"
 	}
 	_, err = f.WriteString(rtype_string + "\n")
@@ -73,7 +76,8 @@ func (g *GRPCGenerator) GenerateFiles(outdir string) error {
 		return err
 	}
 	var restype_string string
-	for _, restype := range g.responseTypes {
+	for _, name := range parser.SortedKeys(g.responseTypes) {
+		restype := g.responseTypes[name]
 		restype_string += restype.Val + "\n"
 	}
 	_, err = f.WriteString(restype_string + "\n")
@@ -81,14 +85,16 @@ func (g *GRPCGenerator) GenerateFiles(outdir string) error {
 		return err
 	}
 	var reqtype_string string
-	for _, reqtype := range g.requestTypes {
+	for _, name := range parser.SortedKeys(g.requestTypes) {
+		reqtype := g.requestTypes[name]
 		reqtype_string += reqtype.Val + "\n"
 	}
 	_, err = f.WriteString(reqtype_string + "\n")
 	if err != nil {
 		return err
 	}
-	for name, mtype := range g.serviceTypes {
+	for _, name := range parser.SortedKeys(g.serviceTypes) {
+		mtype := g.serviceTypes[name]
 		serviceString := "service " + name + "{\n"
 		for _, method := range mtype.Methods {
 			serviceString += "\t" + strings.ReplaceAll(method.Val, "\n", "\n\t") + "\n"
@@ -99,6 +105,10 @@ func (g *GRPCGenerator) GenerateFiles(outdir string) error {
 			return err
 		}
 	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
 	// Execute the grpc file
 	wd, err := os.Getwd()
 	if err != nil {
@@ -116,13 +126,8 @@ func (g *GRPCGenerator) GenerateFiles(outdir string) error {
 	os.Chdir(wd)
 	gen_dir := path.Join(outdir, "gen-go", g.appName)
 	mod_file := path.Join(gen_dir, "go.mod")
-	outf, err := os.OpenFile(mod_file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		return err
-	}
-	defer outf.Close()
 	mod_string := "module " + g.appName + "\n\ngo 1.14\n\nrequire google.golang.org/grpc v1.36.0\nrequire github.com/golang/protobuf v1.5.2\nrequire google.golang.org/protobuf v1.26.0"
-	_, err = outf.WriteString(mod_string)
+	err = parser.WriteFile(mod_file, []byte(mod_string), parser.FileMode)
 	if err != nil {
 		return err
 	}
@@ -137,9 +142,10 @@ func (g *GRPCGenerator) GenerateFiles(outdir string) error {
 }
 
 func (g *GRPCGenerator) ConvertRemoteTypes(remoteTypes map[string]*parser.ImplInfo) error {
-	for name, rtype := range remoteTypes {
+	for _, name := range parser.SortedKeys(remoteTypes) {
+		rtype := remoteTypes[name]
 		var fields []FieldInfo
-		rtype_string := "message " + name + " {\n"
+		rtype_string := idlComment(rtype.Doc) + "message " + name + " {\n"
 		for idx, field := range rtype.Fields {
 			str, err := g.getGrpcTypeString(field.Type)
 			if err != nil {
@@ -156,13 +162,33 @@ func (g *GRPCGenerator) ConvertRemoteTypes(remoteTypes map[string]*parser.ImplIn
 }
 
 func (g *GRPCGenerator) ConvertEnumTypes(enumTypes map[string]*parser.EnumInfo) error {
-	for name, etype := range enumTypes {
+	for _, name := range parser.SortedKeys(enumTypes) {
+		etype := enumTypes[name]
 		if len(etype.ValNames) == 0 {
 			continue
 		}
-		etype_string := "enum " + name + " {\n"
+		etype_string := idlComment(etype.Doc) + "enum " + name + " {\n"
+		has_zero := false
+		has_alias := false
+		seen_values := make(map[int64]bool)
+		for idx := range etype.ValNames {
+			value := enumValue(etype, idx)
+			if value < math.MinInt32 || value > math.MaxInt32 {
+				return errors.New("Value of enum constant " + etype.ValNames[idx] + " does not fit into a protobuf enum")
+			}
+			has_zero = has_zero || value == 0
+			has_alias = has_alias || seen_values[value]
+			seen_values[value] = true
+		}
+		if has_alias {
+			etype_string += "\toption allow_alias = true;\n"
+		}
+		if !has_zero {
+			// proto3 requires the first enum value to be zero
+			etype_string += "\t" + name + "_UNSPECIFIED = 0;\n"
+		}
 		for idx, valname := range etype.ValNames {
-			etype_string += "\t" + valname + " = " + fmt.Sprintf("%d", idx) + ";\n"
+			etype_string += "\t" + valname + " = " + fmt.Sprintf("%d", enumValue(etype, idx)) + ";\n"
 		}
 		etype_string += "}"
 		rinfo := RemoteTypeInfo{Name: name, Val: etype_string, IsEnum: true, PkgPath: etype.PkgPath}
@@ -176,7 +202,7 @@ func (g *GRPCGenerator) getGrpcArgName(name string) string {
 		return v.Name
 	}
 
-	log.Fatal("Could not find user-defined Type in Grpc Converted types")
+	parser.Abort("Could not find user-defined Type in Grpc Converted types")
 	return ""
 }
 
@@ -262,6 +288,9 @@ func (g *GRPCGenerator) generateServiceMethod(handler_name string, service_name
 		if idx == 0 {
 			// Special handling for context arg
 			argNames = append(argNames, arg.Name)
+			body += "if p, ok := peer.FromContext(" + arg.Name + "); ok {\n"
+			body += "\t" + arg.Name + " = stdlib.WithCallerAddr(" + arg.Name + ", p.Addr.String())\n"
+			body += "}\n"
 			continue
 		}
 		if arg.Type.BaseType == parser.USERDEFINED {
@@ -315,7 +344,7 @@ func (g *GRPCGenerator) generateServiceMethod(handler_name string, service_name
 			}
 		} else {
 			argName := g.convertMessageFieldName(arg.Name)
-			argNames = append(argNames, request_arg_name+"."+argName)
+			argNames = append(argNames, convertBasicValue(request_arg_name+"."+argName, arg.Type, grpcGoTypes, false))
 		}
 	}
 	var retNames []string
@@ -323,6 +352,10 @@ func (g *GRPCGenerator) generateServiceMethod(handler_name string, service_name
 		retNames = append(retNames, fmt.Sprintf("ret%d", idx))
 	}
 	body += strings.Join(retNames, ",") + " := " + handler_name + ".service." + funcInfo.Name + "(" + strings.Join(argNames, ",") + ")\n"
+	errName := retNames[len(retNames)-1]
+	body += "if errors.Is(" + errName + ", stdlib.ErrResourceExhausted) {\n"
+	body += "\treturn nil, status.Error(codes.ResourceExhausted, " + errName + ".Error())\n"
+	body += "}\n"
 
 	for idx, arg := range funcInfo.Return {
 		if arg.Type.BaseType == parser.USERDEFINED {
@@ -379,7 +412,7 @@ func (g *GRPCGenerator) generateServiceMethod(handler_name string, service_name
 		new_retname := "response"
 		body += new_retname + " := &" + g.appName + "." + response_type.Name + "{}\n"
 		for idx, field := range response_type.Fields {
-			body += new_retname + "." + field.Name + " = " + retNames[idx] + "\n"
+			body += new_retname + "." + field.Name + " = " + convertBasicValue(retNames[idx], field.Type, grpcGoTypes, true) + "\n"
 		}
 	}
 	body += "return response," + retNames[len(retNames)-1]
@@ -390,7 +423,8 @@ func (g *GRPCGenerator) GenerateServerMethods(handler_name string, service_name
 	bodies := make(map[string]string)
 	var methodInfos []MethodInfo
 	var funcNames []string
-	for name, method := range methods {
+	for _, name := range parser.SortedKeys(methods) {
+		method := methods[name]
 		funcNames = append(funcNames, name)
 		response_name, err := g.packResponse(service_name, name, method.Return)
 		if err != nil {
@@ -414,7 +448,7 @@ func (g *GRPCGenerator) GenerateServerMethods(handler_name string, service_name
 		new_rets = append(new_rets, parser.GetErrorArg(""))
 		method.Return = new_rets
 		var method_string string
-		method_string += "rpc " + name + " (" + request_name + ") returns (" + response_name + ") {}"
+		method_string += idlComment(method.Doc) + "rpc " + name + " (" + request_name + ") returns (" + response_name + ") {}"
 		methods[name] = method
 		methodInfos = append(methodInfos, MethodInfo{Name: name, Val: method_string})
 	}
@@ -499,7 +533,7 @@ func (g *GRPCGenerator) generateClientMethod(handler_name string, service_name s
 			}
 		} else {
 			argName := g.convertMessageFieldName(arg.Name)
-			body += "request." + argName + " = " + arg.Name + "\n"
+			body += "request." + argName + " = " + convertBasicValue(arg.Name, arg.Type, grpcGoTypes, true) + "\n"
 		}
 	}
 	if len(funcInfo.Return) != 1 {
@@ -557,13 +591,16 @@ func (g *GRPCGenerator) generateClientMethod(handler_name string, service_name s
 			} else {
 				retName := fmt.Sprintf("ret%d", idx)
 				body += "var " + retName + " " + field.Type.String() + "\n"
-				response_body += retName + " = response." + field.Name + "\n"
+				response_body += retName + " = " + convertBasicValue("response."+field.Name, field.Type, grpcGoTypes, false) + "\n"
 				retNames = append(retNames, retName)
 			}
 		}
 	}
 	retNames = append(retNames, "err")
 	body += "if err != nil {\n"
+	body += "\tif status.Code(err) == codes.ResourceExhausted {\n"
+	body += "\t\terr = &stdlib.ResourceExhaustedError{Reason: status.Convert(err).Message()}\n"
+	body += "\t}\n"
 	body += "\treturn " + strings.Join(retNames, ",") + "\n"
 	body += "}\n"
 	if has_timeout {
@@ -578,7 +615,8 @@ func (g *GRPCGenerator) generateClientMethod(handler_name string, service_name s
 
 func (g *GRPCGenerator) GenerateClientMethods(handler_name string, service_name string, methods map[string]parser.FuncInfo, nextNodeMethodArgs []parser.ArgInfo, nextNodeMethodReturn []parser.ArgInfo, is_metrics_on bool, has_timeout bool) (map[string]string, error) {
 	bodies := make(map[string]string)
-	for name, method := range methods {
+	for _, name := range parser.SortedKeys(methods) {
+		method := methods[name]
 		method.Args = append(method.Args, nextNodeMethodArgs...)
 		last_return := method.Return[len(method.Return)-1]
 		method.Return = append(method.Return[:len(method.Return)-1], nextNodeMethodReturn...)
@@ -603,6 +641,7 @@ func (g *GRPCGenerator) GenerateServerConstructor(prev_handler string, service_n
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "os"})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "errors"})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "net"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "google.golang.org/grpc/peer"})
 	//imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "time"})
 	//imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "google.golang.org/grpc/keepalive"})
 	if is_metrics_on {
@@ -688,45 +727,91 @@ func (g *GRPCGenerator) GenerateClientConstructor(service_name string, handler_n
 	return funcInfo, body, imports, fields, []parser.StructInfo{}
 }
 
+// Go types generated by protoc that differ from the types declared in the specification
+var grpcGoTypes = map[parser.BasicType]string{
+	parser.INT:    "int64",
+	parser.INT8:   "int32",
+	parser.INT16:  "int32",
+	parser.UINT:   "uint64",
+	parser.UINT8:  "uint32",
+	parser.UINT16: "uint32",
+	parser.BYTE:   "uint32",
+}
+
 func (g *GRPCGenerator) basicTypeToString(baseType parser.Type, typeDetail parser.TypeDetail) (string, error) {
 	if baseType == parser.BASIC {
-		if typeDetail.TypeName == parser.INT64 {
+		switch typeDetail.TypeName {
+		case parser.INT64, parser.INT:
 			return "int64", nil
-		} else if typeDetail.TypeName == parser.STRING {
+		case parser.INT32, parser.INT16, parser.INT8:
+			return "int32", nil
+		case parser.UINT64, parser.UINT:
+			return "uint64", nil
+		case parser.UINT32, parser.UINT16, parser.UINT8, parser.BYTE:
+			return "uint32", nil
+		case parser.STRING:
 			return "string", nil
-		} else if typeDetail.TypeName == parser.DOUBLE {
+		case parser.DOUBLE:
 			return "double", nil
-		} else if typeDetail.TypeName == parser.BOOL {
+		case parser.FLOAT32:
+			return "float", nil
+		case parser.BOOL:
 			return "bool", nil
 		}
+		return "", errors.New("Unsupported type for grpc: " + typeDetail.String(false))
 	} else if baseType == parser.USERDEFINED {
+		if typeDetail.PkgPath != "" {
+			return "", errors.New("Unsupported type for grpc: " + typeDetail.UserType + " is declared outside of the specification")
+		}
 		splits := strings.Split(typeDetail.UserType, ".")
 		return splits[len(splits)-1], nil
 	}
 	return "", errors.New("Unsupported type for grpc: " + baseType.String())
 }
 
+// Protobuf has no nested containers and the elements of repeated fields and maps can not be converted to the
+// generated go types, so only elements that protoc maps back to the specification type are allowed.
+func (g *GRPCGenerator) getGrpcElemTypeString(typeInfo parser.TypeInfo) (string, error) {
+	if isByteSlice(typeInfo) {
+		return "bytes", nil
+	}
+	if typeInfo.BaseType != parser.BASIC && typeInfo.BaseType != parser.USERDEFINED {
+		return "", errors.New("Unsupported nested type for grpc: " + typeInfo.String())
+	}
+	if _, ok := grpcGoTypes[typeInfo.Detail.TypeName]; ok && typeInfo.BaseType == parser.BASIC {
+		return "", errors.New("Unsupported element type for grpc: " + typeInfo.String() + ", use a 32 or 64 bit integer type instead")
+	}
+	return g.basicTypeToString(typeInfo.BaseType, typeInfo.Detail)
+}
+
 func (g *GRPCGenerator) getGrpcTypeString(typeInfo parser.TypeInfo) (string, error) {
 	if typeInfo.BaseType == parser.BASIC || typeInfo.BaseType == parser.USERDEFINED {
 		return g.basicTypeToString(typeInfo.BaseType, typeInfo.Detail)
 	} else if typeInfo.BaseType == parser.MAP {
-		basic_type1, err := g.basicTypeToString(typeInfo.ContainerType1, typeInfo.Detail)
+		key := typeInfo.KeyType()
+		if key.BaseType != parser.BASIC || key.Detail.TypeName == parser.DOUBLE || key.Detail.TypeName == parser.FLOAT32 {
+			return "", errors.New("Unsupported map key type for grpc: " + key.String())
+		}
+		basic_type1, err := g.getGrpcElemTypeString(key)
 		if err != nil {
 			return "", err
 		}
-		basic_type2, err := g.basicTypeToString(typeInfo.ContainerType2, typeInfo.Container2Detail)
+		basic_type2, err := g.getGrpcElemTypeString(typeInfo.ElemType())
 		if err != nil {
 			return "", err
 		}
 		return "map<" + basic_type1 + "," + basic_type2 + ">", nil
 	} else if typeInfo.BaseType == parser.LIST {
-		basic_type, err := g.basicTypeToString(typeInfo.ContainerType1, typeInfo.Detail)
+		if isByteSlice(typeInfo) {
+			return "bytes", nil
+		}
+		basic_type, err := g.getGrpcElemTypeString(typeInfo.ElemType())
 		if err != nil {
 			return "", err
 		}
 		return "repeated " + basic_type, nil
 	}
-	return "", errors.New("Unsupported type for grpc: " + typeInfo.BaseType.String())
+	return "", errors.New("Unsupported type for grpc: " + typeInfo.String())
 }
 
 func (g *GRPCGenerator) SetCustomParameters(params map[string]string) {
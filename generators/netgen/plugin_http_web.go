@@ -2,7 +2,6 @@ package netgen
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
@@ -46,67 +45,69 @@ func (d *DefaultWebGenerator) packResponse(service_name string, func_name string
 	}
 }
 
-func (d *DefaultWebGenerator) generateServiceMethod(handler_name string, service_name string, funcInfo parser.FuncInfo, is_metrics_on bool) (string, error) {
-	var body string
-	if is_metrics_on {
-		body += generateFunctionWrapperBody(handler_name, funcInfo.Name)
-	}
-	if len(funcInfo.Args) > 1 {
-		body += "var err error\n"
-	}
-	var arg_names []string
+// Argument of a method in the web templates
+type webArg struct {
+	Name string // Name of the argument
+	Type string // Go type of the argument
+	Var  string // Variable holding the JSON encoding of the argument
+}
+
+// Result of a method in the web templates, except for the error
+type webResult struct {
+	Var   string // Variable holding the result
+	Field string // Field of the response object holding the result
+	Type  string // Go type of the result
+}
+
+// Data of the web templates
+type webMethodData struct {
+	Handler  string          // Receiver of the generated method
+	Service  string          // Name of the service
+	Method   parser.FuncInfo // Method of the service
+	Context  string          // Context of the call on the client side
+	Cancel   string          // Variable holding the function that cancels the context of the call on the server side
+	Metrics  string          // Code that records the metrics of the method, empty if metrics are off
+	Args     []webArg        // Arguments of Method after the context
+	CallArgs []string        // Arguments that the server passes to Method
+	Results  []webResult     // Results of Method before the error
+	Err      string          // Variable holding the error returned by Method
+	Response string          // Name of the response object
+}
+
+func (d *DefaultWebGenerator) methodData(handler_name string, service_name string, funcInfo parser.FuncInfo) webMethodData {
+	data := webMethodData{Handler: handler_name, Service: service_name, Method: funcInfo, Context: "context.Background()", Cancel: funcInfo.LocalName("cancel"), Response: d.service_responses[service_name][funcInfo.Name]}
 	for idx, arg := range funcInfo.Args {
 		if idx == 0 {
-			body += "ctx := context.Background()\n"
-			arg_names = append(arg_names, "ctx")
+			data.Context = arg.Name
+			data.CallArgs = append(data.CallArgs, "ctx")
 			continue
 		}
-		body += "var " + arg.Name + " " + arg.Type.String() + "\n"
-		arg_id := fmt.Sprintf("arg%d", idx)
-		body += arg_id + " := r.FormValue(\"" + arg.Name + "\")\n"
-		body += "if " + arg_id + " != \"\" {\n"
-		body += "\terr = json.Unmarshal([]byte(" + arg_id + "), &" + arg.Name + ")\n"
-		body += "\tif err != nil {\n"
-		body += "\t\thttp.Error(w, err.Error(), 500)\n"
-		body += "\t\tlog.Println(err)\n"
-		body += "\t\tlog.Println(" + arg_id + `, "` + arg.Name + `")` + "\n"
-		body += "\t\treturn\n"
-		body += "\t}\n"
-		body += "}\n"
-		arg_names = append(arg_names, arg.Name)
-	}
-	var ret_names []string
-	for idx, _ := range funcInfo.Return {
-		ret_name := fmt.Sprintf("ret%d", idx)
-		ret_names = append(ret_names, ret_name)
+		data.Args = append(data.Args, webArg{Name: arg.Name, Type: arg.Type.String(), Var: fmt.Sprintf("arg%d", idx)})
+		data.CallArgs = append(data.CallArgs, arg.Name)
 	}
-	body += strings.Join(ret_names, ", ") + " := " + handler_name + ".service." + funcInfo.Name + "(" + strings.Join(arg_names, ", ") + ")\n"
-	// Return an error if the internal service returns an error
-	last_ret_name := ret_names[len(ret_names)-1]
-	body += "if " + last_ret_name + " != nil {\n"
-	body += "\thttp.Error(w, " + last_ret_name + ".Error(), 500)\n\treturn\n}\n"
-	// Result should be encoded with json in the response writer
-	// Get correct response name
-	service_map := d.service_responses[service_name]
-	response_name := service_map[funcInfo.Name]
-	body += "response := " + response_name + "{}\n"
-	for idx, ret_name := range ret_names {
-		response_ret_name := fmt.Sprintf("Ret%d", idx)
-		if idx == len(ret_names)-1 {
-			// Last return is an error so we don't need to encode that response
-			break
+	for idx, ret := range funcInfo.Return {
+		if idx == len(funcInfo.Return)-1 {
+			data.Err = fmt.Sprintf("ret%d", idx)
+			continue
 		}
-		body += "response." + response_ret_name + " = " + ret_name + "\n"
+		data.Results = append(data.Results, webResult{Var: fmt.Sprintf("ret%d", idx), Field: fmt.Sprintf("Ret%d", idx), Type: ret.Type.String()})
 	}
-	body += "json.NewEncoder(w).Encode(response)\n"
+	return data
+}
 
-	return body, nil
+func (d *DefaultWebGenerator) generateServiceMethod(handler_name string, service_name string, funcInfo parser.FuncInfo, is_metrics_on bool) (string, error) {
+	data := d.methodData(handler_name, service_name, funcInfo)
+	if is_metrics_on {
+		data.Metrics = generateFunctionWrapperBody(handler_name, funcInfo.Name)
+	}
+	return parser.ExecuteTemplate("web.server_method", data)
 }
 
 func (d *DefaultWebGenerator) GenerateServerMethods(handler_name string, service_name string, methods map[string]parser.FuncInfo, is_metrics_on bool, instance_name string) (map[string]string, error) {
 	bodies := make(map[string]string)
 	var func_names []string
-	for name, method := range methods {
+	for _, name := range parser.SortedKeys(methods) {
+		method := methods[name]
 		// Create Response Object
 		d.packResponse(service_name, name, method.Return)
 		func_names = append(func_names, name)
@@ -136,44 +137,13 @@ func (d *DefaultWebGenerator) GenerateServerMethods(handler_name string, service
 }
 
 func (d *DefaultWebGenerator) generateClientMethod(handler_name string, service_name string, funcInfo parser.FuncInfo) (string, error) {
-	var body string
-	body += "values := url.Values{}\n"
-	for idx, arg := range funcInfo.Args {
-		if idx == 0 {
-			// Ignore context arg
-			continue
-		}
-		argname := fmt.Sprintf("arg%d", idx)
-		body += argname + ", _ := json.Marshal(" + arg.Name + ")\n"
-		body += "values.Set(" + arg.Name + ", string(" + argname + "))\n"
-	}
-	body += "resp, err := http.PostForm(" + handler_name + ".url" + ", values)\n"
-	var ret_names []string
-	var err_ret_names []string
-	for idx, retarg := range funcInfo.Return {
-		if idx == len(funcInfo.Return)-1 {
-			continue
-		}
-		ret_names = append(ret_names, fmt.Sprintf("response.Ret%d", idx))
-		err_ret_names = append(err_ret_names, fmt.Sprintf("var ret%d %s", idx, retarg.Type.String()))
-	}
-	// Check error
-	body += "if err != nil {\n"
-	body += "\treturn " + strings.Join(err_ret_names, ", ") + ", err\n"
-	body += "}\n"
-	body += "defer resp.Body.Close()\n"
-	// Get correct response name
-	service_map := d.service_responses[service_name]
-	response_name := service_map[funcInfo.Name]
-	body += "var response " + response_name
-	body += "json.NewDecoder(resp.Body).Decode(&response)\n"
-	body += "return " + strings.Join(ret_names, ", ") + ", nil\n"
-	return body, nil
+	return parser.ExecuteTemplate("web.client_method", d.methodData(handler_name, service_name, funcInfo))
 }
 
 func (d *DefaultWebGenerator) GenerateClientMethods(handler_name string, service_name string, methods map[string]parser.FuncInfo, nextNodeMethodArgs []parser.ArgInfo, nextNodeMethodReturn []parser.ArgInfo, is_metrics_on bool, has_timeout bool) (map[string]string, error) {
 	bodies := make(map[string]string)
-	for name, method := range methods {
+	for _, name := range parser.SortedKeys(methods) {
+		method := methods[name]
 		method.Args = append(method.Args, nextNodeMethodArgs...)
 		last_return := method.Return[len(method.Return)-1]
 		method.Return = append(method.Return[:len(method.Return)-1], nextNodeMethodReturn...)
@@ -210,12 +180,14 @@ func (d *DefaultWebGenerator) ConvertEnumTypes(enumTypes map[string]*parser.Enum
 }
 
 func (d *DefaultWebGenerator) GetImports(_ bool) []parser.ImportInfo {
-	return []parser.ImportInfo{parser.ImportInfo{ImportName: "", FullName: "github.com/gorilla/mux"}, parser.ImportInfo{ImportName: "", FullName: "net/http"}, parser.ImportInfo{ImportName: "", FullName: "os"}, parser.ImportInfo{ImportName: "", FullName: "context"}, parser.ImportInfo{ImportName: "", FullName: "encoding/json"}}
+	return []parser.ImportInfo{parser.ImportInfo{ImportName: "", FullName: "github.com/gorilla/mux"}, parser.ImportInfo{ImportName: "", FullName: "net/http"}, parser.ImportInfo{ImportName: "", FullName: "os"}, parser.ImportInfo{ImportName: "", FullName: "encoding/json"}, parser.ImportInfo{ImportName: "", FullName: "github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"}}
 }
 
 func (d *DefaultWebGenerator) getClientImports() []parser.ImportInfo {
 	imports := d.GetImports(false)
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "context"})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "net/url"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "strings"})
 	return imports
 }
 
@@ -232,8 +204,9 @@ func (d *DefaultWebGenerator) GenerateServerConstructor(prev_handler string, ser
 	body := ""
 	body += "handler := &" + handler_name + "{service: old_handler, url: url}\n"
 	var structs []parser.StructInfo
-	for _, v := range d.service_responses[base_name] {
-		structs = append(structs, d.response_objs[v])
+	responses := d.service_responses[base_name]
+	for _, method_name := range parser.SortedKeys(responses) {
+		structs = append(structs, d.response_objs[responses[method_name]])
 	}
 	if is_metrics_on {
 		funcs := d.functions[base_name]
@@ -286,8 +259,9 @@ func (d *DefaultWebGenerator) GenerateClientConstructor(service_name string, han
 	body += "url := \"http://\" + addr + \":\" + port\n"
 	body += "return &" + base_name + "WebClient{url: url}, nil\n"
 	var structs []parser.StructInfo
-	for _, v := range d.service_responses[base_name] {
-		structs = append(structs, d.response_objs[v])
+	responses := d.service_responses[base_name]
+	for _, method_name := range parser.SortedKeys(responses) {
+		structs = append(structs, d.response_objs[responses[method_name]])
 	}
 	return funcInfo, body, imports, fields, structs
 }
@@ -295,3 +269,52 @@ func (d *DefaultWebGenerator) GenerateClientConstructor(service_name string, han
 func (d *DefaultWebGenerator) SetCustomParameters(params map[string]string) {
 	// Has no custom parameters
 }
+
+func init() {
+	parser.RegisterTemplate("web.server_method", `{{.Metrics}}{{if .Args}}var err error
+{{end}}{{if .Method.Args}}ctx, {{.Cancel}} := stdlib.WithEncodedDeadline(stdlib.WithCallerAddr(r.Context(), r.RemoteAddr), r.Header.Get(stdlib.DeadlineHeader))
+defer {{.Cancel}}()
+{{end}}{{range .Args}}var {{.Name}} {{.Type}}
+{{.Var}} := r.FormValue("{{.Name}}")
+if {{.Var}} != "" {
+	err = json.Unmarshal([]byte({{.Var}}), &{{.Name}})
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		log.Println(err)
+		log.Println({{.Var}}, "{{.Name}}")
+		return
+	}
+}
+{{end}}{{range .Results}}{{.Var}}, {{end}}{{.Err}} := {{.Handler}}.service.{{.Method.Name}}({{join .CallArgs ", "}})
+if {{.Err}} != nil {
+	stdlib.WriteHTTPError(w, {{.Err}})
+	return
+}
+response := {{.Response}}{}
+{{range .Results}}response.{{.Field}} = {{.Var}}
+{{end}}json.NewEncoder(w).Encode(response)
+`)
+	parser.RegisterTemplate("web.client_method", `values := url.Values{}
+{{range .Args}}{{.Var}}, _ := json.Marshal({{.Name}})
+values.Set("{{.Name}}", string({{.Var}}))
+{{end}}var response {{.Response}}
+req, err := http.NewRequestWithContext({{.Context}}, http.MethodPost, {{.Handler}}.url+"/{{.Method.Name}}", strings.NewReader(values.Encode()))
+if err != nil {
+	return {{range .Results}}response.{{.Field}}, {{end}}err
+}
+req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+if timeout, ok := stdlib.EncodeDeadline({{.Context}}); ok {
+	req.Header.Set(stdlib.DeadlineHeader, timeout)
+}
+resp, err := http.DefaultClient.Do(req)
+if err != nil {
+	return {{range .Results}}response.{{.Field}}, {{end}}err
+}
+defer resp.Body.Close()
+if resp.StatusCode != http.StatusOK {
+	return {{range .Results}}response.{{.Field}}, {{end}}stdlib.ReadHTTPError(resp)
+}
+err = json.NewDecoder(resp.Body).Decode(&response)
+return {{range .Results}}response.{{.Field}}, {{end}}err
+`)
+}
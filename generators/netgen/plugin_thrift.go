@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"os/exec"
 	"path"
@@ -51,12 +52,11 @@ func (t *ThriftGenerator) GetImports(hasUserDefinedObjs bool) []parser.ImportInf
 func (t *ThriftGenerator) GenerateFiles(outdir string) error {
 	// Generate thrift file
 	thrift_file := path.Join(outdir, t.appName+".thrift")
-	f, err := os.Create(thrift_file)
-	if err != nil {
-		return err
-	}
+	f := parser.NewOutputFile(thrift_file, parser.FileMode)
+	var err error
 	var rtype_string string
-	for _, rtype := range t.remoteTypes {
+	for _, name := range parser.SortedKeys(t.remoteTypes) {
+		rtype := t.remoteTypes[name]
 		rtype_string += rtype.Val + "\n"
 	}
 	_, err = f.WriteString(rtype_string + "\n")
@@ -64,14 +64,16 @@ func (t *ThriftGenerator) GenerateFiles(outdir string) error {
 		return err
 	}
 	var restype_string string
-	for _, restype := range t.responseTypes {
+	for _, name := range parser.SortedKeys(t.responseTypes) {
+		restype := t.responseTypes[name]
 		restype_string += restype.Val + "\n"
 	}
 	_, err = f.WriteString(restype_string + "\n")
 	if err != nil {
 		return err
 	}
-	for name, mtype := range t.serviceTypes {
+	for _, name := range parser.SortedKeys(t.serviceTypes) {
+		mtype := t.serviceTypes[name]
 		serviceString := "service " + name + "{\n"
 		for _, method := range mtype.Methods {
 			serviceString += "\t" + strings.ReplaceAll(method.Val, "\n", "\n\t") + "\n"
@@ -82,6 +84,10 @@ func (t *ThriftGenerator) GenerateFiles(outdir string) error {
 			return err
 		}
 	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
 	// Execute the thrift file
 	wd, err := os.Getwd()
 	if err != nil {
@@ -98,12 +104,7 @@ func (t *ThriftGenerator) GenerateFiles(outdir string) error {
 	os.Chdir(wd)
 	gen_dir := path.Join(outdir, "gen-go", strings.ToLower(t.appName))
 	mod_file := path.Join(gen_dir, "go.mod")
-	outf, err := os.OpenFile(mod_file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		return err
-	}
-	defer outf.Close()
-	_, err = outf.WriteString("module " + t.appName + "\n\ngo 1.18\n\nrequire github.com/apache/thrift v0.16.0")
+	err = parser.WriteFile(mod_file, []byte("module "+t.appName+"\n\ngo 1.18\n\nrequire github.com/apache/thrift v0.16.0"), parser.FileMode)
 	if err != nil {
 		return err
 	}
@@ -119,9 +120,10 @@ func (t *ThriftGenerator) GenerateFiles(outdir string) error {
 }
 
 func (t *ThriftGenerator) ConvertRemoteTypes(remoteTypes map[string]*parser.ImplInfo) error {
-	for name, rtype := range remoteTypes {
+	for _, name := range parser.SortedKeys(remoteTypes) {
+		rtype := remoteTypes[name]
 		var fields []FieldInfo
-		rtype_string := "struct " + name + " {\n"
+		rtype_string := idlComment(rtype.Doc) + "struct " + name + " {\n"
 		for idx, field := range rtype.Fields {
 			str, err := t.getThriftTypeString(field.Type)
 			if err != nil {
@@ -138,13 +140,18 @@ func (t *ThriftGenerator) ConvertRemoteTypes(remoteTypes map[string]*parser.Impl
 }
 
 func (t *ThriftGenerator) ConvertEnumTypes(enumTypes map[string]*parser.EnumInfo) error {
-	for name, etype := range enumTypes {
+	for _, name := range parser.SortedKeys(enumTypes) {
+		etype := enumTypes[name]
 		if len(etype.ValNames) == 0 {
 			continue
 		}
-		etype_string := "enum " + name + " {\n"
+		etype_string := idlComment(etype.Doc) + "enum " + name + " {\n"
 		for idx, valname := range etype.ValNames {
-			etype_string += "\t" + valname + " = " + fmt.Sprintf("%d", idx)
+			value := enumValue(etype, idx)
+			if value < math.MinInt32 || value > math.MaxInt32 {
+				return errors.New("Value of enum constant " + valname + " does not fit into a thrift enum")
+			}
+			etype_string += "\t" + valname + " = " + fmt.Sprintf("%d", value)
 			if idx != len(etype.ValNames)-1 {
 				etype_string += ","
 			}
@@ -165,6 +172,13 @@ func (t *ThriftGenerator) generateServiceMethod(handler_name string, service_nam
 	}
 	var argNames []string
 	for idx, arg := range funcInfo.Args {
+		if idx == 0 {
+			// The client sends the time left until its deadline in a header
+			timeout, cancel := funcInfo.LocalName("timeout"), funcInfo.LocalName("cancel")
+			body += timeout + ", _ := thrift.GetHeader(" + arg.Name + ", stdlib.DeadlineHeader)\n"
+			body += arg.Name + ", " + cancel + " := stdlib.WithEncodedDeadline(" + arg.Name + ", " + timeout + ")\n"
+			body += "defer " + cancel + "()\n"
+		}
 		if arg.Type.BaseType == parser.USERDEFINED {
 			argName := fmt.Sprintf("arg%d", idx)
 			argNames = append(argNames, argName)
@@ -193,7 +207,7 @@ func (t *ThriftGenerator) generateServiceMethod(handler_name string, service_nam
 				body += "copier.Copy(&" + argName + ", " + arg.Name + ")\n"
 			}
 		} else if arg.Type.BaseType == parser.MAP && arg.Type.ContainerType2 == parser.USERDEFINED {
-			argName := fmt.Sprint("arg%d", idx)
+			argName := fmt.Sprintf("arg%d", idx)
 			argNames = append(argNames, argName)
 			argType, err := t.basicTypeToString(arg.Type.ContainerType2, arg.Type.Container2Detail)
 			if err != nil {
@@ -206,7 +220,7 @@ func (t *ThriftGenerator) generateServiceMethod(handler_name string, service_nam
 				body += "copier.Copy(&" + argName + ", " + arg.Name + ")\n"
 			}
 		} else {
-			argNames = append(argNames, arg.Name)
+			argNames = append(argNames, convertBasicValue(arg.Name, arg.Type, thriftGoTypes, false))
 		}
 	}
 	var retNames []string
@@ -268,7 +282,7 @@ func (t *ThriftGenerator) generateServiceMethod(handler_name string, service_nam
 		new_retname := "response"
 		body += new_retname + " := " + t.appName + ".New" + response_type.Name + "()\n"
 		for idx, field := range response_type.Fields {
-			body += new_retname + "." + field.Name + " = " + retNames[idx] + "\n"
+			body += new_retname + "." + field.Name + " = " + convertBasicValue(retNames[idx], field.Type, thriftGoTypes, true) + "\n"
 		}
 	}
 	body += "return response," + retNames[len(retNames)-1]
@@ -282,7 +296,7 @@ func (t *ThriftGenerator) getThriftArgName(name string) string {
 		return v.Name
 	}
 
-	log.Fatal("Could not find user-defined Type ", name, " in Thrift Converted types")
+	parser.Abort("Could not find user-defined Type ", name, " in Thrift Converted types")
 	return ""
 }
 
@@ -326,6 +340,7 @@ func (t *ThriftGenerator) GenerateServerConstructor(prev_handler string, service
 	var imports []parser.ImportInfo
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "os"})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "errors"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"})
 	body := ""
 	if is_metrics_on {
 		funcs := t.functions[base_name]
@@ -342,7 +357,7 @@ func (t *ThriftGenerator) generateServerRunMethod(service_name string, handler_n
 	var body string
 	fn := parser.FuncInfo{Name: "Run", Args: []parser.ArgInfo{}, Return: []parser.ArgInfo{parser.GetErrorArg("")}}
 	body += "var protocolFactory thrift.TProtocolFactory\n"
-	body += "protocolFactory = thrift.NewTBinaryProtocolFactory(true, true)\n"
+	body += "protocolFactory = thrift.NewTHeaderProtocolFactoryConf(&thrift.TConfiguration{})\n"
 	body += "var transportFactory thrift.TTransportFactory\n"
 	body += "transportFactory = thrift.NewTTransportFactory()\n"
 	body += "addr := os.Getenv(\"" + service_name + "_ADDRESS\")\n"
@@ -371,11 +386,12 @@ func (t *ThriftGenerator) GenerateClientConstructor(service_name string, handler
 	fields := []parser.ArgInfo{parser.GetPointerArg("client", t.appName+"."+base_name+"Client")}
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "os"})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "errors"})
+	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"})
 	body := ""
 	body += "var transportFactory thrift.TTransportFactory\n"
 	body += "transportFactory = thrift.NewTTransportFactory()\n"
 	body += "var protocolFactory thrift.TProtocolFactory\n"
-	body += "protocolFactory = thrift.NewTBinaryProtocolFactory(true, true)\n"
+	body += "protocolFactory = thrift.NewTHeaderProtocolFactoryConf(&thrift.TConfiguration{})\n"
 	body += "var transport thrift.TTransport\n"
 	body += "addr := os.Getenv(\"" + service_name + "_ADDRESS\")\n"
 	body += "port := os.Getenv(\"" + service_name + "_PORT\")\n"
@@ -383,7 +399,7 @@ func (t *ThriftGenerator) GenerateClientConstructor(service_name string, handler
 	body += "\treturn nil, errors.New(\"Address or port were not set\")\n}\n"
 	body += "var err error\n"
 	transport_str := "transport, err = thrift.NewTSocket(addr + \":\" + port)\n"
-	retBody := "return &" + base_name + "RPCClient{client:" + t.appName + ".New" + base_name + "Client(thrift.NewTStandardClient(iprot, oprot))}, nil"
+	retBody := "return &" + base_name + "RPCClient{client:" + t.appName + ".New" + base_name + "Client(thrift.NewTStandardClient(protocol, protocol))}, nil"
 	if timeout != "" {
 		tmp := "duration, err := time.ParseDuration(\"" + timeout + "\")\n"
 		tmp += "if err != nil {\n"
@@ -394,7 +410,7 @@ func (t *ThriftGenerator) GenerateClientConstructor(service_name string, handler
 		transport_str = tmp
 		imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "time"})
 		fields = append(fields, parser.GetBasicArg("Timeout", "time.Duration"))
-		retBody = "return &" + base_name + "RPCClient{client:" + t.appName + ".New" + base_name + "Client(thrift.NewTStandardClient(iprot, oprot)), Timeout: duration}, nil"
+		retBody = "return &" + base_name + "RPCClient{client:" + t.appName + ".New" + base_name + "Client(thrift.NewTStandardClient(protocol, protocol)), Timeout: duration}, nil"
 	}
 	body += transport_str
 	body += "if err != nil {\n\treturn nil, err\n}\n"
@@ -402,8 +418,8 @@ func (t *ThriftGenerator) GenerateClientConstructor(service_name string, handler
 	body += "if err != nil {\n\treturn nil, err\n}\n"
 	body += "err = transport.Open()\n"
 	body += "if err != nil {\n\treturn nil, err\n}\n"
-	body += "iprot := protocolFactory.GetProtocol(transport)\n"
-	body += "oprot := protocolFactory.GetProtocol(transport)\n"
+	// The header protocol reads and writes the frames of a call with the same protocol
+	body += "protocol := protocolFactory.GetProtocol(transport)\n"
 	body += retBody
 	return funcInfo, body, imports, fields, []parser.StructInfo{}
 }
@@ -412,7 +428,8 @@ func (t *ThriftGenerator) GenerateServerMethods(handler_name string, service_nam
 	bodies := make(map[string]string)
 	var methodInfos []MethodInfo
 	var funcNames []string
-	for name, method := range methods {
+	for _, name := range parser.SortedKeys(methods) {
+		method := methods[name]
 		// Pack return types into a single response object
 		response_name, err := t.packResponse(service_name, name, method.Return)
 		if err != nil {
@@ -427,7 +444,7 @@ func (t *ThriftGenerator) GenerateServerMethods(handler_name string, service_nam
 		var new_rets []parser.ArgInfo
 		// Arguments need to be modified. If it is a userdefined arg then it needs to be changed into a thrift arg
 		var method_string string
-		method_string += response_name + " " + name + "(\n"
+		method_string += idlComment(method.Doc) + response_name + " " + name + "(\n"
 		for idx, arg := range method.Args {
 			if idx != 0 {
 				// Only ignore the context arg which will always be the 1st arg
@@ -451,6 +468,8 @@ func (t *ThriftGenerator) GenerateServerMethods(handler_name string, service_nam
 				} else {
 					new_arg = parser.GetBasicArg(arg.Name, thrift_arg_name)
 				}
+			} else if wireType, ok := thriftGoTypes[arg.Type.Detail.TypeName]; ok && arg.Type.BaseType == parser.BASIC {
+				new_arg = parser.GetBasicArg(arg.Name, wireType)
 			}
 			new_args = append(new_args, new_arg)
 		}
@@ -487,6 +506,11 @@ func (t *ThriftGenerator) generateClientMethod(handler_name string, service_name
 				body += arg.Name + ", cancel := context.WithTimeout(" + arg.Name + "," + handler_name + ".Timeout)\n"
 				body += "defer cancel()\n"
 			}
+			timeout := funcInfo.LocalName("timeout")
+			body += "if " + timeout + ", ok := stdlib.EncodeDeadline(" + arg.Name + "); ok {\n"
+			body += "\t" + arg.Name + " = thrift.SetHeader(" + arg.Name + ", stdlib.DeadlineHeader, " + timeout + ")\n"
+			body += "\t" + arg.Name + " = thrift.SetWriteHeaderList(" + arg.Name + ", []string{stdlib.DeadlineHeader})\n"
+			body += "}\n"
 			continue
 		}
 		if arg.Type.BaseType == parser.USERDEFINED {
@@ -534,7 +558,7 @@ func (t *ThriftGenerator) generateClientMethod(handler_name string, service_name
 				body += "copier.Copy(&" + argName + ", &" + arg.Name + ")\n"
 			}
 		} else {
-			argNames = append(argNames, arg.Name)
+			argNames = append(argNames, convertBasicValue(arg.Name, arg.Type, thriftGoTypes, true))
 		}
 	}
 	var retNames []string
@@ -592,7 +616,7 @@ func (t *ThriftGenerator) generateClientMethod(handler_name string, service_name
 			} else {
 				retName := fmt.Sprintf("ret%d", idx)
 				body += "var " + retName + " " + field.Type.String() + "\n"
-				response_body += retName + " = response." + field.Name + "\n"
+				response_body += retName + " = " + convertBasicValue("response."+field.Name, field.Type, thriftGoTypes, false) + "\n"
 				retNames = append(retNames, retName)
 			}
 		}
@@ -613,7 +637,8 @@ func (t *ThriftGenerator) generateClientMethod(handler_name string, service_name
 
 func (t *ThriftGenerator) GenerateClientMethods(handler_name string, service_name string, methods map[string]parser.FuncInfo, nextNodeMethodArgs []parser.ArgInfo, nextNodeMethodReturn []parser.ArgInfo, is_metrics_on bool, has_timeout bool) (map[string]string, error) {
 	bodies := make(map[string]string)
-	for name, method := range methods {
+	for _, name := range parser.SortedKeys(methods) {
+		method := methods[name]
 		method.Args = append(method.Args, nextNodeMethodArgs...)
 		last_return := method.Return[len(method.Return)-1]
 		method.Return = append(method.Return[:len(method.Return)-1], nextNodeMethodReturn...)
@@ -628,45 +653,86 @@ func (t *ThriftGenerator) GenerateClientMethods(handler_name string, service_nam
 	return bodies, nil
 }
 
+// Go types generated by thrift that differ from the types declared in the specification.
+// Thrift has no unsigned types so unsigned integers are widened, uint64 values above MaxInt64 wrap around.
+var thriftGoTypes = map[parser.BasicType]string{
+	parser.INT:     "int64",
+	parser.UINT8:   "int16",
+	parser.BYTE:    "int16",
+	parser.UINT16:  "int32",
+	parser.UINT32:  "int64",
+	parser.UINT:    "int64",
+	parser.UINT64:  "int64",
+	parser.FLOAT32: "float64",
+}
+
 func (t *ThriftGenerator) basicTypeToString(baseType parser.Type, typeDetail parser.TypeDetail) (string, error) {
 	if baseType == parser.BASIC {
-		if typeDetail.TypeName == parser.INT64 {
+		switch typeDetail.TypeName {
+		case parser.INT64, parser.INT, parser.UINT32, parser.UINT, parser.UINT64:
 			return "i64", nil
-		} else if typeDetail.TypeName == parser.STRING {
+		case parser.INT32, parser.UINT16:
+			return "i32", nil
+		case parser.INT16, parser.UINT8, parser.BYTE:
+			return "i16", nil
+		case parser.INT8:
+			return "byte", nil
+		case parser.STRING:
 			return "string", nil
-		} else if typeDetail.TypeName == parser.DOUBLE {
+		case parser.DOUBLE, parser.FLOAT32:
 			return "double", nil
-		} else if typeDetail.TypeName == parser.BOOL {
+		case parser.BOOL:
 			return "bool", nil
 		}
+		return "", errors.New("Unsupported type for thrift: " + typeDetail.String(false))
 	} else if baseType == parser.USERDEFINED {
+		if typeDetail.PkgPath != "" {
+			return "", errors.New("Unsupported type for thrift: " + typeDetail.UserType + " is declared outside of the specification")
+		}
 		splits := strings.Split(typeDetail.UserType, ".")
 		return splits[len(splits)-1], nil
 	}
 	return "", errors.New("Unsupported type for thrift: " + baseType.String())
 }
 
+// Container elements can not be converted to the generated go types, so only elements that thrift maps back
+// to the specification type are allowed. Nested containers may only hold basic types.
+func (t *ThriftGenerator) getThriftElemTypeString(typeInfo parser.TypeInfo) (string, error) {
+	if typeInfo.BaseType == parser.BASIC {
+		if _, ok := thriftGoTypes[typeInfo.Detail.TypeName]; ok {
+			return "", errors.New("Unsupported element type for thrift: " + typeInfo.String() + ", use a signed fixed size type instead")
+		}
+	}
+	if (typeInfo.BaseType == parser.LIST || typeInfo.BaseType == parser.MAP) && typeInfo.IsUserDefined() {
+		return "", errors.New("Unsupported nested type for thrift: " + typeInfo.String())
+	}
+	return t.getThriftTypeString(typeInfo)
+}
+
 func (t *ThriftGenerator) getThriftTypeString(typeInfo parser.TypeInfo) (string, error) {
 	if typeInfo.BaseType == parser.BASIC || typeInfo.BaseType == parser.USERDEFINED {
 		return t.basicTypeToString(typeInfo.BaseType, typeInfo.Detail)
 	} else if typeInfo.BaseType == parser.LIST {
-		basic_type, err := t.basicTypeToString(typeInfo.ContainerType1, typeInfo.Detail)
+		if isByteSlice(typeInfo) {
+			return "binary", nil
+		}
+		basic_type, err := t.getThriftElemTypeString(typeInfo.ElemType())
 		if err != nil {
 			return "", err
 		}
 		return "list<" + basic_type + ">", nil
 	} else if typeInfo.BaseType == parser.MAP {
-		basic_type1, err := t.basicTypeToString(typeInfo.ContainerType1, typeInfo.Detail)
+		basic_type1, err := t.getThriftElemTypeString(typeInfo.KeyType())
 		if err != nil {
 			return "", err
 		}
-		basic_type2, err := t.basicTypeToString(typeInfo.ContainerType2, typeInfo.Container2Detail)
+		basic_type2, err := t.getThriftElemTypeString(typeInfo.ElemType())
 		if err != nil {
 			return "", err
 		}
 		return "map<" + basic_type1 + "," + basic_type2 + ">", nil
 	}
-	return "", errors.New("Unsupported type for thrift: " + typeInfo.BaseType.String())
+	return "", errors.New("Unsupported type for thrift: " + typeInfo.String())
 }
 
 func (t *ThriftGenerator) SetCustomParameters(params map[string]string) {
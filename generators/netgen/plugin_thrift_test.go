@@ -0,0 +1,31 @@
+package netgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+)
+
+func TestThriftDeadlineLocals(t *testing.T) {
+	method := parser.FuncInfo{Name: "Wait", Args: []parser.ArgInfo{parser.GetContextArg("ctx"), parser.GetBasicArg("timeout", "int64"), parser.GetBasicArg("cancel", "bool")}, Return: []parser.ArgInfo{parser.GetErrorArg("")}, Public: true}
+	methods := map[string]parser.FuncInfo{"Wait": method}
+	g := NewThriftGenerator().(*ThriftGenerator)
+	g.SetAppName("app")
+	server, err := g.GenerateServerMethods("handler", "WaitService", methods, false, "waiter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"timeout_, _ := thrift.GetHeader(ctx, stdlib.DeadlineHeader)\n", "ctx, cancel_ := stdlib.WithEncodedDeadline(ctx, timeout_)\n", "defer cancel_()\n"} {
+		if !strings.Contains(server["Wait"], expected) {
+			t.Errorf("Expected %q in the server method\n%s", expected, server["Wait"])
+		}
+	}
+	client, err := g.generateClientMethod("handler", "WaitService", method, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "if timeout_, ok := stdlib.EncodeDeadline(ctx); ok {\n"; !strings.Contains(client, expected) {
+		t.Errorf("Expected %q in the client method\n%s", expected, client)
+	}
+}
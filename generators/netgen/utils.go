@@ -2,6 +2,7 @@ package netgen
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
@@ -59,3 +60,48 @@ func generateMetricImports() []parser.ImportInfo {
 func generateMetricConstructorBody(handler_name string) string {
 	return "go " + handler_name + ".startMetrics()\n"
 }
+
+// Returns true if the type is a []byte which is sent as raw bytes rather than as a list
+func isByteSlice(typeInfo parser.TypeInfo) bool {
+	if typeInfo.BaseType != parser.LIST {
+		return false
+	}
+	elem := typeInfo.ElemType()
+	return elem.BaseType == parser.BASIC && (elem.Detail.TypeName == parser.UINT8 || elem.Detail.TypeName == parser.BYTE)
+}
+
+// Converts expr between the type declared in the specification and the go type used by the generated network code.
+// wireTypes holds the generated go types that differ from the specification types.
+func convertBasicValue(expr string, typeInfo parser.TypeInfo, wireTypes map[parser.BasicType]string, toWire bool) string {
+	if typeInfo.BaseType != parser.BASIC {
+		return expr
+	}
+	wireType, ok := wireTypes[typeInfo.Detail.TypeName]
+	if !ok {
+		return expr
+	}
+	if toWire {
+		return wireType + "(" + expr + ")"
+	}
+	return typeInfo.String() + "(" + expr + ")"
+}
+
+// Formats a doc comment from the specification as a comment in the generated IDL files
+func idlComment(doc string) string {
+	if doc == "" {
+		return ""
+	}
+	var comment string
+	for _, line := range strings.Split(doc, "\n") {
+		comment += strings.TrimRight("// "+line, " ") + "\n"
+	}
+	return comment
+}
+
+// Returns the value of the idx-th enum constant. Enums without recorded values are numbered by position.
+func enumValue(etype *parser.EnumInfo, idx int) int64 {
+	if len(etype.Values) == len(etype.ValNames) {
+		return etype.Values[idx]
+	}
+	return int64(idx)
+}
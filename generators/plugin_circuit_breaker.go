@@ -1,10 +1,8 @@
 package generators
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
 )
 
 type CircuitBreakerModifier struct {
@@ -35,91 +33,18 @@ func (m *CircuitBreakerModifier) GetName() string {
 	return "CircuitBreakerModifier"
 }
 
-func (m *CircuitBreakerModifier) generateClientMethodBody(receiver_name string, finfo parser.FuncInfo) string {
-	var body string
-	var arg_names []string
-	for _, arg := range finfo.Args {
-		arg_names = append(arg_names, arg.Name)
-	}
-	var ret_names []string
-	// Add ret_names and vars to the body
-	for idx, ret := range finfo.Return {
-		ret_name := fmt.Sprintf("ret_%d", idx)
-		ret_names = append(ret_names, ret_name)
-		body += "var " + ret_name + " " + ret.String() + "\n"
-	}
-	body += "if !" + receiver_name + ".cb.Ready() {\n"
-	body += "\t" + ret_names[len(ret_names)-1] + " = errors.New(\"Circuit breaker tripped\")\n"
-	body += "\treturn " + strings.Join(ret_names, ", ") + "\n"
-	body += "}\n"
-	body += "defer func(){" + receiver_name + ".cb.Done(ctx, " + ret_names[len(ret_names)-1] + ")}()\n"
-	body += strings.Join(ret_names, ",") + " = " + receiver_name + ".client." + finfo.Name + "(" + strings.Join(arg_names, ", ") + ")\n"
-	body += "return " + strings.Join(ret_names, ", ")
-	return body
-}
-
-func (m *CircuitBreakerModifier) getImports() []parser.ImportInfo {
-	var imports []parser.ImportInfo
-	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "context"})
-	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "time"})
-	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "log"})
-	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "errors"})
-	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "github.com/mercari/go-circuitbreaker"})
-	return imports
-}
-
-func (m *CircuitBreakerModifier) getClientFields(next_node_name string) []parser.ArgInfo {
-	var fields []parser.ArgInfo
-	fields = append(fields, parser.GetPointerArg("client", next_node_name))
-	fields = append(fields, parser.GetPointerArg("cb", "circuitbreaker.CircuitBreaker"))
-	return fields
-}
-
-func (m *CircuitBreakerModifier) getClientConstructor(name string, next_node *ServiceImplInfo) (parser.FuncInfo, string) {
-	func_name := "New" + name
-	ret_args := []parser.ArgInfo{parser.GetPointerArg("", name)}
-	args := []parser.ArgInfo{}
-	args = append(args, parser.GetPointerArg("client", next_node.Name))
-	for _, param := range m.Params {
-		switch ptype := param.(type) {
-		case *ValueParameter:
-			args = append(args, parser.GetBasicArg(ptype.KeywordName, "string"))
-		}
-	}
-	body := ""
-	body += "interval_dur, err := time.ParseDuration(interval)\n"
-	body += "if err != nil {\n"
-	body += "\tlog.Fatal(err)\n"
-	body += "}\n"
-	body += "cb := circuitbreaker.New(\n"
-	body += "\tcircuitbreaker.WithFailOnContextCancel(true),\n"
-	body += "\tcircuitbreaker.WithFailOnContextDeadline(true),\n"
-	body += "\tcircuitbreaker.WithCounterResetInterval(interval_dur),\n"
-	body += "\tcircuitbreaker.WithTripFunc(circuitbreaker.NewTripFuncFailureRate(1000,0.1)),\n"
-	body += ")\n"
-	body += "return &" + name + "{client:client, cb:cb}\n"
-	return parser.FuncInfo{Name: func_name, Args: args, Return: ret_args}, body
-}
+var breakerWrapper = &wrapper{Plugin: "CircuitBreaker", Suffix: "CircuitBreaker", Receiver: "cbm", Field: "breaker", Type: "stdlib.CircuitBreaker", Constructor: "circuit_breaker.breaker", Method: "circuit_breaker.client_method"}
 
 func (m *CircuitBreakerModifier) ModifyClient(prev_node *ServiceImplInfo) (*ServiceImplInfo, error) {
-	bodies := make(map[string]string)
-	newMethods := copyMap(prev_node.Methods)
-	receiver_name := "cbm"
-	for name, method := range newMethods {
-		combineMethodInfo(&method, prev_node)
-		bodies[name] = m.generateClientMethodBody(receiver_name, method)
-		newMethods[name] = method
+	breaker, err := stdlib.ParseCircuitBreaker(prev_node.InstanceName, valueParams(m.Params))
+	if err != nil {
+		return nil, err
 	}
-	next_node_args := []parser.ArgInfo{}
-	name := prev_node.BaseName + "CircuitBreaker"
-	return &ServiceImplInfo{Name: name, ReceiverName: receiver_name, Methods: newMethods, MethodBodies: bodies, BaseName: prev_node.BaseName, Imports: m.getImports(), InstanceName: prev_node.InstanceName, NextNodeMethodArgs: next_node_args}, nil
+	return breakerWrapper.modifyClient(prev_node, parser.SortedKeys(breaker.Methods))
 }
 
 func (m *CircuitBreakerModifier) AddClientConstructor(node *ServiceImplInfo, next_node *ServiceImplInfo) {
-	constructor, body := m.getClientConstructor(node.Name, next_node)
-	node.MethodBodies[constructor.Name] = body
-	node.Constructors = []parser.FuncInfo{constructor}
-	node.Fields = m.getClientFields(next_node.Name)
+	breakerWrapper.addClientConstructor(node, next_node, m.Params)
 }
 
 func (m *CircuitBreakerModifier) GetPluginName() string {
@@ -129,3 +54,40 @@ func (m *CircuitBreakerModifier) GetPluginName() string {
 func GenerateCircuitBreakerModifier(node parser.ModifierNode) Modifier {
 	return &CircuitBreakerModifier{NewNoOpSourceCodeModifier(), get_params(node)}
 }
+
+func init() {
+	breakerWrapper.register(`breaker, err := stdlib.ParseCircuitBreaker("{{.Instance}}", map[string]string{ {{- range .Options}}"{{.}}": {{.}}, {{end -}} })
+if err != nil {
+	log.Fatal(err)
+}
+return breaker
+`, `{{range .Results}}var {{.Name}} {{.Type}}
+{{end}}{{.Err}} = {{.Receiver}}.breaker.Do({{.Context}}, "{{.Method.Name}}", func() error {
+	{{join .ResultNames ", "}} = {{.Receiver}}.client.{{.Method.Name}}({{join .ArgNames ", "}})
+	return {{.Err}}
+})
+return {{join .ResultNames ", "}}`)
+	RegisterModifier(ModifierPlugin{
+		Name:        "CircuitBreaker",
+		Description: "Stops calling a service while too many of its calls fail",
+		Params: []ParamInfo{
+			{Name: "interval", Description: "Interval after which the failure counters are reset, e.g. 10s", Required: true},
+			{Name: "trip", Description: "Strategy that trips the breaker: failure_rate (default), consecutive_failures or slow_call_rate"},
+			{Name: "threshold", Description: "Ratio of failed or slow calls (0.1 and 0.5 by default), or number of consecutive failures (5 by default), that trips the breaker"},
+			{Name: "min_samples", Description: "Calls counted before the rate strategies may trip the breaker, 1000 by default"},
+			{Name: "slow_call", Description: "Duration from which a call is slow, 1s by default"},
+			{Name: "open_timeout", Description: "Time the breaker stays open before it lets probe calls pass, 10s by default"},
+			{Name: "half_open_probes", Description: "Probe calls that have to succeed to close the breaker, 1 by default"},
+			{Name: "per_method", Description: "Whether every method has a breaker of its own, False by default"},
+			{Name: "methods", Description: "Options of single methods, which get a breaker of their own, e.g. {\"Leaf\": {\"trip\": \"consecutive_failures\"}}"},
+		},
+		Generate: GenerateCircuitBreakerModifier,
+		IRType:   &CircuitBreakerModifier{},
+		Validate: func(values map[string]string) error {
+			_, err := stdlib.ParseCircuitBreaker("", values)
+			return err
+		},
+		// Retry calls the breaker once per attempt, and doesn't retry once it is open
+		Order: ModifierOrder{Outside: []string{"Retry"}},
+	})
+}
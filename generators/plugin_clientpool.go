@@ -1,7 +1,9 @@
 package generators
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
@@ -34,21 +36,49 @@ func (m *ClientPoolModifier) GetName() string {
 	return "ClientPool"
 }
 
+// Whether calls give up once no client was free for max_wait
+func (m *ClientPoolModifier) hasMaxWait() bool {
+	_, ok := valueParams(m.Params)["max_wait"]
+	return ok
+}
+
 func (m *ClientPoolModifier) getImports() []parser.ImportInfo {
 	var imports []parser.ImportInfo
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: MODULE_ROOT + "/stdlib"})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "context"})
 	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "strconv"})
+	if m.hasMaxWait() {
+		imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "log"})
+		imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "time"})
+	}
 	return imports
 }
 
 func (m *ClientPoolModifier) generateClientMethodBody(receiverName string, finfo parser.FuncInfo) string {
 	var arg_names []string
-	for _, arg := range finfo.Args {
+	ctx := "context.Background()"
+	for idx, arg := range finfo.Args {
+		if idx == 0 && arg.Type.String() == "context.Context" {
+			ctx = arg.Name
+		}
 		arg_names = append(arg_names, arg.Name)
 	}
 	body := ""
-	body += "client := " + receiverName + ".pool.Pop()\n"
+	// Methods without results can't return the error of a pool without a free client, they wait until one is free
+	if m.hasMaxWait() && len(finfo.Return) > 0 {
+		body += "client, err := " + receiverName + ".pool.PopContext(" + ctx + ")\n"
+		body += "if err != nil {\n"
+		var ret_names []string
+		for idx, ret := range finfo.Return[:len(finfo.Return)-1] {
+			ret_name := fmt.Sprintf("ret_%d", idx)
+			body += "\tvar " + ret_name + " " + ret.String() + "\n"
+			ret_names = append(ret_names, ret_name)
+		}
+		body += "\treturn " + strings.Join(append(ret_names, "err"), ", ") + "\n"
+		body += "}\n"
+	} else {
+		body += "client := " + receiverName + ".pool.Pop()\n"
+	}
 	body += "defer " + receiverName + ".pool.Push(client)\n"
 	body += "return client." + finfo.Name + "(" + strings.Join(arg_names, ", ") + ")"
 	return body
@@ -91,6 +121,13 @@ func (m *ClientPoolModifier) getClientConstructor(name string, next_node *Servic
 		body += "\tpool.StartMetricsThread(service_name)\n"
 		body += "}\n"
 	}
+	if m.hasMaxWait() {
+		body += "max_wait_duration, err := time.ParseDuration(max_wait)\n"
+		body += "if err != nil {\n"
+		body += "\tlog.Fatal(err)\n"
+		body += "}\n"
+		body += "pool.MaxWait = max_wait_duration\n"
+	}
 	body += "return &" + name + "{pool: pool}\n"
 	return parser.FuncInfo{Name: func_name, Args: args, Return: ret_args}, body
 }
@@ -154,3 +191,26 @@ func (m *ClientPoolModifier) GetPluginName() string {
 func GenerateClientPoolModifier(node parser.ModifierNode) Modifier {
 	return &ClientPoolModifier{NewNoOpSourceCodeModifier(), get_params(node)}
 }
+
+func init() {
+	RegisterModifier(ModifierPlugin{
+		Name:        "ClientPool",
+		Description: "Shares a pool of clients of a service",
+		Params: []ParamInfo{
+			{Name: "max_clients", Description: "Number of clients in the pool", Required: true},
+			{Name: "metrics", Description: "Collects metrics of the pool if True"},
+			{Name: "max_wait", Description: "Time a call waits for a free client before it fails, e.g. 100ms"},
+		},
+		Generate: GenerateClientPoolModifier,
+		IRType:   &ClientPoolModifier{},
+		Validate: func(values map[string]string) error {
+			if value, ok := values["max_wait"]; ok {
+				if wait, err := time.ParseDuration(value); err != nil || wait <= 0 {
+					return fmt.Errorf("max_wait: %s is not a duration, e.g. 100ms", value)
+				}
+			}
+			return nil
+		},
+		Order: ModifierOrder{Innermost: true},
+	})
+}
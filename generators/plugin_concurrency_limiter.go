@@ -0,0 +1,122 @@
+package generators
+
+import (
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+)
+
+type ConcurrencyLimiterModifier struct {
+	*NoOpSourceCodeModifier
+	Params []Parameter
+}
+
+func (m *ConcurrencyLimiterModifier) Accept(v Visitor) {
+	v.VisitModifier(v, m)
+}
+
+func (m *ConcurrencyLimiterModifier) GetParams() []Parameter {
+	return m.Params
+}
+
+func (n *ConcurrencyLimiterModifier) GetNodes(nodeType string) []Node {
+	var nodes []Node
+	if getType(n) == nodeType {
+		nodes = append(nodes, n)
+	}
+	for _, child := range n.Params {
+		nodes = append(nodes, child.GetNodes(nodeType)...)
+	}
+	return nodes
+}
+
+func (m *ConcurrencyLimiterModifier) GetName() string {
+	return "ConcurrencyLimiterModifier"
+}
+
+func (m *ConcurrencyLimiterModifier) GetPluginName() string {
+	return "ConcurrencyLimiter"
+}
+
+var concurrencyLimiterWrapper = &wrapper{Plugin: "ConcurrencyLimiter", Suffix: "ConcurrencyLimiter", Receiver: "cl", Field: "limiter", Type: "stdlib.ConcurrencyLimiter", Constructor: "concurrency_limiter.constructor", Method: "concurrency_limiter.method"}
+
+// The clients share the template of the methods of the servers
+var clientConcurrencyLimiterWrapper = &wrapper{Plugin: "ConcurrencyLimiter", Suffix: "ClientConcurrencyLimiter", Receiver: "clm", Field: "limiter", Type: "stdlib.ConcurrencyLimiter", Constructor: "concurrency_limiter.limiter", Method: "concurrency_limiter.method"}
+
+// Returns the names of the methods with options of their own
+func (m *ConcurrencyLimiterModifier) getMethods(prev_node *ServiceImplInfo) ([]string, error) {
+	limiter, err := stdlib.ParseConcurrencyLimiter(prev_node.InstanceName, valueParams(m.Params))
+	if err != nil {
+		return nil, err
+	}
+	return parser.SortedKeys(limiter.Methods), nil
+}
+
+func (m *ConcurrencyLimiterModifier) ModifyServer(prev_node *ServiceImplInfo) (*ServiceImplInfo, error) {
+	methods, err := m.getMethods(prev_node)
+	if err != nil {
+		return nil, err
+	}
+	return concurrencyLimiterWrapper.modifyServer(prev_node, methods, m.Params)
+}
+
+func (m *ConcurrencyLimiterModifier) ModifyClient(prev_node *ServiceImplInfo) (*ServiceImplInfo, error) {
+	methods, err := m.getMethods(prev_node)
+	if err != nil {
+		return nil, err
+	}
+	return clientConcurrencyLimiterWrapper.modifyClient(prev_node, methods)
+}
+
+func (m *ConcurrencyLimiterModifier) AddClientConstructor(node *ServiceImplInfo, next_node *ServiceImplInfo) {
+	clientConcurrencyLimiterWrapper.addClientConstructor(node, next_node, m.Params)
+}
+
+func GenerateConcurrencyLimiterModifier(node parser.ModifierNode) Modifier {
+	return &ConcurrencyLimiterModifier{NewNoOpSourceCodeModifier(), get_params(node)}
+}
+
+func init() {
+	concurrencyLimiterWrapper.register(`limiter, err := stdlib.ParseConcurrencyLimiter("{{.Instance}}", map[string]string{ {{- range .Options}}"{{.}}": {{.}}, {{end -}} })
+if err != nil {
+	log.Fatal(err)
+}
+return &{{.Name}}{ {{- .Next}}: {{.Next}}, limiter: limiter}
+`, `{{range .Results}}var {{.Name}} {{.Type}}
+{{end}}{{.Err}} = {{.Receiver}}.limiter.Do({{.Context}}, "{{.Method.Name}}", func() error {
+	{{join .ResultNames ", "}} = {{.Receiver}}.{{.Next}}.{{.Method.Name}}({{join .ArgNames ", "}})
+	return {{.Err}}
+})
+return {{join .ResultNames ", "}}`)
+	parser.RegisterTemplate("concurrency_limiter.limiter", `limiter, err := stdlib.ParseConcurrencyLimiter("{{.Instance}}", map[string]string{ {{- range .Options}}"{{.}}": {{.}}, {{end -}} })
+if err != nil {
+	log.Fatal(err)
+}
+return limiter
+`)
+	RegisterModifier(ModifierPlugin{
+		Name:        "ConcurrencyLimiter",
+		Description: "Caps the calls of a client, or to a service, that are in flight at once",
+		Params: []ParamInfo{
+			{Name: "limit", Description: "Calls in flight at once, the initial limit of the adaptive algorithms", Required: true},
+			{Name: "max_wait", Description: "Time a call waits for a free slot before it is rejected, e.g. 50ms, 0 by default"},
+			{Name: "max_queue", Description: "Calls that may wait at once, unbounded by default"},
+			{Name: "adaptive", Description: "Algorithm that adapts the limit: fixed (default), aimd or gradient"},
+			{Name: "min_limit", Description: "Lowest limit of the adaptive algorithms, 1 by default"},
+			{Name: "max_limit", Description: "Highest limit of the adaptive algorithms, 10 times the limit by default"},
+			{Name: "backoff", Description: "Factor of the limit once a call is dropped, 0.9 by default, aimd only"},
+			{Name: "slow_call", Description: "Duration from which a call counts as dropped, 1s by default, aimd only"},
+			{Name: "per_method", Description: "Whether every method has a limit of its own, False by default"},
+			{Name: "methods", Description: "Options of single methods, which get a limit of their own, e.g. {\"Leaf\": {\"limit\": 5}}"},
+		},
+		Generate: GenerateConcurrencyLimiterModifier,
+		IRType:   &ConcurrencyLimiterModifier{},
+		Validate: func(values map[string]string) error {
+			_, err := stdlib.ParseConcurrencyLimiter("", values)
+			return err
+		},
+		// The clients of a limited service get limits of their own as client modifiers
+		ServerOnly: true,
+		// Thrift doesn't return the rejected calls as such to the clients
+		Frameworks: []string{"grpc", "default"},
+	})
+}
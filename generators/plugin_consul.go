@@ -178,3 +178,24 @@ func (n *ConsulNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "r", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "Consul"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterModifier(ModifierPlugin{
+		Name:        "ConsulModifier",
+		Description: "Registers a service with a registry",
+		Params: []ParamInfo{
+			{Name: "reg", Description: "Registry instance", Required: true},
+			{Name: "service_name", Description: "Name the service is registered with", Required: true},
+			{Name: "service_id", Description: "ID the service is registered with", Required: true},
+		},
+		Generate: GenerateConsulModifier,
+		IRType:   &ConsulModifier{},
+	})
+	RegisterComponent(ComponentPlugin{
+		Type:         "ConsulRegistry",
+		AbstractType: "Registry",
+		Description:  "Consul service registry",
+		Generate:     GenerateConsulNode,
+		IRType:       &ConsulNode{},
+	})
+}
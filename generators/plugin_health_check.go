@@ -1,8 +1,6 @@
 package generators
 
 import (
-	"strings"
-
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
 )
 
@@ -44,14 +42,19 @@ func (m *HealthCheckModifier) getImports() []parser.ImportInfo {
 	return imports
 }
 
-func (m *HealthCheckModifier) generateServerMethodBody(receiver_name string, method parser.FuncInfo) string {
-	var body string
+// Data of the health_check templates
+type healthCheckMethodData struct {
+	Receiver string          // Receiver of the generated method, the wrapped service is its service field
+	Method   parser.FuncInfo // Method of the service
+	ArgNames []string        // Names of the arguments of Method
+}
+
+func (m *HealthCheckModifier) generateServerMethodBody(receiver_name string, method parser.FuncInfo) (string, error) {
 	var arg_names []string
 	for _, arg := range method.Args {
 		arg_names = append(arg_names, arg.Name)
 	}
-	body += "return " + receiver_name + ".service." + method.Name + "(" + strings.Join(arg_names, ", ") + ")\n"
-	return body
+	return parser.ExecuteTemplate("health_check.server_method", healthCheckMethodData{Receiver: receiver_name, Method: method, ArgNames: arg_names})
 }
 
 func (m *HealthCheckModifier) getConstructor(name string, prev_node *ServiceImplInfo) (parser.FuncInfo, string) {
@@ -74,12 +77,21 @@ func (m *HealthCheckModifier) ModifyServer(prev_node *ServiceImplInfo) (*Service
 	newMethods := copyMap(prev_node.Methods)
 	receiver_name := "hc"
 	for name, method := range newMethods {
-		bodies[name] = m.generateServerMethodBody(receiver_name, method)
+		body, err := m.generateServerMethodBody(receiver_name, method)
+		if err != nil {
+			return nil, err
+		}
+		bodies[name] = body
 	}
 
 	// Add a new Health method
-	newMethods["Health"] = parser.FuncInfo{Name: "Health", Args: []parser.ArgInfo{parser.GetContextArg("ctx")}, Return: []parser.ArgInfo{parser.GetBasicArg("", "string"), parser.GetErrorArg("")}, Public: true}
-	bodies["Health"] = "return \"Healthy\", nil"
+	health := parser.FuncInfo{Name: "Health", Args: []parser.ArgInfo{parser.GetContextArg("ctx")}, Return: []parser.ArgInfo{parser.GetBasicArg("", "string"), parser.GetErrorArg("")}, Public: true}
+	body, err := parser.ExecuteTemplate("health_check.health_method", healthCheckMethodData{Receiver: receiver_name, Method: health, ArgNames: []string{"ctx"}})
+	if err != nil {
+		return nil, err
+	}
+	newMethods["Health"] = health
+	bodies["Health"] = body
 
 	name := prev_node.BaseName + "HealthChecker"
 	constructor, body := m.getConstructor(name, prev_node)
@@ -91,3 +103,14 @@ func GenerateHealthCheckModifier(node parser.ModifierNode) Modifier {
 	params := get_params(node)
 	return &HealthCheckModifier{NewNoOpSourceCodeModifier(), params}
 }
+
+func init() {
+	parser.RegisterTemplate("health_check.server_method", "return {{.Receiver}}.service.{{.Method.Name}}({{join .ArgNames \", \"}})\n")
+	parser.RegisterTemplate("health_check.health_method", "return \"Healthy\", nil")
+	RegisterModifier(ModifierPlugin{
+		Name:        "HealthChecker",
+		Description: "Adds a Health method to a service",
+		Generate:    GenerateHealthCheckModifier,
+		IRType:      &HealthCheckModifier{},
+	})
+}
@@ -85,3 +85,13 @@ func (n *JaegerNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "t", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "Jaeger"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterComponent(ComponentPlugin{
+		Type:         "JaegerTracer",
+		AbstractType: "Tracer",
+		Description:  "Jaeger tracing backend",
+		Generate:     GenerateJaegerNode,
+		IRType:       &JaegerNode{},
+	})
+}
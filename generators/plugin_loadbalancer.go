@@ -177,6 +177,15 @@ func (n *LoadBalancerNode) GetDependencies() map[string]Dependency {
 	return dependencies
 }
 
+// Data of the load_balancer templates
+type loadBalancerData struct {
+	Name     string          // Name of the generated client
+	BaseType string          // Service type of the balanced clients
+	Receiver string          // Receiver of the generated methods, the load balancer is its balancer field
+	Method   parser.FuncInfo // Method of the service, not set for the constructor
+	ArgNames []string        // Names of the arguments of Method
+}
+
 func (n *LoadBalancerNode) GenerateClientNode(info *parser.ServiceInfo) {
 	methods := copyMap(info.Methods)
 	con_name := "New" + n.Name
@@ -187,27 +196,47 @@ func (n *LoadBalancerNode) GenerateClientNode(info *parser.ServiceInfo) {
 	imports := []parser.ImportInfo{parser.ImportInfo{ImportName: "", FullName: "genz/stdlib"}, parser.ImportInfo{ImportName: "", FullName: "context"}, parser.ImportInfo{ImportName: "", FullName: "spec/services"}}
 	fields := []parser.ArgInfo{parser.GetPointerArg("balancer", "stdlib.LoadBalancer[services."+n.BaseTypeName+"]")}
 
-	cons_body := ""
-	cons_body += "lb := stdlib.NewLoadBalancer[services." + n.BaseTypeName + "](clients)\n"
-	cons_body += "return &" + n.Name + "{balancer:lb}\n"
+	receiverName := "lb"
+	cons_body, err := parser.ExecuteTemplate("load_balancer.constructor", loadBalancerData{Name: n.Name, BaseType: n.BaseTypeName, Receiver: receiverName})
+	if err != nil {
+		parser.Abort(err)
+	}
 	bodies := make(map[string]string)
 	bodies[con_name] = cons_body
-	receiverName := "lb"
 	for name, method := range methods {
 		var arg_names []string
 		for _, arg := range method.Args {
 			arg_names = append(arg_names, arg.Name)
 		}
-		body := ""
-		body += "client := " + receiverName + ".balancer.PickClient()\n"
-		body += "return client." + name + "(" + strings.Join(arg_names, ", ") + ")"
+		body, err := parser.ExecuteTemplate("load_balancer.client_method", loadBalancerData{Name: n.Name, BaseType: n.BaseTypeName, Receiver: receiverName, Method: method, ArgNames: arg_names})
+		if err != nil {
+			parser.Abort(err)
+		}
 		bodies[name] = body
 	}
-	var values []string
-	deps := n.GetDependencies()
-	for d, _ := range deps {
-		values = append(values, d)
-	}
+	values := parser.SortedKeys(n.GetDependencies())
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: receiverName, Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, Values: values, BaseName: n.BaseTypeName, PluginName: "LoadBalancer"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	parser.RegisterTemplate("load_balancer.constructor", "lb := stdlib.NewLoadBalancer[services.{{.BaseType}}](clients)\nreturn &{{.Name}}{balancer:lb}\n")
+	parser.RegisterTemplate("load_balancer.client_method", "client := {{.Receiver}}.balancer.PickClient()\nreturn client.{{.Method.Name}}({{join .ArgNames \", \"}})")
+	RegisterModifier(ModifierPlugin{
+		Name:        "LoadBalancer",
+		Description: "Balances the calls of a client over the replicas of a service",
+		Generate:    GenerateLoadBalancerModifier,
+		IRType:      &LoadBalancerModifier{},
+	})
+	RegisterComponent(ComponentPlugin{
+		Type:         "LoadBalancer",
+		AbstractType: "LoadBalancer",
+		Description:  "Balances calls over a list of service instances",
+		Params: []ParamInfo{
+			{Name: "clients", Description: "Service instances the calls are balanced over", Required: true},
+			{Name: "basetype", Description: "Service type of the instances", Required: true},
+		},
+		Generate: GenerateLoadBalancerNode,
+		IRType:   &LoadBalancerNode{},
+	})
+}
@@ -48,3 +48,16 @@ func GenerateLocalMetricNode(node parser.DetailNode) Node {
 
 	return &LocalMetricNode{Name: node.Name, Params: params, ClientModifiers: cmodifiers, ServerModifiers: smodifiers}
 }
+
+func init() {
+	RegisterComponent(ComponentPlugin{
+		Type:         "LocalMetricCollector",
+		AbstractType: "MetricCollector",
+		Description:  "Writes the collected metrics to a local file",
+		Params: []ParamInfo{
+			{Name: "filename", Description: "File the metrics are written to"},
+		},
+		Generate: GenerateLocalMetricNode,
+		IRType:   &LocalMetricNode{},
+	})
+}
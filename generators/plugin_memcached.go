@@ -84,3 +84,13 @@ func (n *MemcachedNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "c", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "Memcached"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterComponent(ComponentPlugin{
+		Type:         "Memcached",
+		AbstractType: "Cache",
+		Description:  "Memcached cache",
+		Generate:     GenerateMemcachedNode,
+		IRType:       &MemcachedNode{},
+	})
+}
@@ -39,3 +39,16 @@ func (m *MetricModifier) GetPluginName() string {
 func GenerateMetricModifier(node parser.ModifierNode) Modifier {
 	return &MetricModifier{NewNoOpSourceCodeModifier(), get_params(node)}
 }
+
+func init() {
+	RegisterModifier(ModifierPlugin{
+		Name:        "MetricModifier",
+		Description: "Reports metrics of the requests of a service to a metric collector",
+		Params: []ParamInfo{
+			{Name: "collector", Description: "Metric collector instance", Required: true},
+			{Name: "metrics", Description: "Metrics that are collected, e.g. [\"latency\"]"},
+		},
+		Generate: GenerateMetricModifier,
+		IRType:   &MetricModifier{},
+	})
+}
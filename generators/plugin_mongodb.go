@@ -1,7 +1,6 @@
 package generators
 
 import (
-	"os"
 	"path"
 	"strconv"
 	"strings"
@@ -34,11 +33,6 @@ func (s *MongoScriptGenerator) Generate(out_dir string) error {
 	}
 	for name, members := range replHandler.Names {
 		filename := path.Join(out_dir, "rs-init-"+name+".sh")
-		outf, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-		if err != nil {
-			return err
-		}
-		defer outf.Close()
 		primary_name := replHandler.PrimaryNames[name]
 		script_body := ""
 		script_body += "#!/bin/bash\n\nDELAY=25\n\n"
@@ -70,7 +64,7 @@ func (s *MongoScriptGenerator) Generate(out_dir string) error {
 		script_body += "rs.initiate(config, {force: true});\nEOF\n\n"
 		script_body += "echo \"****** Waiting for ${DELAY} seconds for replicaset configuration to be applied ****** \"\n\n"
 		script_body += "sleep $DELAY"
-		_, err = outf.WriteString(script_body)
+		err := parser.WriteFile(filename, []byte(script_body), parser.ScriptMode)
 		if err != nil {
 			return err
 		}
@@ -188,3 +182,17 @@ func (n *MongoDBNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "c", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "MongoDB"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterComponent(ComponentPlugin{
+		Type:         "MongoDB",
+		AbstractType: "NoSQLDatabase",
+		Description:  "MongoDB database",
+		Params: []ParamInfo{
+			{Name: "replica_set", Description: "Name of the replica set the database is part of"},
+			{Name: "is_primary", Description: "The database is the primary of its replica set if True"},
+		},
+		Generate: GenerateMongoDBNode,
+		IRType:   &MongoDBNode{},
+	})
+}
@@ -84,3 +84,13 @@ func (n *MySqlDBNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "c", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "MySQL"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterComponent(ComponentPlugin{
+		Type:         "MySqlDB",
+		AbstractType: "RelationalDB",
+		Description:  "MySQL database",
+		Generate:     GenerateMySqlDBNode,
+		IRType:       &MySqlDBNode{},
+	})
+}
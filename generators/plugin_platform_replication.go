@@ -58,3 +58,15 @@ func (m *PlatformReplicationModifier) ModifyDeployInfo(depInfo *deploy.DeployInf
 func GeneratePlaformReplicationModifier(node parser.ModifierNode) Modifier {
 	return &PlatformReplicationModifier{NewNoOpDeployerModifier(), get_params(node)}
 }
+
+func init() {
+	RegisterModifier(ModifierPlugin{
+		Name:        "PlatformReplication",
+		Description: "Runs replicas of the container of a service",
+		Params: []ParamInfo{
+			{Name: "num_replicas", Description: "Number of replicas", Required: true},
+		},
+		Generate: GeneratePlaformReplicationModifier,
+		IRType:   &PlatformReplicationModifier{},
+	})
+}
@@ -110,3 +110,16 @@ func (n *RabbitMQNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "c", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "RabbitMQ"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterComponent(ComponentPlugin{
+		Type:         "RabbitMQ",
+		AbstractType: "Queue",
+		Description:  "RabbitMQ queue",
+		Params: []ParamInfo{
+			{Name: "queue_name", Description: "Name of the queue"},
+		},
+		Generate: GenerateRabbitMQNode,
+		IRType:   &RabbitMQNode{},
+	})
+}
@@ -0,0 +1,84 @@
+package generators
+
+import (
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+)
+
+type RateLimiterModifier struct {
+	*NoOpSourceCodeModifier
+	Params []Parameter
+}
+
+func (m *RateLimiterModifier) Accept(v Visitor) {
+	v.VisitModifier(v, m)
+}
+
+func (m *RateLimiterModifier) GetParams() []Parameter {
+	return m.Params
+}
+
+func (n *RateLimiterModifier) GetNodes(nodeType string) []Node {
+	var nodes []Node
+	if getType(n) == nodeType {
+		nodes = append(nodes, n)
+	}
+	for _, child := range n.Params {
+		nodes = append(nodes, child.GetNodes(nodeType)...)
+	}
+	return nodes
+}
+
+func (m *RateLimiterModifier) GetName() string {
+	return "RateLimiterModifier"
+}
+
+func (m *RateLimiterModifier) GetPluginName() string {
+	return "RateLimiter"
+}
+
+var rateLimiterWrapper = &wrapper{Plugin: "RateLimiter", Suffix: "RateLimiter", Receiver: "rl", Field: "limiter", Type: "stdlib.RateLimiter", Constructor: "rate_limiter.constructor", Method: "rate_limiter.server_method"}
+
+func (m *RateLimiterModifier) ModifyServer(prev_node *ServiceImplInfo) (*ServiceImplInfo, error) {
+	limiter, err := stdlib.ParseRateLimiter(valueParams(m.Params))
+	if err != nil {
+		return nil, err
+	}
+	return rateLimiterWrapper.modifyServer(prev_node, parser.SortedKeys(limiter.Methods), m.Params)
+}
+
+func GenerateRateLimiterModifier(node parser.ModifierNode) Modifier {
+	return &RateLimiterModifier{NewNoOpSourceCodeModifier(), get_params(node)}
+}
+
+func init() {
+	rateLimiterWrapper.register(`limiter, err := stdlib.ParseRateLimiter(map[string]string{ {{- range .Options}}"{{.}}": {{.}}, {{end -}} })
+if err != nil {
+	log.Fatal(err)
+}
+return &{{.Name}}{service: service, limiter: limiter}
+`, `{{range .Results}}var {{.Name}} {{.Type}}
+{{end}}if {{.Err}} = {{.Receiver}}.limiter.Allow({{.Context}}, "{{.Method.Name}}"); {{.Err}} != nil {
+	return {{join .ResultNames ", "}}
+}
+return {{.Receiver}}.service.{{.Method.Name}}({{join .ArgNames ", "}})`)
+	RegisterModifier(ModifierPlugin{
+		Name:        "RateLimiter",
+		Description: "Rejects the calls to a service that exceed a rate limit",
+		Params: []ParamInfo{
+			{Name: "rate", Description: "Calls per second, e.g. 100", Required: true},
+			{Name: "burst", Description: "Calls that may pass at once, the rate rounded up by default"},
+			{Name: "per_caller", Description: "True to limit every caller on its own"},
+			{Name: "methods", Description: "Limits of single methods, e.g. {\"Leaf\": {\"rate\": 10}}"},
+		},
+		Generate: GenerateRateLimiterModifier,
+		IRType:   &RateLimiterModifier{},
+		Validate: func(values map[string]string) error {
+			_, err := stdlib.ParseRateLimiter(values)
+			return err
+		},
+		ServerOnly: true,
+		// Thrift neither returns the rejected calls as such to the clients nor tells the server who the caller is
+		Frameworks: []string{"grpc", "default"},
+	})
+}
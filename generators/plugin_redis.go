@@ -84,3 +84,13 @@ func (n *RedisNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "c", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "Redis"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterComponent(ComponentPlugin{
+		Type:         "RedisCache",
+		AbstractType: "Cache",
+		Description:  "Redis cache",
+		Generate:     GenerateRedisNode,
+		IRType:       &RedisNode{},
+	})
+}
@@ -1,10 +1,8 @@
 package generators
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
 )
 
 type RetryModifier struct {
@@ -39,82 +37,52 @@ func (m *RetryModifier) GetPluginName() string {
 	return "Retry"
 }
 
-func (m *RetryModifier) generateClientMethodBody(receiverName string, finfo parser.FuncInfo) string {
-	var body string
-	var arg_names []string
-	for _, arg := range finfo.Args {
-		arg_names = append(arg_names, arg.Name)
-	}
-	var ret_names []string
-	// Add ret_names and vars to the body
-	for idx, ret := range finfo.Return {
-		ret_name := fmt.Sprintf("ret_%d", idx)
-		ret_names = append(ret_names, ret_name)
-		body += "var " + ret_name + " " + ret.String() + "\n"
-	}
-	body += "var i int64\n"
-	body += "for i = 0; i < " + receiverName + ".max_retries; i++ {\n"
-	body += "\t" + strings.Join(ret_names, ",") + " = " + receiverName + ".client." + finfo.Name + "(" + strings.Join(arg_names, ", ") + ")\n"
-	body += "\tif " + ret_names[len(ret_names)-1] + " == nil {\n"
-	body += "\t\tbreak\n"
-	body += "\t}\n"
-	body += "}\n"
-	body += "return " + strings.Join(ret_names, ", ")
-	return body
-}
-
-func (m *RetryModifier) getImports() []parser.ImportInfo {
-	var imports []parser.ImportInfo
-	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "context"})
-	imports = append(imports, parser.ImportInfo{ImportName: "", FullName: "strconv"})
-	return imports
-}
-
-func (m *RetryModifier) getClientFields(next_node_name string) []parser.ArgInfo {
-	var fields []parser.ArgInfo
-	fields = append(fields, parser.GetPointerArg("client", next_node_name))
-	fields = append(fields, parser.GetBasicArg("max_retries", "int64"))
-	return fields
-}
-
-func (m *RetryModifier) getClientConstructor(name string, next_node *ServiceImplInfo) (parser.FuncInfo, string) {
-	func_name := "New" + name
-	ret_args := []parser.ArgInfo{parser.GetPointerArg("", name)}
-	args := []parser.ArgInfo{}
-	args = append(args, parser.GetPointerArg("client", next_node.Name))
-	for _, param := range m.Params {
-		switch ptype := param.(type) {
-		case *ValueParameter:
-			args = append(args, parser.GetBasicArg(ptype.KeywordName, "string"))
-		}
-	}
-	body := ""
-	body += "max_retries_num, _ := strconv.ParseInt(max_retries, 10, 64)\n"
-	body += "return &" + name + "{client:client, max_retries: max_retries_num}\n"
-	return parser.FuncInfo{Name: func_name, Args: args, Return: ret_args}, body
-}
+var retryWrapper = &wrapper{Plugin: "Retry", Suffix: "Retrier", Receiver: "rm", Field: "retrier", Type: "stdlib.Retrier", Constructor: "retry.retrier", Method: "retry.client_method"}
 
 func (m *RetryModifier) ModifyClient(prev_node *ServiceImplInfo) (*ServiceImplInfo, error) {
-	bodies := make(map[string]string)
-	newMethods := copyMap(prev_node.Methods)
-	receiver_name := "rm"
-	for name, method := range newMethods {
-		combineMethodInfo(&method, prev_node)
-		bodies[name] = m.generateClientMethodBody(receiver_name, method)
-		newMethods[name] = method
+	retrier, err := stdlib.ParseRetrier(valueParams(m.Params))
+	if err != nil {
+		return nil, err
 	}
-	next_node_args := []parser.ArgInfo{}
-	name := prev_node.BaseName + "Retrier"
-	return &ServiceImplInfo{Name: name, ReceiverName: receiver_name, Methods: newMethods, MethodBodies: bodies, BaseName: prev_node.BaseName, Imports: m.getImports(), InstanceName: prev_node.InstanceName, NextNodeMethodArgs: next_node_args}, nil
+	return retryWrapper.modifyClient(prev_node, parser.SortedKeys(retrier.Methods))
 }
 
 func (m *RetryModifier) AddClientConstructor(node *ServiceImplInfo, next_node *ServiceImplInfo) {
-	constructor, body := m.getClientConstructor(node.Name, next_node)
-	node.MethodBodies[constructor.Name] = body
-	node.Constructors = []parser.FuncInfo{constructor}
-	node.Fields = m.getClientFields(next_node.Name)
+	retryWrapper.addClientConstructor(node, next_node, m.Params)
 }
 
 func GenerateRetryModifier(node parser.ModifierNode) Modifier {
 	return &RetryModifier{NewNoOpSourceCodeModifier(), get_params(node)}
 }
+
+func init() {
+	retryWrapper.register(`retrier, err := stdlib.ParseRetrier(map[string]string{ {{- range .Options}}"{{.}}": {{.}}, {{end -}} })
+if err != nil {
+	log.Fatal(err)
+}
+return retrier
+`, `{{range .Results}}var {{.Name}} {{.Type}}
+{{end}}{{.Err}} = {{.Receiver}}.retrier.Do({{.Context}}, "{{.Method.Name}}", func() error {
+	{{join .ResultNames ", "}} = {{.Receiver}}.client.{{.Method.Name}}({{join .ArgNames ", "}})
+	return {{.Err}}
+})
+return {{join .ResultNames ", "}}`)
+	RegisterModifier(ModifierPlugin{
+		Name:        "Retry",
+		Description: "Retries the failed calls of a client with a backoff",
+		Params: []ParamInfo{
+			{Name: "max_retries", Description: "Maximum number of attempts of a call", Required: true},
+			{Name: "backoff", Description: "Delay between attempts: none, constant, exponential (default) or jitter (decorrelated)"},
+			{Name: "base_delay", Description: "Delay before the first retry, 10ms by default"},
+			{Name: "max_delay", Description: "Upper bound of the delay between attempts, 1s by default"},
+			{Name: "budget", Description: "Maximum ratio of retries to calls of the client, 0.2 by default, 0 disables the budget"},
+			{Name: "methods", Description: "Options of single methods, e.g. {\"Leaf\": {\"max_retries\": 3}}"},
+		},
+		Generate: GenerateRetryModifier,
+		IRType:   &RetryModifier{},
+		Validate: func(values map[string]string) error {
+			_, err := stdlib.ParseRetrier(values)
+			return err
+		},
+	})
+}
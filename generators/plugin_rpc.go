@@ -188,3 +188,19 @@ func (m *RPCServerModifier) AddClientConstructor(node *ServiceImplInfo, next_nod
 	node.Fields = fields
 	node.Structs = structs
 }
+
+func init() {
+	RegisterModifier(ModifierPlugin{
+		Name:        "RPCServer",
+		Description: "Serves the methods of a service over an RPC framework",
+		Params: []ParamInfo{
+			{Name: "framework", Description: "RPC framework, grpc or aiothrift", Required: true},
+			{Name: "timeout", Description: "Timeout of the client calls, e.g. 1s"},
+			{Name: "metrics", Description: "Collects latency metrics of the calls if True"},
+			{Name: "resolver", Description: "Resolves the address of the service with the registry, e.g. consul"},
+		},
+		Generate: GenerateRPCServerModifier,
+		IRType:   &RPCServerModifier{},
+		Order:    ModifierOrder{Outermost: true},
+	})
+}
@@ -0,0 +1,106 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/parser"
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib"
+)
+
+type TimeoutModifier struct {
+	*NoOpSourceCodeModifier
+	Params   []Parameter
+	warnings []string
+}
+
+func (m *TimeoutModifier) Accept(v Visitor) {
+	v.VisitModifier(v, m)
+}
+
+func (m *TimeoutModifier) GetParams() []Parameter {
+	return m.Params
+}
+
+func (n *TimeoutModifier) GetNodes(nodeType string) []Node {
+	var nodes []Node
+	if getType(n) == nodeType {
+		nodes = append(nodes, n)
+	}
+	for _, child := range n.Params {
+		nodes = append(nodes, child.GetNodes(nodeType)...)
+	}
+	return nodes
+}
+
+func (m *TimeoutModifier) GetName() string {
+	return "TimeoutModifier"
+}
+
+func (m *TimeoutModifier) GetPluginName() string {
+	return "Timeout"
+}
+
+func (m *TimeoutModifier) TakeWarnings() []string {
+	warnings := m.warnings
+	m.warnings = nil
+	return warnings
+}
+
+// Methods without a context can't be cancelled, their calls run to completion and the timeout has no effect
+var timeoutWrapper = &wrapper{Plugin: "Timeout", Suffix: "Timeout", Receiver: "tm", Field: "timeout", Type: "stdlib.Timeout", Constructor: "timeout.timeout", Method: "timeout.client_method"}
+
+func (m *TimeoutModifier) ModifyClient(prev_node *ServiceImplInfo) (*ServiceImplInfo, error) {
+	timeout, err := stdlib.ParseTimeout(valueParams(m.Params))
+	if err != nil {
+		return nil, err
+	}
+	var uncancellable []string
+	for _, name := range parser.SortedKeys(prev_node.Methods) {
+		method := prev_node.Methods[name]
+		if method.Public && (len(method.Args) == 0 || method.Args[0].Type.String() != "context.Context") {
+			uncancellable = append(uncancellable, name)
+		}
+	}
+	if len(uncancellable) > 0 {
+		m.warnings = append(m.warnings, fmt.Sprintf("Timeout: the methods %s of %s have no context.Context argument, their calls can't be cancelled", strings.Join(uncancellable, ", "), prev_node.BaseName))
+	}
+	return timeoutWrapper.modifyClient(prev_node, parser.SortedKeys(timeout.Methods))
+}
+
+func (m *TimeoutModifier) AddClientConstructor(node *ServiceImplInfo, next_node *ServiceImplInfo) {
+	timeoutWrapper.addClientConstructor(node, next_node, m.Params)
+}
+
+func GenerateTimeoutModifier(node parser.ModifierNode) Modifier {
+	return &TimeoutModifier{NoOpSourceCodeModifier: NewNoOpSourceCodeModifier(), Params: get_params(node)}
+}
+
+func init() {
+	// The timeout option is an argument of the constructor too
+	timeoutWrapper.register(`{{.Receiver}}_timeout, {{.Receiver}}_err := stdlib.ParseTimeout(map[string]string{ {{- range .Options}}"{{.}}": {{.}}, {{end -}} })
+if {{.Receiver}}_err != nil {
+	log.Fatal({{.Receiver}}_err)
+}
+return {{.Receiver}}_timeout
+`, `{{range .Results}}var {{.Name}} {{.Type}}
+{{end}}{{.Err}} = {{.Receiver}}.timeout.Do({{.Context}}, "{{.Method.Name}}", func({{.ContextArg}} context.Context) error {
+	{{join .ResultNames ", "}} = {{.Receiver}}.client.{{.Method.Name}}({{join .ArgNames ", "}})
+	return {{.Err}}
+})
+return {{join .ResultNames ", "}}`)
+	RegisterModifier(ModifierPlugin{
+		Name:        "Timeout",
+		Description: "Cancels the calls of a client that outlast a deadline",
+		Params: []ParamInfo{
+			{Name: "timeout", Description: "Deadline of the calls, e.g. 500ms", Required: true},
+			{Name: "methods", Description: "Deadlines of single methods, e.g. {\"Leaf\": {\"timeout\": \"100ms\"}}"},
+		},
+		Generate: GenerateTimeoutModifier,
+		IRType:   &TimeoutModifier{},
+		Validate: func(values map[string]string) error {
+			_, err := stdlib.ParseTimeout(values)
+			return err
+		},
+	})
+}
@@ -278,3 +278,17 @@ func GenerateTracerModifier(node parser.ModifierNode) Modifier {
 	}
 	return &TracerModifier{NewNoOpSourceCodeModifier(), tracerInstanceName, get_params(node)}
 }
+
+func init() {
+	RegisterModifier(ModifierPlugin{
+		Name:        "TracerModifier",
+		Description: "Traces the requests of a service with a tracer component",
+		Params: []ParamInfo{
+			{Name: "tracer", Description: "Tracer instance that collects the spans", Required: true},
+			{Name: "service_name", Description: "Name of the service in the traces"},
+			{Name: "sampling_rate", Description: "Fraction of the requests that are traced"},
+		},
+		Generate: GenerateTracerModifier,
+		IRType:   &TracerModifier{},
+	})
+}
@@ -158,3 +158,18 @@ func (m *WebServerModifier) AddClientConstructor(node *ServiceImplInfo, next_nod
 	node.Fields = fields
 	node.Structs = structs
 }
+
+func init() {
+	RegisterModifier(ModifierPlugin{
+		Name:        "WebServer",
+		Description: "Serves the methods of a service over HTTP",
+		Params: []ParamInfo{
+			{Name: "framework", Description: "Web framework, e.g. default", Required: true},
+			{Name: "timeout", Description: "Timeout of the client calls, e.g. 1s"},
+			{Name: "metrics", Description: "Collects latency metrics of the calls if True"},
+		},
+		Generate: GenerateWebServerModifier,
+		IRType:   &WebServerModifier{},
+		Order:    ModifierOrder{Outermost: true},
+	})
+}
@@ -374,3 +374,22 @@ func (n *XTraceNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "t", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "XTrace"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterModifier(ModifierPlugin{
+		Name:        "XTraceModifier",
+		Description: "Propagates X-Trace metadata through the requests of a service",
+		Params: []ParamInfo{
+			{Name: "tracer", Description: "XTracer instance that collects the events", Required: true},
+		},
+		Generate: GenerateXTraceModifier,
+		IRType:   &XTraceModifier{},
+	})
+	RegisterComponent(ComponentPlugin{
+		Type:         "XTracerImpl",
+		AbstractType: "XTracer",
+		Description:  "X-Trace server",
+		Generate:     GenerateXTraceNode,
+		IRType:       &XTraceNode{},
+	})
+}
@@ -85,3 +85,13 @@ func (n *ZipkinNode) GenerateClientNode(info *parser.ImplInfo) {
 	client_node := &ServiceImplInfo{Name: n.Name, ReceiverName: "t", Methods: methods, Constructors: []parser.FuncInfo{constructor}, Imports: imports, Fields: fields, MethodBodies: bodies, PluginName: "Zipkin"}
 	n.ASTNodes = append(n.ASTNodes, client_node)
 }
+
+func init() {
+	RegisterComponent(ComponentPlugin{
+		Type:         "ZipkinTracer",
+		AbstractType: "Tracer",
+		Description:  "Zipkin tracing backend",
+		Generate:     GenerateZipkinNode,
+		IRType:       &ZipkinNode{},
+	})
+}
@@ -7,7 +7,6 @@ require (
 	github.com/go-redis/redis/v8 v8.11.4
 	github.com/go-sql-driver/mysql v1.6.0
 	github.com/hashicorp/consul/api v1.24.0
-	github.com/otiai10/copy v1.7.0
 	github.com/rabbitmq/amqp091-go v1.3.0
 	github.com/schollz/progressbar/v3 v3.13.1
 	github.com/tracingplane/tracingplane-go v0.0.0-20171025152126-8c4e6f79b148
@@ -20,6 +19,7 @@ require (
 	golang.org/x/exp v0.0.0-20230321023759-10a507213a29
 	golang.org/x/mod v0.6.0
 	gonum.org/v1/gonum v0.11.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -15,11 +15,22 @@ func _() {
 	_ = x[INTERFACE-4]
 	_ = x[CONTEXT-5]
 	_ = x[ERROR-6]
+	_ = x[INT-7]
+	_ = x[INT8-8]
+	_ = x[INT16-9]
+	_ = x[INT32-10]
+	_ = x[UINT-11]
+	_ = x[UINT8-12]
+	_ = x[UINT16-13]
+	_ = x[UINT32-14]
+	_ = x[UINT64-15]
+	_ = x[FLOAT32-16]
+	_ = x[BYTE-17]
 }
 
-const _BasicType_name = "INT64BOOLDOUBLESTRINGINTERFACECONTEXTERROR"
+const _BasicType_name = "INT64BOOLDOUBLESTRINGINTERFACECONTEXTERRORINTINT8INT16INT32UINTUINT8UINT16UINT32UINT64FLOAT32BYTE"
 
-var _BasicType_index = [...]uint8{0, 5, 9, 15, 21, 30, 37, 42}
+var _BasicType_index = [...]uint8{0, 5, 9, 15, 21, 30, 37, 42, 45, 49, 54, 59, 63, 68, 74, 80, 86, 93, 97}
 
 func (i BasicType) String() string {
 	if i < 0 || i >= BasicType(len(_BasicType_index)-1) {
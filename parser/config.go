@@ -1,17 +1,26 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
-)
 
+	"gopkg.in/yaml.v3"
+)
 
 type Address struct {
-	Name string `json:"name"`
-	Address string `json:"address"`
-	Port int `json:"port"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Port     int    `json:"port"`
 	Hostname string `json:"hostname"`
 }
 
@@ -22,38 +31,325 @@ type Node struct {
 }
 
 type EnvVariable struct {
-	Name string `json:"name"`
+	Name  string `json:"name"`
 	Value string `json:"value"`
 }
 
 type Environment struct {
-	Name string `json:"name"`
+	Name      string        `json:"name"`
 	Variables []EnvVariable `json:"variables"`
 }
-	
+
 type Config struct {
-	AppName string `json:"app_name"`
-	SrcDir string `json:"src_dir"`
-	OutDir string `json:"output_dir"`
-	WiringFile string `json:"wiring_file"`
-	Target string `json:"target"`
-	Addresses []Address `json:"addresses"`
-	Inventory []Node `json:"inventory"`
-	Environment []Environment `json:"environment"`
-}
-
-func ParseConfig (filename string) (*Config, error) {
-	jsonFile, err := os.Open(filename)
-	if err != nil {
-		return nil, err
+	AppName     string             `json:"app_name"`
+	SrcDir      string             `json:"src_dir"`
+	OutDir      string             `json:"output_dir"`
+	WiringFile  string             `json:"wiring_file"`
+	Target      string             `json:"target"`
+	Addresses   []Address          `json:"addresses"`
+	Inventory   []Node             `json:"inventory"`
+	Environment []Environment      `json:"environment"`
+	Profiles    map[string]Profile `json:"profiles"`
+	TemplateDir string             `json:"template_dir"`
+	Profile     string             `json:"-"` // Name of the applied profile, empty if none was selected
+	filename    string             // File the config was read from, used for diagnostics
+}
+
+// Code generation targets that are supported
+var configTargets = map[string]bool{"go": true}
+
+// ConfigError lists every problem found in a config file
+type ConfigError struct {
+	Filename string
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	var lines []string
+	for _, problem := range e.Problems {
+		lines = append(lines, e.Filename+": "+problem)
 	}
-	defer jsonFile.Close()
-	bytes, err := ioutil.ReadAll(jsonFile)
+	return strings.Join(lines, "\n")
+}
+
+// ParseConfig reads a JSON or, if the file ends in .yaml or .yml, a YAML config file. Unknown fields, missing
+// required fields and malformed values are reported together in a *ConfigError. ${NAME} and ${NAME:-default}
+// in string values are replaced with the value of the environment variable NAME.
+func ParseConfig(filename string) (*Config, error) {
+	return ParseConfigProfile(filename, "")
+}
+
+// ParseConfigProfile reads a config file like ParseConfig and applies the overlay of the named profile to it. No
+// overlay is applied if profile is empty.
+func ParseConfigProfile(filename string, profile string) (*Config, error) {
+	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	var config Config
-	json.Unmarshal(bytes, &config)
+	config, problems := decodeConfig(filename, data, profile)
+	if len(problems) > 0 {
+		return nil, &ConfigError{Filename: filename, Problems: problems}
+	}
 	config.AppName = strings.ToLower(config.AppName)
-	return &config, nil
-}
\ No newline at end of file
+	return config, nil
+}
+
+func decodeConfig(filename string, data []byte, profile string) (*Config, []string) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == ".yaml" || ext == ".yml" {
+		// YAML is converted to JSON so that both formats are decoded with the same rules
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, []string{err.Error()}
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		data = converted
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, []string{err.Error()}
+	}
+	var problems []string
+	checkConfigFields(doc, reflect.TypeOf(Config{}), "", &problems)
+
+	config := &Config{filename: filename}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(config); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			field := listIndex.ReplaceAllString(typeErr.Field, "[$1]")
+			problems = append(problems, fmt.Sprintf("%s: cannot use %s value as %s", field, typeErr.Value, typeErr.Type))
+		} else {
+			problems = append(problems, err.Error())
+		}
+		// The values of the config are incomplete, checking them would only report follow-up problems
+		return config, problems
+	}
+	if profile != "" {
+		if problem := config.applyProfile(profile); problem != "" {
+			return config, append(problems, problem)
+		}
+	}
+	interpolateConfig(reflect.ValueOf(config).Elem(), "", &problems)
+	problems = append(problems, config.validate()...)
+	return config, problems
+}
+
+// Returns the json name of every decoded field of a struct type
+func configFieldNames(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func joinConfigPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Reports every key of the decoded document that does not correspond to a field of t
+func checkConfigFields(doc interface{}, t reflect.Type, path string, problems *[]string) {
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := configFieldNames(t)
+		var keys []string
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if field, ok := fields[key]; ok {
+				checkConfigFields(obj[key], field.Type, joinConfigPath(path, key), problems)
+			} else {
+				*problems = append(*problems, "unknown field "+joinConfigPath(path, key))
+			}
+		}
+	case reflect.Slice:
+		if list, ok := doc.([]interface{}); ok {
+			for idx, elem := range list {
+				checkConfigFields(elem, t.Elem(), fmt.Sprintf("%s[%d]", path, idx), problems)
+			}
+		}
+	case reflect.Map:
+		if obj, ok := doc.(map[string]interface{}); ok {
+			var keys []string
+			for key := range obj {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				checkConfigFields(obj[key], t.Elem(), joinConfigPath(path, key), problems)
+			}
+		}
+	}
+}
+
+// Matches the list indices in the field paths of json errors, e.g. the 0 in addresses.0.port
+var listIndex = regexp.MustCompile(`\.([0-9]+)`)
+
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Replaces the environment variable references in every string of v
+func interpolateConfig(v reflect.Value, path string, problems *[]string) {
+	switch v.Kind() {
+	case reflect.String:
+		value := envReference.ReplaceAllStringFunc(v.String(), func(ref string) string {
+			match := envReference.FindStringSubmatch(ref)
+			if value, ok := os.LookupEnv(match[1]); ok {
+				return value
+			}
+			if match[2] != "" {
+				return match[3]
+			}
+			*problems = append(*problems, path+": environment variable "+match[1]+" is not set")
+			return ref
+		})
+		v.SetString(value)
+	case reflect.Struct:
+		fields := configFieldNames(v.Type())
+		var names []string
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			interpolateConfig(v.FieldByIndex(fields[name].Index), joinConfigPath(path, name), problems)
+		}
+	case reflect.Slice:
+		for idx := 0; idx < v.Len(); idx++ {
+			interpolateConfig(v.Index(idx), fmt.Sprintf("%s[%d]", path, idx), problems)
+		}
+	}
+}
+
+// Checks the required fields and the values of the decoded config
+func (c *Config) validate() []string {
+	var problems []string
+	required := []struct {
+		name  string
+		value string
+	}{{"app_name", c.AppName}, {"src_dir", c.SrcDir}, {"output_dir", c.OutDir}, {"wiring_file", c.WiringFile}, {"target", c.Target}}
+	for _, field := range required {
+		if field.value == "" {
+			problems = append(problems, "missing required field "+field.name)
+		}
+	}
+	if c.Target != "" && !configTargets[c.Target] {
+		problems = append(problems, "unsupported target "+c.Target)
+	}
+	names := make(map[string]bool)
+	for idx, addr := range c.Addresses {
+		path := fmt.Sprintf("addresses[%d]", idx)
+		if addr.Name == "" {
+			problems = append(problems, "missing required field "+path+".name")
+		} else if names[addr.Name] {
+			problems = append(problems, path+": duplicate address for "+addr.Name)
+		}
+		names[addr.Name] = true
+		if addr.Address == "" {
+			problems = append(problems, "missing required field "+path+".address")
+		}
+		if addr.Port <= 0 || addr.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("%s.port: %d is not a valid port", path, addr.Port))
+		}
+	}
+	for idx, env := range c.Environment {
+		if env.Name == "" {
+			problems = append(problems, fmt.Sprintf("missing required field environment[%d].name", idx))
+		}
+	}
+	for idx, node := range c.Inventory {
+		if node.Hostname == "" {
+			problems = append(problems, fmt.Sprintf("missing required field inventory[%d].hostname", idx))
+		}
+	}
+	return problems
+}
+
+// Reports addresses and environments of the config that don't name an instance of the wiring file
+func (c *Config) checkInstanceNames(root *MillenialNode, diags *Diagnostics) {
+	instances := make(map[string]bool)
+	var collect func(nodes []DetailNode)
+	collect = func(nodes []DetailNode) {
+		for _, node := range nodes {
+			instances[node.Name] = true
+			collect(node.Children)
+		}
+	}
+	for _, container := range root.Children {
+		collect(container.Children)
+	}
+	pos := token.Position{Filename: c.filename}
+	for idx, addr := range c.Addresses {
+		if !instances[addr.Name] {
+			diags.Errorf(pos, "addresses[%d].name: %s is not an instance in %s%s", idx, addr.Name, c.WiringFile, suggestInstance(addr.Name, instances))
+		}
+	}
+	for idx, env := range c.Environment {
+		if !instances[env.Name] {
+			diags.Errorf(pos, "environment[%d].name: %s is not an instance in %s%s", idx, env.Name, c.WiringFile, suggestInstance(env.Name, instances))
+		}
+	}
+}
+
+// Suggests the closest instance name for a misspelled one
+func suggestInstance(name string, instances map[string]bool) string {
+	best := ""
+	bestDistance := len(name)/2 + 1
+	var candidates []string
+	for instance := range instances {
+		candidates = append(candidates, instance)
+	}
+	sort.Strings(candidates)
+	for _, instance := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(instance)); d < bestDistance {
+			best, bestDistance = instance, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return " (did you mean " + best + "?)"
+}
+
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
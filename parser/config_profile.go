@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Profile is a named overlay of a config, e.g. for a dev, staging or production deployment. It is declared in the
+// "profiles" object of the config file and applied on top of the base config when it is selected.
+//
+// The overlay is merged as follows:
+//   - output_dir replaces the output directory of the base config if it is set.
+//   - addresses are merged by name. The address, port and hostname set in an overlay entry replace the ones of the
+//     base entry with the same name, fields that are not set are kept. Entries with new names are appended.
+//   - environment entries are merged by name and their variables are merged by variable name. Variables of the
+//     overlay replace the values of the base variables, new variables and entries are appended.
+//   - inventory nodes are merged by hostname. An overlay node replaces the base node with the same hostname
+//     entirely, new nodes are appended.
+//
+// The order of the base config is kept, appended entries follow in the order of the overlay.
+type Profile struct {
+	OutDir      string        `json:"output_dir"`
+	Addresses   []Address     `json:"addresses"`
+	Inventory   []Node        `json:"inventory"`
+	Environment []Environment `json:"environment"`
+}
+
+// Applies the overlay of the named profile. Returns a problem if the config has no such profile.
+func (c *Config) applyProfile(name string) string {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		var names []string
+		for profileName := range c.Profiles {
+			names = append(names, profileName)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return "unknown profile " + name + ", the config declares no profiles"
+		}
+		return "unknown profile " + name + " (available: " + strings.Join(names, ", ") + ")"
+	}
+	if profile.OutDir != "" {
+		c.OutDir = profile.OutDir
+	}
+	c.Addresses = mergeAddresses(c.Addresses, profile.Addresses)
+	c.Environment = mergeEnvironments(c.Environment, profile.Environment)
+	c.Inventory = mergeInventory(c.Inventory, profile.Inventory)
+	c.Profile = name
+	return ""
+}
+
+func mergeAddresses(base []Address, overlay []Address) []Address {
+	merged := append([]Address{}, base...)
+	for _, addr := range overlay {
+		idx := indexOf(len(merged), func(i int) bool { return merged[i].Name == addr.Name })
+		if idx < 0 {
+			merged = append(merged, addr)
+			continue
+		}
+		if addr.Address != "" {
+			merged[idx].Address = addr.Address
+		}
+		if addr.Port != 0 {
+			merged[idx].Port = addr.Port
+		}
+		if addr.Hostname != "" {
+			merged[idx].Hostname = addr.Hostname
+		}
+	}
+	return merged
+}
+
+func mergeEnvironments(base []Environment, overlay []Environment) []Environment {
+	merged := make([]Environment, len(base))
+	for i, env := range base {
+		merged[i] = Environment{Name: env.Name, Variables: append([]EnvVariable{}, env.Variables...)}
+	}
+	for _, env := range overlay {
+		idx := indexOf(len(merged), func(i int) bool { return merged[i].Name == env.Name })
+		if idx < 0 {
+			merged = append(merged, env)
+			continue
+		}
+		for _, variable := range env.Variables {
+			vars := merged[idx].Variables
+			if varIdx := indexOf(len(vars), func(i int) bool { return vars[i].Name == variable.Name }); varIdx >= 0 {
+				vars[varIdx].Value = variable.Value
+			} else {
+				merged[idx].Variables = append(vars, variable)
+			}
+		}
+	}
+	return merged
+}
+
+func mergeInventory(base []Node, overlay []Node) []Node {
+	merged := append([]Node{}, base...)
+	for _, node := range overlay {
+		if idx := indexOf(len(merged), func(i int) bool { return merged[i].Hostname == node.Hostname }); idx >= 0 {
+			merged[idx] = node
+		} else {
+			merged = append(merged, node)
+		}
+	}
+	return merged
+}
+
+// Returns the index of the first of n elements that matches, or -1
+func indexOf(n int, matches func(i int) bool) int {
+	for i := 0; i < n; i++ {
+		if matches(i) {
+			return i
+		}
+	}
+	return -1
+}
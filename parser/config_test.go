@@ -0,0 +1,246 @@
+package parser
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name string, content string) string {
+	filename := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func configProblems(t *testing.T, name string, content string) []string {
+	_, err := ParseConfig(writeConfig(t, name, content))
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("expected a *ConfigError, got %v", err)
+	}
+	return configErr.Problems
+}
+
+func TestParseConfigJSONAndYAML(t *testing.T) {
+	jsonConfig := `{
+		"app_name": "Leaf", "src_dir": "input", "output_dir": "output", "wiring_file": "instances.py", "target": "go",
+		"addresses": [{"name": "leafService", "address": "leafService", "port": 9500}],
+		"inventory": [{"hostname": "pinky05", "is_build_node": true}],
+		"environment": [{"name": "leafService", "variables": [{"name": "LEVEL", "value": "debug"}]}]
+	}`
+	yamlConfig := `
+app_name: Leaf
+src_dir: input
+output_dir: output
+wiring_file: instances.py
+target: go
+addresses:
+  - name: leafService
+    address: leafService
+    port: 9500
+inventory:
+  - hostname: pinky05
+    is_build_node: true
+environment:
+  - name: leafService
+    variables:
+      - {name: LEVEL, value: debug}
+`
+	fromJSON, err := ParseConfig(writeConfig(t, "config.json", jsonConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := ParseConfig(writeConfig(t, "config.yaml", yamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON.AppName != "leaf" || fromJSON.Addresses[0].Port != 9500 || !fromJSON.Inventory[0].IsBuildNode {
+		t.Errorf("unexpected config %+v", fromJSON)
+	}
+	fromJSON.filename, fromYAML.filename = "", ""
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("JSON and YAML configs differ:\n%+v\n%+v", fromJSON, fromYAML)
+	}
+}
+
+func TestParseConfigProblems(t *testing.T) {
+	problems := configProblems(t, "config.json", `{
+		"app_name": "leaf", "src_dir": "input", "wiring_file": "instances.py", "target": "rust", "hostname": "localhost",
+		"addresses": [
+			{"nmae": "leafService", "address": "leafService", "port": 9500},
+			{"name": "nonleafService", "address": "nonleafService", "port": 70000},
+			{"name": "nonleafService", "address": "nonleafService", "port": 9501}
+		]
+	}`)
+	expected := []string{
+		"unknown field addresses[0].nmae",
+		"unknown field hostname",
+		"missing required field output_dir",
+		"unsupported target rust",
+		"missing required field addresses[0].name",
+		"addresses[1].port: 70000 is not a valid port",
+		"addresses[2]: duplicate address for nonleafService",
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected problems\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(problems, "\n"))
+	}
+
+	problems = configProblems(t, "config.yaml", "app_name: leaf\nsrc_dir: input\noutput_dir: out\nwiring_file: w.py\ntarget: go\naddresses:\n  - name: leafService\n    address: leafService\n    port: high\n")
+	expected = []string{"addresses[0].port: cannot use string value as int"}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected problems %v, got %v", expected, problems)
+	}
+}
+
+func TestParseConfigInterpolation(t *testing.T) {
+	t.Setenv("BLUEPRINT_TEST_OUT", "/tmp/out")
+	config, err := ParseConfig(writeConfig(t, "config.yaml", `
+app_name: leaf
+src_dir: ${BLUEPRINT_TEST_SRC:-input}
+output_dir: ${BLUEPRINT_TEST_OUT}/leaf
+wiring_file: instances.py
+target: go
+addresses:
+  - {name: leafService, address: "${BLUEPRINT_TEST_HOST:-localhost}", port: 9500}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.SrcDir != "input" || config.OutDir != "/tmp/out/leaf" || config.Addresses[0].Address != "localhost" {
+		t.Errorf("unexpected config %+v", config)
+	}
+
+	problems := configProblems(t, "config.json", `{"app_name": "leaf", "src_dir": "${BLUEPRINT_TEST_UNSET}", "output_dir": "out", "wiring_file": "w.py", "target": "go"}`)
+	expected := []string{"src_dir: environment variable BLUEPRINT_TEST_UNSET is not set"}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected problems %v, got %v", expected, problems)
+	}
+}
+
+func TestConfigInstanceNames(t *testing.T) {
+	root := &MillenialNode{Children: []ContainerNode{{Children: []DetailNode{
+		{Name: "leafService"},
+		{Name: "docker", Children: []DetailNode{{Name: "nonleafService"}}},
+	}}}}
+	config := &Config{
+		WiringFile:  "instances.py",
+		Addresses:   []Address{{Name: "leafService"}, {Name: "nonleafServce"}, {Name: "xtracer"}},
+		Environment: []Environment{{Name: "nonleafService"}},
+		filename:    "config.json",
+	}
+	diags := NewDiagnostics()
+	config.checkInstanceNames(root, diags)
+	var messages []string
+	for _, item := range diags.Items {
+		messages = append(messages, item.String())
+	}
+	expected := []string{
+		"config.json: error: addresses[1].name: nonleafServce is not an instance in instances.py (did you mean nonleafService?)",
+		"config.json: error: addresses[2].name: xtracer is not an instance in instances.py",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(messages, "\n"))
+	}
+}
+
+const profileConfig = `{
+	"app_name": "leaf", "src_dir": "input", "output_dir": "output", "wiring_file": "instances.py", "target": "go",
+	"addresses": [
+		{"name": "leafService", "address": "leafService", "port": 9500},
+		{"name": "nonleafService", "address": "nonleafService", "port": 9501}
+	],
+	"inventory": [{"hostname": "pinky05", "is_build_node": true}, {"hostname": "pinky04"}],
+	"environment": [{"name": "leafService", "variables": [{"name": "LEVEL", "value": "debug"}, {"name": "CACHE", "value": "on"}]}],
+	"profiles": {
+		"prod": {
+			"output_dir": "output_prod",
+			"addresses": [
+				{"name": "nonleafService", "hostname": "pinky06", "port": 9601},
+				{"name": "jaegerTracer", "address": "jaegerTracer", "port": 14268}
+			],
+			"inventory": [{"hostname": "pinky05"}, {"hostname": "pinky06", "is_build_node": true}],
+			"environment": [
+				{"name": "leafService", "variables": [{"name": "LEVEL", "value": "info"}, {"name": "REPLICAS", "value": "3"}]},
+				{"name": "nonleafService", "variables": [{"name": "LEVEL", "value": "warn"}]}
+			]
+		},
+		"dev": {}
+	}
+}`
+
+func TestParseConfigProfile(t *testing.T) {
+	filename := writeConfig(t, "config.json", profileConfig)
+	base, err := ParseConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod, err := ParseConfigProfile(filename, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.OutDir != "output" || len(base.Addresses) != 2 || base.Profile != "" {
+		t.Errorf("the base config must not be changed by its profiles: %+v", base)
+	}
+	if prod.OutDir != "output_prod" || prod.Profile != "prod" {
+		t.Errorf("unexpected output directory %s for profile %s", prod.OutDir, prod.Profile)
+	}
+	expectedAddresses := []Address{
+		{Name: "leafService", Address: "leafService", Port: 9500},
+		{Name: "nonleafService", Address: "nonleafService", Port: 9601, Hostname: "pinky06"},
+		{Name: "jaegerTracer", Address: "jaegerTracer", Port: 14268},
+	}
+	if !reflect.DeepEqual(prod.Addresses, expectedAddresses) {
+		t.Errorf("expected addresses %+v, got %+v", expectedAddresses, prod.Addresses)
+	}
+	expectedInventory := []Node{{Hostname: "pinky05"}, {Hostname: "pinky04"}, {Hostname: "pinky06", IsBuildNode: true}}
+	if !reflect.DeepEqual(prod.Inventory, expectedInventory) {
+		t.Errorf("expected inventory %+v, got %+v", expectedInventory, prod.Inventory)
+	}
+	expectedEnvironment := []Environment{
+		{Name: "leafService", Variables: []EnvVariable{{"LEVEL", "info"}, {"CACHE", "on"}, {"REPLICAS", "3"}}},
+		{Name: "nonleafService", Variables: []EnvVariable{{"LEVEL", "warn"}}},
+	}
+	if !reflect.DeepEqual(prod.Environment, expectedEnvironment) {
+		t.Errorf("expected environment %+v, got %+v", expectedEnvironment, prod.Environment)
+	}
+
+	dev, err := ParseConfigProfile(filename, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev.Profile, base.Profile = "", ""
+	if !reflect.DeepEqual(dev, base) {
+		t.Errorf("an empty profile must not change the config:\n%+v\n%+v", dev, base)
+	}
+
+	_, err = ParseConfigProfile(filename, "staging")
+	expected := filename + ": unknown profile staging (available: dev, prod)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestParseConfigProfileProblems(t *testing.T) {
+	problems := configProblems(t, "config.yaml", `
+app_name: leaf
+src_dir: input
+output_dir: output
+wiring_file: instances.py
+target: go
+profiles:
+  prod:
+    output: output_prod
+    addresses:
+      - {name: leafService, address: "${BLUEPRINT_TEST_UNSET}", port: 9500}
+`)
+	// Unknown fields are reported for every profile, values only for the selected one
+	expected := []string{"unknown field profiles.prod.output"}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected problems %v, got %v", expected, problems)
+	}
+}
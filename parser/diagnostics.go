@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// A single problem found by the compiler. Pos refers either to the Go specification or to the wiring file.
+// Diagnostics that are not tied to a source location have an invalid (zero) position.
+type Diagnostic struct {
+	Severity Severity
+	Pos      token.Position
+	Message  string
+	Code     string // Name of the check that reported the diagnostic, e.g. a lint rule. Empty for compiler errors.
+}
+
+func (d Diagnostic) String() string {
+	message := d.Message
+	if d.Code != "" {
+		message += " [" + d.Code + "]"
+	}
+	if d.Pos.Filename == "" && !d.Pos.IsValid() {
+		return d.Severity.String() + ": " + message
+	}
+	return d.Pos.String() + ": " + d.Severity.String() + ": " + message
+}
+
+// Diagnostics collects the errors and warnings reported by the different compiler stages so that a
+// single run reports every problem instead of stopping at the first one.
+type Diagnostics struct {
+	Items []Diagnostic
+}
+
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{}
+}
+
+func (d *Diagnostics) Errorf(pos token.Position, format string, args ...interface{}) {
+	d.Items = append(d.Items, Diagnostic{Severity: SeverityError, Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *Diagnostics) Warnf(pos token.Position, format string, args ...interface{}) {
+	d.Items = append(d.Items, Diagnostic{Severity: SeverityWarning, Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// Reports a diagnostic found by the check called code
+func (d *Diagnostics) Report(severity Severity, code string, pos token.Position, format string, args ...interface{}) {
+	d.Items = append(d.Items, Diagnostic{Severity: severity, Pos: pos, Message: fmt.Sprintf(format, args...), Code: code})
+}
+
+func (d *Diagnostics) ErrorCount() int {
+	count := 0
+	for _, item := range d.Items {
+		if item.Severity == SeverityError {
+			count += 1
+		}
+	}
+	return count
+}
+
+func (d *Diagnostics) HasErrors() bool {
+	return d.ErrorCount() > 0
+}
+
+// Returns the diagnostics ordered by file and position. Diagnostics without a file come first.
+func (d *Diagnostics) Sorted() []Diagnostic {
+	items := make([]Diagnostic, len(d.Items))
+	copy(items, d.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		pi, pj := items[i].Pos, items[j].Pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		if pi.Line != pj.Line {
+			return pi.Line < pj.Line
+		}
+		return pi.Column < pj.Column
+	})
+	return items
+}
+
+// Prints every diagnostic in the "file:line:col: severity: message" format used by compilers
+func (d *Diagnostics) Print(w io.Writer) {
+	for _, item := range d.Sorted() {
+		fmt.Fprintln(w, item.String())
+	}
+}
+
+// The machine-readable form of a diagnostic
+type jsonDiagnostic struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Writes the sorted diagnostics as a JSON array
+func (d *Diagnostics) WriteJSON(w io.Writer) error {
+	items := []jsonDiagnostic{}
+	for _, item := range d.Sorted() {
+		items = append(items, jsonDiagnostic{File: item.Pos.Filename, Line: item.Pos.Line, Column: item.Pos.Column, Severity: item.Severity.String(), Code: item.Code, Message: item.Message})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
+
+// AbortError is raised through Abort by compiler stages that have no way of returning an error, such as
+// visitors. The pipeline recovers it and returns the wrapped error to the caller.
+type AbortError struct {
+	Err error
+}
+
+func (e *AbortError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AbortError) Unwrap() error {
+	return e.Err
+}
+
+// Abort stops the compilation. The arguments are formatted like log.Fatal.
+func Abort(args ...interface{}) {
+	panic(&AbortError{Err: errors.New(fmt.Sprint(args...))})
+}
+
+// Abortf stops the compilation. The arguments are formatted like log.Fatalf.
+func Abortf(format string, args ...interface{}) {
+	panic(&AbortError{Err: fmt.Errorf(format, args...)})
+}
+
+// RecoverAbort converts an abort into an error. It must be deferred directly by the caller.
+func RecoverAbort(err *error) {
+	if r := recover(); r != nil {
+		abort, ok := r.(*AbortError)
+		if !ok {
+			panic(r)
+		}
+		*err = abort
+	}
+}
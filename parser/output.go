@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Permissions of generated files. Only scripts are executable.
+const (
+	FileMode   os.FileMode = 0644
+	ScriptMode os.FileMode = 0755
+)
+
+var writtenFiles = struct {
+	sync.Mutex
+	names map[string]bool
+}{names: make(map[string]bool)}
+
+// WriteFile writes data to filename unless the file already holds exactly this data, so that unchanged files keep
+// their modification time. The permissions of an existing file are set to perm either way. Every file passed to
+// WriteFile is recorded, see WrittenFiles. Go source is formatted with go/format first, source that doesn't parse is
+// written as it is.
+func WriteFile(filename string, data []byte, perm os.FileMode) error {
+	if filepath.Ext(filename) == ".go" {
+		if formatted, err := format.Source(data); err == nil {
+			data = formatted
+		}
+	}
+	return writeFile(filename, data, perm)
+}
+
+func writeFile(filename string, data []byte, perm os.FileMode) error {
+	writtenFiles.Lock()
+	writtenFiles.names[filepath.Clean(filename)] = true
+	writtenFiles.Unlock()
+
+	if info, err := os.Stat(filename); err == nil && info.Mode().IsRegular() && info.Size() == int64(len(data)) {
+		if old, err := ioutil.ReadFile(filename); err == nil && bytes.Equal(old, data) {
+			if info.Mode().Perm() != perm {
+				return os.Chmod(filename, perm)
+			}
+			return nil
+		}
+	}
+	if err := ioutil.WriteFile(filename, data, perm); err != nil {
+		return err
+	}
+	// WriteFile only applies perm to new files
+	return os.Chmod(filename, perm)
+}
+
+// WrittenFiles returns the files passed to WriteFile since the last call to ResetWrittenFiles, sorted
+func WrittenFiles() []string {
+	writtenFiles.Lock()
+	defer writtenFiles.Unlock()
+	var names []string
+	for name := range writtenFiles.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ResetWrittenFiles() {
+	writtenFiles.Lock()
+	defer writtenFiles.Unlock()
+	writtenFiles.names = make(map[string]bool)
+}
+
+// OutputFile buffers the content of a generated file. Close writes the content with WriteFile.
+type OutputFile struct {
+	bytes.Buffer
+	Name string
+	Perm os.FileMode
+}
+
+func NewOutputFile(name string, perm os.FileMode) *OutputFile {
+	return &OutputFile{Name: name, Perm: perm}
+}
+
+func (f *OutputFile) Close() error {
+	return WriteFile(f.Name, f.Bytes(), f.Perm)
+}
+
+// CopyDir copies the files below src to dst like WriteFile, but leaves Go source unformatted. Files that are
+// executable in src are written with ScriptMode, all others with FileMode.
+func CopyDir(src string, dst string) error {
+	return filepath.Walk(src, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, name)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		perm := FileMode
+		if info.Mode().Perm()&0111 != 0 {
+			perm = ScriptMode
+		}
+		return writeFile(target, data, perm)
+	})
+}
+
+// SortedKeys returns the keys of m in increasing order. Generators range over the sorted keys of maps so that two runs
+// with the same input generate the same output.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -2,8 +2,11 @@ package parser
 
 import (
 	"go/ast"
+	"go/constant"
 	"go/parser"
+	"go/scanner"
 	"go/token"
+	"go/types"
 	"log"
 	"os"
 	"path/filepath"
@@ -59,6 +62,8 @@ type FuncInfo struct {
 	Args   []ArgInfo
 	Return []ArgInfo
 	Public bool
+	Doc    string         // Doc comment of the method in the specification
+	Pos    token.Position `json:"-"` // Position of the declaration in the specification, if any
 }
 
 func (f FuncInfo) GetArgNames() []string {
@@ -69,6 +74,20 @@ func (f FuncInfo) GetArgNames() []string {
 	return retvals
 }
 
+// Returns a name for a local variable of a generated body of f that no argument of f uses
+func (f FuncInfo) LocalName(name string) string {
+	for taken := true; taken; {
+		taken = false
+		for _, arg := range f.Args {
+			if arg.Name == name {
+				name += "_"
+				taken = true
+			}
+		}
+	}
+	return name
+}
+
 type RequireInfo struct {
 	Name    string
 	Path    string
@@ -79,6 +98,7 @@ type ServiceInfo struct {
 	Name    string
 	Methods map[string]FuncInfo
 	PkgPath string
+	Pos     token.Position `json:"-"`
 }
 
 type ImportInfo struct {
@@ -89,6 +109,7 @@ type ImportInfo struct {
 type StructInfo struct {
 	Name   string
 	Fields []ArgInfo
+	Doc    string
 }
 
 type EnumInfo struct {
@@ -96,6 +117,8 @@ type EnumInfo struct {
 	Type     string // Has to be a Basic Type!
 	PkgPath  string
 	ValNames []string
+	Values   []int64 // Constant values of ValNames
+	Doc      string
 }
 
 type ImplInfo struct {
@@ -105,15 +128,24 @@ type ImplInfo struct {
 	Interfaces       map[string]bool
 	PkgPath          string
 	ConstructorInfos []FuncInfo
+	Doc              string
+	Pos              token.Position `json:"-"`
 }
 
 type SpecParser struct {
 	config          *Config
 	logger          *log.Logger
+	fset            *token.FileSet
+	diags           *Diagnostics
+	checker         *specTypeChecker
+	srcDirs         []string       // Directories that are searched for packages
+	curPkg          *types.Package // Type checked package that is being parsed
 	Services        map[string]*ServiceInfo
 	Implementations map[string]*ImplInfo
 	Functions       map[string][]*FuncInfo
 	ExtraFunctions  []*FuncInfo
+	serviceEmbeds   map[string][]embeddedType // service name -> embedded interfaces
+	implEmbeds      map[string][]embeddedType // implementation name -> embedded types
 	RemoteTypes     map[string]*ImplInfo
 	Enums           map[string]*EnumInfo
 	PathPkgs        map[string]string
@@ -146,151 +178,47 @@ func (s *SpecParser) PrintFunctions() {
 	}
 }
 
+// Reports an error at the position of node in the specification
+func (s *SpecParser) errorf(node ast.Node, format string, args ...interface{}) {
+	s.diags.Errorf(s.fset.Position(node.Pos()), format, args...)
+}
+
+// Reports an unsupported type. The returned TypeInfo is a placeholder so that parsing can continue.
+func (s *SpecParser) typeError(node ast.Node, format string, args ...interface{}) TypeInfo {
+	s.errorf(node, format, args...)
+	return TypeInfo{}
+}
+
 // Helper parser functions
 func (s *SpecParser) getType(node *ast.Field) TypeInfo {
-	switch eType := node.Type.(type) {
-	case *ast.Ident:
-		return stringToType(eType.Name)
-	case *ast.ArrayType:
-		switch eltType := eType.Elt.(type) {
-		case *ast.Ident:
-			return arrayToType(eltType.Name)
-		case *ast.InterfaceType:
-			return arrayToType("interface")
-		case *ast.SelectorExpr:
-			var selXName string
-			var selName string
-			switch selXType := eltType.X.(type) {
-			case *ast.Ident:
-				selXName = selXType.Name
-			default:
-				s.logger.Fatal(reflect.TypeOf(selXType), "is not a valid option for a selector")
-			}
-			selName = eltType.Sel.Name
-			return arrayToType(selXName + "." + selName)
-		default:
-			s.logger.Fatal(reflect.TypeOf(eltType), " is not a valid Element Type for List")
-		}
-	case *ast.MapType:
-		var keyName string
-		var valName string
-		switch keyType := eType.Key.(type) {
-		case *ast.Ident:
-			keyName = keyType.Name
-		default:
-			s.logger.Fatal(reflect.TypeOf(keyType), " is not a valid Key Type for Map")
-		}
-		switch valType := eType.Value.(type) {
-		case *ast.Ident:
-			valName = valType.Name
-		case *ast.InterfaceType:
-			valName = "interface"
-		default:
-			s.logger.Fatal(reflect.TypeOf(valType), " is not a valid Val Type for Map")
-		}
-		return mapToType(keyName, valName)
-	case *ast.InterfaceType:
-		return interfaceToType()
-	case *ast.SelectorExpr:
-		var selXName string
-		var selName string
-		switch selXType := eType.X.(type) {
-		case *ast.Ident:
-			selXName = selXType.Name
-		default:
-			s.logger.Fatal(reflect.TypeOf(selXType), "is not a valid option for a selector")
-		}
-		selName = eType.Sel.Name
-		if selName == "Context" && selXName == "context" {
-			return ctxType()
-		}
-		return stringToType(selXName + "." + selName)
-	case *ast.StarExpr:
-		switch xType := eType.X.(type) {
-		case *ast.Ident:
-			valName := xType.Name
-			return pointerToType(valName)
-		case *ast.SelectorExpr:
-			var selXName string
-			var selName string
-			switch selXType := xType.X.(type) {
-			case *ast.Ident:
-				selXName = selXType.Name
-			default:
-				s.logger.Fatal(reflect.TypeOf(selXType), "is not a valid option for a selector")
-			}
-			selName = xType.Sel.Name
-			return pointerToType(selXName + "." + selName)
-		case *ast.IndexExpr:
-			// We can have templated types now
-			var exprName string
-			var indexName string
-			switch exprType := xType.X.(type) {
-			case *ast.Ident:
-				exprName = exprType.Name
-			case *ast.SelectorExpr:
-				var selXName string
-				switch selXType := exprType.X.(type) {
-				case *ast.Ident:
-					selXName = selXType.Name
-				default:
-					s.logger.Fatal(reflect.TypeOf(selXType), " is not a valid option for an index expression")
-				}
-				exprName = selXName + "." + exprType.Sel.Name
-			default:
-				s.logger.Fatal(reflect.TypeOf(exprType), "is not a valid option for an index expression")
-			}
-			switch indexType := xType.Index.(type) {
-			case *ast.Ident:
-				indexName = indexType.Name
-			case *ast.StarExpr:
-				switch indexStarxtype := indexType.X.(type) {
-				case *ast.Ident:
-					indexName = "*" + indexStarxtype.Name
-				default:
-					s.logger.Fatal(reflect.TypeOf(indexStarxtype), " is not a valid option for an index expression")
-				}
-			default:
-				s.logger.Fatal(reflect.TypeOf(indexType), "is not a valid option for an index expression")
-			}
-			return pointerToType(exprName + "[" + indexName + "]")
-		default:
-			s.logger.Fatal(reflect.TypeOf(xType), " is not a valid Val Type for a Pointer")
-		}
-	case *ast.Ellipsis:
-		switch eltType := eType.Elt.(type) {
-		case *ast.Ident:
-			return ellipsisToType(eltType.Name)
-		case *ast.InterfaceType:
-			return ellipsisToType("interface")
-		default:
-			s.logger.Fatal(reflect.TypeOf(eltType), " is not a valid Element Type for Ellipsis")
-		}
-	case *ast.ChanType:
-		switch valType := eType.Value.(type) {
-		case *ast.Ident:
-			valName := valType.Name
-			return chanToType(valName)
-		case *ast.SelectorExpr:
-			var selXName string
-			var selName string
-			switch selXType := valType.X.(type) {
-			case *ast.Ident:
-				selXName = selXType.Name
-			default:
-				s.logger.Fatal(reflect.TypeOf(selXType), "is not a valid option for a selector")
-			}
-			selName = valType.Sel.Name
-			return chanToType(selXName + "." + selName)
-		default:
-			s.logger.Fatal(reflect.TypeOf(valType), "is not a valid Val Type for a channel")
+	if ellipsis, ok := node.Type.(*ast.Ellipsis); ok {
+		// The checked type of a variadic parameter is a slice
+		elem := s.exprType(ellipsis.Elt)
+		return containerType(ELLIPSIS, nil, &elem)
+	}
+	return s.exprType(node.Type)
+}
+
+// Returns the declared names of a parameter, result or struct field. Unnamed and embedded fields have a single empty name.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		return []string{""}
+	}
+	var names []string
+	for _, name := range field.Names {
+		names = append(names, name.Name)
+	}
+	return names
+}
+
+// Returns the text of a doc comment without comment markers
+func docText(groups ...*ast.CommentGroup) string {
+	for _, group := range groups {
+		if group != nil {
+			return strings.TrimSpace(group.Text())
 		}
-	case *ast.FuncType:
-		return funcType()
-	default:
-		s.logger.Fatal(reflect.TypeOf(eType), " is not currently supported by the Parser")
 	}
-	return TypeInfo{}
+	return ""
 }
 
 func (s *SpecParser) getFuncInfo(node *ast.FuncType) FuncInfo {
@@ -299,22 +227,19 @@ func (s *SpecParser) getFuncInfo(node *ast.FuncType) FuncInfo {
 	if node.Params != nil {
 		// Params are not nil which means there are parameters
 		for _, param := range node.Params.List {
-			paramName := param.Names[0].Name
 			paramType := s.getType(param)
-			arg := ArgInfo{Name: paramName, Type: paramType}
-			args = append(args, arg)
+			for _, paramName := range fieldNames(param) {
+				args = append(args, ArgInfo{Name: paramName, Type: paramType})
+			}
 		}
 	}
 	if node.Results != nil {
 		// Results are not nil which means there are return vals
 		for _, retParam := range node.Results.List {
-			var resultName string
-			if retParam.Names != nil {
-				resultName = retParam.Names[0].Name
-			}
 			resultType := s.getType(retParam)
-			result := ArgInfo{Name: resultName, Type: resultType}
-			returns = append(returns, result)
+			for _, resultName := range fieldNames(retParam) {
+				returns = append(returns, ArgInfo{Name: resultName, Type: resultType})
+			}
 		}
 	}
 
@@ -326,13 +251,24 @@ func (s *SpecParser) parseTypes(path string, decl *ast.GenDecl) {
 	for _, spec := range decl.Specs {
 		typespec, ok := spec.(*ast.TypeSpec)
 		if !ok {
-			s.logger.Fatal("Parsing Error: Parser expected a type specification")
+			s.errorf(spec, "Parsing Error: Parser expected a type specification")
+			continue
 		}
 		name := typespec.Name.Name
+		// The doc comment of an unparenthesized declaration is attached to the declaration
+		doc := docText(typespec.Doc)
+		if doc == "" && !decl.Lparen.IsValid() {
+			doc = docText(decl.Doc)
+		}
 		switch t := typespec.Type.(type) {
 		case *ast.InterfaceType:
 			methods := make(map[string]FuncInfo)
 			for _, method := range t.Methods.List {
+				if len(method.Names) == 0 {
+					// Embedded interface, its methods are added once all packages have been parsed
+					s.addEmbeddedType(s.serviceEmbeds, name, method.Type)
+					continue
+				}
 				var funcInfo FuncInfo
 				switch methodType := method.Type.(type) {
 				case *ast.FuncType:
@@ -340,33 +276,35 @@ func (s *SpecParser) parseTypes(path string, decl *ast.GenDecl) {
 					funcInfo = s.getFuncInfo(methodType)
 					funcInfo.Name = funcName
 					funcInfo.Public = true
+					funcInfo.Doc = docText(method.Doc, method.Comment)
+					funcInfo.Pos = s.fset.Position(method.Pos())
 					methods[funcName] = funcInfo
 				default:
-					s.logger.Fatal("Parsing Error: Expected a function declaration in Interface Type and not", reflect.TypeOf(methodType))
+					s.errorf(method, "Parsing Error: Expected a function declaration in Interface Type and not %s", reflect.TypeOf(methodType))
 				}
 			}
-			serviceInfo := ServiceInfo{Name: name, Methods: methods, PkgPath: path}
+			serviceInfo := ServiceInfo{Name: name, Methods: methods, PkgPath: path, Pos: s.fset.Position(typespec.Pos())}
 			s.Services[name] = &serviceInfo
 
 		case *ast.StructType:
 			var fields []ArgInfo
 			if t.Fields.List != nil {
 				for _, field := range t.Fields.List {
-					var fieldName string
-					if field.Names != nil {
-						fieldName = field.Names[0].Name
+					if len(field.Names) == 0 {
+						s.addEmbeddedType(s.implEmbeds, name, field.Type)
 					}
 					typeString := s.getType(field)
-					fieldInfo := ArgInfo{Name: fieldName, Type: typeString}
-					fields = append(fields, fieldInfo)
+					for _, fieldName := range fieldNames(field) {
+						fields = append(fields, ArgInfo{Name: fieldName, Type: typeString})
+					}
 				}
 			}
-			implInfo := ImplInfo{Name: name, Fields: fields, PkgPath: path}
+			implInfo := ImplInfo{Name: name, Fields: fields, PkgPath: path, Doc: doc, Pos: s.fset.Position(typespec.Pos())}
 			s.Implementations[name] = &implInfo
 
 		case *ast.Ident:
 			// Potentially could be an enum!!!! (Otherwise it is just a simple typedef)
-			enumInfo := EnumInfo{Name: name, Type: t.Name, PkgPath: path}
+			enumInfo := EnumInfo{Name: name, Type: t.Name, PkgPath: path, Doc: doc}
 			s.Enums[name] = &enumInfo
 		}
 	}
@@ -377,6 +315,8 @@ func (s *SpecParser) parseFunctions(decl *ast.FuncDecl) {
 		// If the receiver is Nil then EITHER this function is not associated with a struct OR this function is a constructor for a struct
 		funcInfo := s.getFuncInfo(decl.Type)
 		funcInfo.Name = decl.Name.Name
+		funcInfo.Doc = docText(decl.Doc)
+		funcInfo.Pos = s.fset.Position(decl.Pos())
 		s.ExtraFunctions = append(s.ExtraFunctions, &funcInfo)
 		return
 	}
@@ -390,11 +330,14 @@ func (s *SpecParser) parseFunctions(decl *ast.FuncDecl) {
 	case *ast.Ident:
 		recvName = recvType.Name
 	default:
-		s.logger.Fatal("The receiver for a function should either be a star expression or an identifier")
+		s.errorf(recvType, "The receiver for a function should either be a star expression or an identifier")
+		return
 	}
 	name := decl.Name.Name
 	funcInfo := s.getFuncInfo(decl.Type)
 	funcInfo.Name = name
+	funcInfo.Doc = docText(decl.Doc)
+	funcInfo.Pos = s.fset.Position(decl.Pos())
 	runes := []rune(name)
 	funcInfo.Public = unicode.IsUpper(runes[0])
 	if v, ok := s.Functions[recvName]; ok {
@@ -431,36 +374,60 @@ func (s *SpecParser) associateFunctions() {
 
 func (s *SpecParser) parseConstBlock(path string, t *ast.GenDecl) {
 	var names []string
+	var values []int64
 	var eInfo *EnumInfo
 	for idx, spec := range t.Specs {
 		s.logger.Println(spec)
 		switch stype := spec.(type) {
 		case *ast.ValueSpec:
-			name := stype.Names[0].Name
-			names = append(names, name)
 			if idx == 0 {
 				// Check if the const block is an enum
 				if stype.Type == nil {
 					// No Type was attached so not an enum
 					return
 				}
-				type_expr := stype.Type.(*ast.Ident)
+				type_expr, ok := stype.Type.(*ast.Ident)
+				if !ok {
+					return
+				}
 				type_name := type_expr.Name
 				if v, ok := s.Enums[type_name]; ok {
 					eInfo = v
 				}
 			}
-			// Not sure if we really need to store the values or not....
+			for _, ident := range stype.Names {
+				if ident.Name == "_" {
+					continue
+				}
+				names = append(names, ident.Name)
+				values = append(values, s.constValue(ident, int64(len(values))))
+			}
 		}
 	}
 	if eInfo != nil {
 		eInfo.ValNames = names
+		eInfo.Values = values
+	}
+}
+
+// Returns the value of an integer constant. Constants that could not be type checked fall back to their position in the block.
+func (s *SpecParser) constValue(ident *ast.Ident, position int64) int64 {
+	if s.checker != nil {
+		if c, ok := s.checker.info.Defs[ident].(*types.Const); ok {
+			if value, exact := constant.Int64Val(constant.ToInt(c.Val())); exact {
+				return value
+			}
+		}
 	}
+	s.logger.Println("Unable to determine the value of constant", ident.Name, "using", position)
+	return position
 }
 
 func (s *SpecParser) parsePackages(pkgs map[string]*ast.Package) {
-	for path, pkg := range pkgs {
-		for _, file := range pkg.Files {
+	for _, path := range sortedDirs(pkgs) {
+		pkg := pkgs[path]
+		s.curPkg = s.checker.packageForDir(path)
+		for _, file := range sortedFiles(pkg) {
 			for _, decl := range file.Decls {
 				// Check if it is a GeneralDeclaration Block
 				switch t := decl.(type) {
@@ -536,17 +503,15 @@ func (s *SpecParser) parseRemoteTypeStructs() {
 }
 
 // Creates New Parser
-func NewSpecParser(config *Config, logger *log.Logger) *SpecParser {
-	return &SpecParser{config: config, logger: logger, Services: make(map[string]*ServiceInfo), Implementations: make(map[string]*ImplInfo), Functions: make(map[string][]*FuncInfo), ExtraFunctions: []*FuncInfo{}, RemoteTypes: make(map[string]*ImplInfo), PathPkgs: make(map[string]string), Enums: make(map[string]*EnumInfo)}
+func NewSpecParser(config *Config, logger *log.Logger, diags *Diagnostics) *SpecParser {
+	return &SpecParser{config: config, logger: logger, fset: token.NewFileSet(), diags: diags, srcDirs: []string{config.SrcDir, "./stdlib"}, Services: make(map[string]*ServiceInfo), Implementations: make(map[string]*ImplInfo), Functions: make(map[string][]*FuncInfo), ExtraFunctions: []*FuncInfo{}, serviceEmbeds: make(map[string][]embeddedType), implEmbeds: make(map[string][]embeddedType), RemoteTypes: make(map[string]*ImplInfo), PathPkgs: make(map[string]string), Enums: make(map[string]*EnumInfo)}
 }
 
 // Exported Parser function
 func (s *SpecParser) ParseSpec() {
 	s.logger.Println("Parsing specification")
-	var fset token.FileSet
 	all_pkgs := make(map[string]*ast.Package)
-	src_dirs := []string{s.config.SrcDir, "./stdlib"}
-	for _, srcdir := range src_dirs {
+	for _, srcdir := range s.srcDirs {
 
 		err := filepath.Walk(srcdir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
@@ -555,9 +520,15 @@ func (s *SpecParser) ParseSpec() {
 			if !info.IsDir() {
 				return nil
 			}
-			pkgs, err := parser.ParseDir(&fset, path, nil, parser.ParseComments)
+			pkgs, err := parser.ParseDir(s.fset, path, nil, parser.ParseComments)
 			if err != nil {
-				s.logger.Fatal(err)
+				if errs, ok := err.(scanner.ErrorList); ok {
+					for _, e := range errs {
+						s.diags.Errorf(e.Pos, "%s", e.Msg)
+					}
+				} else {
+					s.diags.Errorf(token.Position{Filename: path}, "%v", err)
+				}
 			}
 
 			for k, v := range pkgs {
@@ -568,11 +539,16 @@ func (s *SpecParser) ParseSpec() {
 			return nil
 		})
 		if err != nil {
-			s.logger.Fatal(err)
+			s.diags.Errorf(token.Position{Filename: srcdir}, "%v", err)
 		}
 	}
+	s.checker = newSpecTypeChecker(s.fset, all_pkgs)
+	s.checker.checkAll()
+	s.logTypeErrors()
 	s.parsePackages(all_pkgs)
 
+	s.resolveEmbeddedInterfaces()
+	s.promoteEmbeddedMethods()
 	s.associateImplementations()
 	s.parseRemoteTypeStructs()
 	s.logger.Println("# Total Service Declarations Found:", len(s.Services))
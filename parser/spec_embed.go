@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// An interface or struct embedded in a service interface or an implementation
+type embeddedType struct {
+	name string
+	expr ast.Expr
+	pkg  *types.Package // Type checked package the embedding type is declared in
+}
+
+// Returns the name of an embedded type, ignoring pointers, package qualifiers and type arguments.
+// Returns an empty string for the type set elements of constraint interfaces.
+func embeddedTypeName(expr ast.Expr) string {
+	switch eType := expr.(type) {
+	case *ast.Ident:
+		return eType.Name
+	case *ast.StarExpr:
+		return embeddedTypeName(eType.X)
+	case *ast.ParenExpr:
+		return embeddedTypeName(eType.X)
+	case *ast.SelectorExpr:
+		return eType.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedTypeName(eType.X)
+	case *ast.IndexListExpr:
+		return embeddedTypeName(eType.X)
+	}
+	return ""
+}
+
+func (s *SpecParser) addEmbeddedType(embeds map[string][]embeddedType, name string, expr ast.Expr) {
+	if embeddedName := embeddedTypeName(expr); embeddedName != "" {
+		embeds[name] = append(embeds[name], embeddedType{name: embeddedName, expr: expr, pkg: s.curPkg})
+	}
+}
+
+func sortedEmbedders(embeds map[string][]embeddedType) []string {
+	var names []string
+	for name := range embeds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Adds the methods of embedded interfaces to the services. Interfaces that are not declared in the
+// specification, e.g. io.Closer, are resolved with the type checker.
+func (s *SpecParser) resolveEmbeddedInterfaces() {
+	resolved := make(map[string]bool)
+	var resolve func(name string, visiting map[string]bool)
+	resolve = func(name string, visiting map[string]bool) {
+		if resolved[name] || visiting[name] {
+			return
+		}
+		visiting[name] = true
+		service := s.Services[name]
+		for _, embed := range s.serviceEmbeds[name] {
+			var methods map[string]FuncInfo
+			if embedded, ok := s.Services[embed.name]; ok && embed.name != name {
+				resolve(embed.name, visiting)
+				methods = embedded.Methods
+			} else if methods, ok = s.checkedInterfaceMethods(embed); !ok {
+				s.errorf(embed.expr, "Unable to resolve interface %s embedded in %s", types.ExprString(embed.expr), name)
+				continue
+			}
+			for funcName, funcInfo := range methods {
+				// Explicitly declared methods take precedence
+				if _, ok := service.Methods[funcName]; !ok {
+					service.Methods[funcName] = funcInfo
+				}
+			}
+		}
+		resolved[name] = true
+	}
+	for _, name := range sortedEmbedders(s.serviceEmbeds) {
+		if _, ok := s.Services[name]; ok {
+			resolve(name, make(map[string]bool))
+		}
+	}
+}
+
+// Returns the methods of an embedded interface using its checked type
+func (s *SpecParser) checkedInterfaceMethods(embed embeddedType) (map[string]FuncInfo, bool) {
+	if s.checker == nil {
+		return nil, false
+	}
+	tv, ok := s.checker.info.Types[embed.expr]
+	if !ok || !isValidType(tv.Type) {
+		return nil, false
+	}
+	iface, ok := tv.Type.Underlying().(*types.Interface)
+	if !ok {
+		return nil, false
+	}
+	methods := make(map[string]FuncInfo)
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		funcInfo, problem := signatureToFuncInfo(method.Type().(*types.Signature), embed.pkg)
+		if problem != "" {
+			s.errorf(embed.expr, "%s", problem)
+			continue
+		}
+		funcInfo.Name = method.Name()
+		funcInfo.Public = method.Exported()
+		methods[method.Name()] = funcInfo
+	}
+	return methods, true
+}
+
+// Adds the methods promoted from embedded structs and interfaces to the implementations. As in Go, methods at a
+// shallower depth shadow deeper ones and methods that are ambiguous at the same depth are not promoted.
+func (s *SpecParser) promoteEmbeddedMethods() {
+	promoted := make(map[string]map[string]FuncInfo)
+	for _, name := range sortedEmbedders(s.implEmbeds) {
+		impl, ok := s.Implementations[name]
+		if !ok {
+			continue
+		}
+		methods := make(map[string]FuncInfo)
+		for funcName, funcInfo := range impl.Methods {
+			methods[funcName] = funcInfo
+		}
+		blocked := make(map[string]bool)
+		seen := map[string]bool{name: true}
+		level := s.implEmbeds[name]
+		for len(level) > 0 {
+			found := make(map[string][]FuncInfo)
+			var next []embeddedType
+			for _, embed := range level {
+				if seen[embed.name] {
+					continue
+				}
+				seen[embed.name] = true
+				if embedded, ok := s.Implementations[embed.name]; ok {
+					for funcName, funcInfo := range embedded.Methods {
+						found[funcName] = append(found[funcName], funcInfo)
+					}
+					next = append(next, s.implEmbeds[embed.name]...)
+				} else if embedded, ok := s.Services[embed.name]; ok {
+					for funcName, funcInfo := range embedded.Methods {
+						found[funcName] = append(found[funcName], funcInfo)
+					}
+				} else {
+					s.logger.Println("Embedded type", embed.name, "of", name, "is not part of the specification")
+				}
+			}
+			for funcName, funcInfos := range found {
+				if _, ok := methods[funcName]; ok || blocked[funcName] {
+					continue
+				}
+				if len(funcInfos) > 1 {
+					blocked[funcName] = true
+					continue
+				}
+				methods[funcName] = funcInfos[0]
+			}
+			level = next
+		}
+		promoted[name] = methods
+	}
+	for name, methods := range promoted {
+		s.Implementations[name].Methods = methods
+	}
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"io/ioutil"
+	"log"
+	"reflect"
+	"testing"
+)
+
+func parseTestSpec(t *testing.T, dir string) *SpecParser {
+	diags := NewDiagnostics()
+	s := NewSpecParser(&Config{SrcDir: dir}, log.New(ioutil.Discard, "", 0), diags)
+	s.srcDirs = []string{dir}
+	s.ParseSpec()
+	if diags.HasErrors() {
+		t.Fatal(diags.Items)
+	}
+	return s
+}
+
+func TestSpecParserTypes(t *testing.T) {
+	s := parseTestSpec(t, "testdata/spectypes")
+	kitchen := s.Services["Kitchen"]
+	if kitchen == nil {
+		t.Fatal("Kitchen service not found")
+	}
+	expected := map[string][]string{
+		"Nested":  {"context.Context", "map[string][]Item", "[]*Item", "[][]int64", "[]byte", "error"},
+		"Numbers": {"context.Context", "int32", "float32", "uint16", "uint64", "int", "time.Time", "error"},
+		"Generic": {"context.Context", "Pair[string, Item]", "[]int", "map[string]time.Duration", "...int8", "error"},
+	}
+	for name, types := range expected {
+		method := kitchen.Methods[name]
+		var actual []string
+		for _, arg := range append(method.Args, method.Return...) {
+			actual = append(actual, arg.Type.String())
+		}
+		if len(actual) != len(types) {
+			t.Errorf("%s: expected %v, got %v", name, types, actual)
+			continue
+		}
+		for i := range types {
+			if actual[i] != types[i] {
+				t.Errorf("%s: expected %v, got %v", name, types, actual)
+				break
+			}
+		}
+	}
+
+	numbers := kitchen.Methods["Numbers"]
+	if numbers.Args[1].Type.Detail.TypeName != INT32 || numbers.Args[2].Type.Detail.TypeName != FLOAT32 || numbers.Args[5].Type.Detail.TypeName != INT {
+		t.Errorf("int32, float32 and int must be distinct basic types: %+v", numbers.Args)
+	}
+	if ret := numbers.Return[0].Type; ret.BaseType != USERDEFINED || ret.Detail.PkgPath != "time" || ret.IsUserDefined() {
+		t.Errorf("Unexpected type info for time.Time %+v", ret)
+	}
+
+	nested := kitchen.Methods["Nested"].Args[1].Type
+	if nested.BaseType != MAP || nested.ElemType().BaseType != LIST || nested.ElemType().ElemType().Detail.UserType != "Item" || !nested.IsUserDefined() {
+		t.Errorf("Unexpected type info for map[string][]Item %+v", nested)
+	}
+	if qualified := PrependPackageName("spectypes", nested); qualified.String() != "map[string][]spectypes.Item" {
+		t.Errorf("Unexpected qualified type %s", qualified)
+	}
+
+	item := s.Implementations["Item"]
+	if item == nil || len(item.Fields) != 3 || item.Fields[1].Name != "Owner" || item.Fields[1].Type.String() != "string" {
+		t.Errorf("Unexpected fields for Item %+v", item)
+	}
+}
+
+func TestSpecParserEmbedding(t *testing.T) {
+	s := parseTestSpec(t, "testdata/embedding")
+	service := s.Services["UserService"]
+	if service == nil {
+		t.Fatal("UserService service not found")
+	}
+	for _, name := range []string{"GetUser", "Reset", "Health", "Close"} {
+		if _, ok := service.Methods[name]; !ok {
+			t.Errorf("UserService is missing method %s", name)
+		}
+	}
+	if len(service.Methods) != 4 {
+		t.Errorf("expected 4 methods, got %v", service.Methods)
+	}
+	if ret := service.Methods["Close"].Return; len(ret) != 1 || ret[0].Type.String() != "error" {
+		t.Errorf("unexpected signature for Close: %v", ret)
+	}
+
+	impl := s.Implementations["UserServiceImpl"]
+	if impl == nil {
+		t.Fatal("UserServiceImpl not found")
+	}
+	for _, name := range []string{"UserService", "Admin", "Healthy"} {
+		if !impl.Interfaces[name] {
+			t.Errorf("UserServiceImpl should implement %s, implements %v", name, impl.Interfaces)
+		}
+	}
+	// Describe is declared at the same depth by AdminHelper and CloseHelper
+	if _, ok := impl.Methods["Describe"]; ok {
+		t.Error("ambiguous method Describe should not be promoted")
+	}
+	if len(impl.Fields) != 3 || impl.Fields[0].Name != "" || impl.Fields[0].Type.String() != "AdminHelper" {
+		t.Errorf("unexpected fields %v", impl.Fields)
+	}
+}
+
+func TestSpecParserEnumsAndDocs(t *testing.T) {
+	s := parseTestSpec(t, "testdata/enums")
+	expected := map[string][]int64{"Status": {1, 2, 12}, "Level": {1, 2, 4}}
+	for name, values := range expected {
+		enum := s.Enums[name]
+		if enum == nil || !reflect.DeepEqual(enum.Values, values) {
+			t.Errorf("%s: expected values %v, got %+v", name, values, enum)
+		}
+	}
+	if names := s.Enums["Status"].ValNames; !reflect.DeepEqual(names, []string{"Pending", "Shipped", "Delivered"}) {
+		t.Errorf("unexpected enum names %v", names)
+	}
+	if doc := s.Enums["Status"].Doc; doc != "Status of an order" {
+		t.Errorf("unexpected enum doc %q", doc)
+	}
+	if doc := s.Implementations["Order"].Doc; doc != "Order is sent between services" {
+		t.Errorf("unexpected struct doc %q", doc)
+	}
+	methods := s.Services["OrderService"].Methods
+	if doc := methods["GetOrder"].Doc; doc != "Returns the order with the given id.\nUnknown ids return an error." {
+		t.Errorf("unexpected method doc %q", doc)
+	}
+	if doc := methods["Cancel"].Doc; doc != "Cancels the order" {
+		t.Errorf("unexpected method doc %q", doc)
+	}
+}
@@ -0,0 +1,385 @@
+package parser
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Type checking of the specification with go/types. Types that cannot be resolved, for example because a
+// third-party dependency is not available, fall back to the types written in the source.
+type specTypeChecker struct {
+	fset        *token.FileSet
+	pkgs        map[string]*ast.Package // directory -> package
+	importPaths map[string]string       // import path -> directory
+	checked     map[string]*types.Package
+	std         types.Importer
+	info        *types.Info
+	errors      []error
+}
+
+func newSpecTypeChecker(fset *token.FileSet, pkgs map[string]*ast.Package) *specTypeChecker {
+	c := &specTypeChecker{fset: fset, pkgs: pkgs, importPaths: make(map[string]string), checked: make(map[string]*types.Package), std: importer.ForCompiler(fset, "source", nil)}
+	c.info = &types.Info{Types: make(map[ast.Expr]types.TypeAndValue), Defs: make(map[*ast.Ident]types.Object), Uses: make(map[*ast.Ident]types.Object)}
+	for dir := range pkgs {
+		c.importPaths[importPathForDir(dir)] = dir
+	}
+	return c
+}
+
+// Derives the import path of a directory from the closest go.mod file.
+// Directories outside of a module use their slash separated path instead.
+func importPathForDir(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		if module := readModulePath(filepath.Join(root, "go.mod")); module != "" {
+			rel, err := filepath.Rel(root, abs)
+			if err != nil || rel == "." {
+				return module
+			}
+			return module + "/" + filepath.ToSlash(rel)
+		}
+		if filepath.Dir(root) == root {
+			return filepath.ToSlash(dir)
+		}
+	}
+}
+
+func readModulePath(gomod string) string {
+	data, err := ioutil.ReadFile(gomod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), "\"")
+		}
+	}
+	return ""
+}
+
+func isStdImportPath(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+// Import resolves the packages of the specification from the parsed sources and standard library packages
+// from GOROOT. Any other package is replaced by an empty package so that checking can continue.
+func (c *specTypeChecker) Import(path string) (*types.Package, error) {
+	if pkg, ok := c.checked[path]; ok {
+		return pkg, nil
+	}
+	if dir, ok := c.importPaths[path]; ok {
+		return c.check(dir), nil
+	}
+	if isStdImportPath(path) {
+		if pkg, err := c.std.Import(path); err == nil {
+			c.checked[path] = pkg
+			return pkg, nil
+		}
+	}
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && strings.HasPrefix(name, "v") && strings.Trim(name[1:], "0123456789") == "" {
+		// Major version suffix
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(strings.Split(name, ".")[0], "go-")
+	pkg := types.NewPackage(path, strings.ReplaceAll(name, "-", "_"))
+	pkg.MarkComplete()
+	c.checked[path] = pkg
+	return pkg, nil
+}
+
+// Type checks the package in dir. Errors are recorded but do not stop the checker.
+func (c *specTypeChecker) check(dir string) *types.Package {
+	path := importPathForDir(dir)
+	if pkg, ok := c.checked[path]; ok {
+		return pkg
+	}
+	astPkg := c.pkgs[dir]
+	// Guards against import cycles
+	c.checked[path] = types.NewPackage(path, astPkg.Name)
+	var names []string
+	for name := range astPkg.Files {
+		if !strings.HasSuffix(name, "_test.go") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var files []*ast.File
+	for _, name := range names {
+		files = append(files, astPkg.Files[name])
+	}
+	conf := types.Config{Importer: c, FakeImportC: true, Error: func(err error) { c.errors = append(c.errors, err) }}
+	pkg, _ := conf.Check(path, c.fset, files, c.info)
+	c.checked[path] = pkg
+	return pkg
+}
+
+func (c *specTypeChecker) checkAll() {
+	for _, dir := range sortedDirs(c.pkgs) {
+		c.check(dir)
+	}
+}
+
+// Returns the package that a type checked directory corresponds to
+func (c *specTypeChecker) packageForDir(dir string) *types.Package {
+	return c.checked[importPathForDir(dir)]
+}
+
+// Removes aliases. Alias types only have the Rhs method on recent versions of go/types.
+func unalias(t types.Type) types.Type {
+	for {
+		alias, ok := t.(interface{ Rhs() types.Type })
+		if !ok {
+			return t
+		}
+		t = alias.Rhs()
+	}
+}
+
+func isValidType(t types.Type) bool {
+	switch tt := unalias(t).(type) {
+	case *types.Basic:
+		return tt.Kind() != types.Invalid
+	case *types.Pointer:
+		return isValidType(tt.Elem())
+	case *types.Slice:
+		return isValidType(tt.Elem())
+	case *types.Array:
+		return isValidType(tt.Elem())
+	case *types.Map:
+		return isValidType(tt.Key()) && isValidType(tt.Elem())
+	case *types.Chan:
+		return isValidType(tt.Elem())
+	case *types.Named:
+		args := tt.TypeArgs()
+		for i := 0; args != nil && i < args.Len(); i++ {
+			if !isValidType(args.At(i)) {
+				return false
+			}
+		}
+	}
+	return t != nil
+}
+
+var basicKinds = map[types.BasicKind]BasicType{
+	types.Bool:    BOOL,
+	types.Int:     INT,
+	types.Int8:    INT8,
+	types.Int16:   INT16,
+	types.Int32:   INT32,
+	types.Int64:   INT64,
+	types.Uint:    UINT,
+	types.Uint8:   UINT8,
+	types.Uint16:  UINT16,
+	types.Uint32:  UINT32,
+	types.Uint64:  UINT64,
+	types.Float32: FLOAT32,
+	types.Float64: DOUBLE,
+	types.String:  STRING,
+}
+
+// Converts a go/types type into a TypeInfo. Types declared in pkg are left unqualified as they are
+// qualified with the package name by the generators.
+func convertType(t types.Type, pkg *types.Package) (TypeInfo, string) {
+	qualifier := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+	switch tt := unalias(t).(type) {
+	case *types.Basic:
+		if tt.Kind() == types.Uint8 && tt.Name() == "byte" {
+			return TypeInfo{BaseType: BASIC, Detail: TypeDetail{TypeName: BYTE}}, ""
+		}
+		if basicType, ok := basicKinds[tt.Kind()]; ok {
+			return TypeInfo{BaseType: BASIC, Detail: TypeDetail{TypeName: basicType}}, ""
+		}
+		return TypeInfo{}, tt.Name() + " is not currently supported by the Parser"
+	case *types.Named:
+		obj := tt.Obj()
+		if obj.Pkg() == nil {
+			if obj.Name() == "error" {
+				return errType(), ""
+			}
+			// comparable and other predeclared interfaces
+			return interfaceToType(), ""
+		}
+		if obj.Pkg().Path() == "context" && obj.Name() == "Context" {
+			return ctxType(), ""
+		}
+		detail := TypeDetail{UserType: types.TypeString(tt, qualifier)}
+		if obj.Pkg() != pkg {
+			detail.PkgPath = obj.Pkg().Path()
+		}
+		return TypeInfo{BaseType: USERDEFINED, Detail: detail}, ""
+	case *types.TypeParam:
+		return TypeInfo{BaseType: USERDEFINED, Detail: TypeDetail{UserType: tt.Obj().Name()}}, ""
+	case *types.Pointer:
+		return convertContainerType(POINTER, nil, tt.Elem(), pkg)
+	case *types.Slice:
+		return convertContainerType(LIST, nil, tt.Elem(), pkg)
+	case *types.Array:
+		return convertContainerType(LIST, nil, tt.Elem(), pkg)
+	case *types.Chan:
+		return convertContainerType(CHAN, nil, tt.Elem(), pkg)
+	case *types.Map:
+		return convertContainerType(MAP, tt.Key(), tt.Elem(), pkg)
+	case *types.Interface:
+		return interfaceToType(), ""
+	case *types.Signature:
+		return TypeInfo{BaseType: FUNC, Detail: TypeDetail{UserType: types.TypeString(tt, qualifier)}}, ""
+	}
+	return TypeInfo{}, types.TypeString(t, qualifier) + " is not currently supported by the Parser"
+}
+
+func convertContainerType(base Type, key types.Type, elem types.Type, pkg *types.Package) (TypeInfo, string) {
+	var keyInfo *TypeInfo
+	if key != nil {
+		info, problem := convertType(key, pkg)
+		if problem != "" {
+			return TypeInfo{}, problem
+		}
+		keyInfo = &info
+	}
+	elemInfo, problem := convertType(elem, pkg)
+	if problem != "" {
+		return TypeInfo{}, problem
+	}
+	return containerType(base, keyInfo, &elemInfo), ""
+}
+
+// Converts the signature of a method declared outside of the parsed syntax, e.g. of an embedded interface
+func signatureToFuncInfo(sig *types.Signature, pkg *types.Package) (FuncInfo, string) {
+	var funcInfo FuncInfo
+	params := sig.Params()
+	for i := 0; i < params.Len(); i++ {
+		param := params.At(i)
+		var paramType TypeInfo
+		var problem string
+		if sig.Variadic() && i == params.Len()-1 {
+			paramType, problem = convertContainerType(ELLIPSIS, nil, param.Type().(*types.Slice).Elem(), pkg)
+		} else {
+			paramType, problem = convertType(param.Type(), pkg)
+		}
+		if problem != "" {
+			return funcInfo, problem
+		}
+		funcInfo.Args = append(funcInfo.Args, ArgInfo{Name: param.Name(), Type: paramType})
+	}
+	results := sig.Results()
+	for i := 0; i < results.Len(); i++ {
+		result := results.At(i)
+		resultType, problem := convertType(result.Type(), pkg)
+		if problem != "" {
+			return funcInfo, problem
+		}
+		funcInfo.Return = append(funcInfo.Return, ArgInfo{Name: result.Name(), Type: resultType})
+	}
+	return funcInfo, ""
+}
+
+// Converts a type expression using only the syntax. Used for types that could not be type checked.
+func (s *SpecParser) astType(expr ast.Expr) TypeInfo {
+	switch eType := expr.(type) {
+	case *ast.Ident:
+		return stringToType(eType.Name)
+	case *ast.ParenExpr:
+		return s.astType(eType.X)
+	case *ast.SelectorExpr:
+		name := types.ExprString(eType)
+		if name == "context.Context" {
+			return ctxType()
+		}
+		return TypeInfo{BaseType: USERDEFINED, Detail: TypeDetail{UserType: name, PkgPath: s.importPathOf(eType)}}
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// Instantiation of a generic type
+		return TypeInfo{BaseType: USERDEFINED, Detail: TypeDetail{UserType: types.ExprString(eType)}}
+	case *ast.ArrayType:
+		elem := s.astType(eType.Elt)
+		return containerType(LIST, nil, &elem)
+	case *ast.StarExpr:
+		elem := s.astType(eType.X)
+		return containerType(POINTER, nil, &elem)
+	case *ast.ChanType:
+		elem := s.astType(eType.Value)
+		return containerType(CHAN, nil, &elem)
+	case *ast.MapType:
+		key := s.astType(eType.Key)
+		elem := s.astType(eType.Value)
+		return containerType(MAP, &key, &elem)
+	case *ast.InterfaceType:
+		return interfaceToType()
+	case *ast.FuncType:
+		return TypeInfo{BaseType: FUNC, Detail: TypeDetail{UserType: types.ExprString(eType)}}
+	}
+	return s.typeError(expr, "%s is not currently supported by the Parser", reflect.TypeOf(expr))
+}
+
+// Returns the import path of the package that a qualified identifier refers to, if it is known
+func (s *SpecParser) importPathOf(sel *ast.SelectorExpr) string {
+	if ident, ok := sel.X.(*ast.Ident); ok && s.checker != nil {
+		if pkgName, ok := s.checker.info.Uses[ident].(*types.PkgName); ok {
+			return pkgName.Imported().Path()
+		}
+	}
+	return ""
+}
+
+// Converts a type expression of the specification into a TypeInfo
+func (s *SpecParser) exprType(expr ast.Expr) TypeInfo {
+	if s.checker != nil {
+		if tv, ok := s.checker.info.Types[expr]; ok && isValidType(tv.Type) {
+			info, problem := convertType(tv.Type, s.curPkg)
+			if problem != "" {
+				return s.typeError(expr, "%s", problem)
+			}
+			return info
+		}
+	}
+	return s.astType(expr)
+}
+
+// Logs type checking errors. They are not reported as diagnostics since missing third-party
+// dependencies are expected when compiling outside of the application's build environment.
+func (s *SpecParser) logTypeErrors() {
+	if len(s.checker.errors) > 0 {
+		s.logger.Println("Type checking found", len(s.checker.errors), "problem(s), falling back to syntactic types where needed. First problem:", s.checker.errors[0])
+	}
+}
+
+// Returns the directories of the parsed packages in a deterministic order
+func sortedDirs(pkgs map[string]*ast.Package) []string {
+	var dirs []string
+	for dir := range pkgs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// Returns the files of a package ordered by file name
+func sortedFiles(pkg *ast.Package) []*ast.File {
+	var names []string
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var files []*ast.File
+	for _, name := range names {
+		files = append(files, pkg.Files[name])
+	}
+	return files
+}
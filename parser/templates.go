@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// TemplateExt is the extension of the files in a template directory. The template name.tmpl overrides the template
+// registered as name.
+const TemplateExt = ".tmpl"
+
+// Functions available to every template
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+var templates = struct {
+	sync.Mutex
+	defaults  map[string]string
+	parsed    map[string]*template.Template
+	overrides map[string]*template.Template
+}{defaults: make(map[string]string), parsed: make(map[string]*template.Template)}
+
+// RegisterTemplate registers the default text of the template name. Plugins register the templates of the code they
+// generate when they are initialized, it panics if name is registered twice or text doesn't parse.
+func RegisterTemplate(name string, text string) {
+	templates.Lock()
+	defer templates.Unlock()
+	if _, ok := templates.defaults[name]; ok {
+		panic("template " + name + " registered twice")
+	}
+	templates.defaults[name] = text
+	templates.parsed[name] = template.Must(template.New(name).Funcs(templateFuncs).Parse(text))
+}
+
+// TemplateNames returns the names of the registered templates, sorted
+func TemplateNames() []string {
+	templates.Lock()
+	defer templates.Unlock()
+	return SortedKeys(templates.defaults)
+}
+
+// DefaultTemplate returns the text that the template name was registered with
+func DefaultTemplate(name string) (string, bool) {
+	templates.Lock()
+	defer templates.Unlock()
+	text, ok := templates.defaults[name]
+	return text, ok
+}
+
+// LoadTemplates replaces the overrides of the registered templates with the files of dir. Every file of dir must be
+// named after a registered template. An empty dir removes all overrides.
+func LoadTemplates(dir string) error {
+	overrides := make(map[string]*template.Template)
+	if dir != "" {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != TemplateExt {
+				continue
+			}
+			filename := filepath.Join(dir, entry.Name())
+			name := strings.TrimSuffix(entry.Name(), TemplateExt)
+			if _, ok := DefaultTemplate(name); !ok {
+				return fmt.Errorf("%s: no template is named %s, see `blueprint templates` for the names", filename, name)
+			}
+			text, err := ioutil.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+			tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(text))
+			if err != nil {
+				return fmt.Errorf("%s: %v", filename, err)
+			}
+			overrides[name] = tmpl
+		}
+	}
+	templates.Lock()
+	defer templates.Unlock()
+	templates.overrides = overrides
+	return nil
+}
+
+// ExecuteTemplate executes the template name with data, using the override of the template if one was loaded
+func ExecuteTemplate(name string, data interface{}) (string, error) {
+	templates.Lock()
+	tmpl, ok := templates.overrides[name]
+	if !ok {
+		tmpl, ok = templates.parsed[name]
+	}
+	templates.Unlock()
+	if !ok {
+		return "", fmt.Errorf("template %s is not registered", name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// WriteDefaultTemplates writes the default text of every registered template to dir, as a starting point for
+// overrides
+func WriteDefaultTemplates(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range TemplateNames() {
+		text, _ := DefaultTemplate(name)
+		if err := WriteFile(filepath.Join(dir, name+TemplateExt), []byte(text), FileMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
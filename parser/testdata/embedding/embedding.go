@@ -0,0 +1,67 @@
+package embedding
+
+import (
+	"context"
+	"io"
+)
+
+type Healthy interface {
+	Health(ctx context.Context) (bool, error)
+}
+
+type Admin interface {
+	Healthy
+	Reset(ctx context.Context, force bool) error
+}
+
+type UserService interface {
+	Admin
+	io.Closer
+	GetUser(ctx context.Context, id int64) (string, error)
+}
+
+type HealthHelper struct{}
+
+func (h *HealthHelper) Health(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+func (h *HealthHelper) Describe() string {
+	return "health"
+}
+
+type AdminHelper struct {
+	*HealthHelper
+}
+
+func (a *AdminHelper) Reset(ctx context.Context, force bool) error {
+	return nil
+}
+
+func (a *AdminHelper) Describe() string {
+	return "admin"
+}
+
+type CloseHelper struct{}
+
+func (c CloseHelper) Close() error {
+	return nil
+}
+
+func (c CloseHelper) Describe() string {
+	return "close"
+}
+
+type UserServiceImpl struct {
+	AdminHelper
+	CloseHelper
+	db string
+}
+
+func NewUserServiceImpl() *UserServiceImpl {
+	return &UserServiceImpl{}
+}
+
+func (u *UserServiceImpl) GetUser(ctx context.Context, id int64) (string, error) {
+	return u.db, nil
+}
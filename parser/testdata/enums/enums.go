@@ -0,0 +1,34 @@
+package enums
+
+import "context"
+
+// Status of an order
+type Status int
+
+const (
+	_ Status = iota
+	Pending
+	Shipped
+	Delivered = Shipped + 10
+)
+
+type Level int
+
+const (
+	Low Level = 1 << iota
+	Medium
+	High
+)
+
+// Order is sent between services
+type Order struct {
+	ID     int64
+	Status Status
+}
+
+type OrderService interface {
+	// Returns the order with the given id.
+	// Unknown ids return an error.
+	GetOrder(ctx context.Context, id int64) (Order, error)
+	Cancel(ctx context.Context, id int64) error // Cancels the order
+}
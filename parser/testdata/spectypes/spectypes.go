@@ -0,0 +1,24 @@
+package spectypes
+
+import (
+	"context"
+	"time"
+)
+
+type Alias = []int
+
+type Pair[K comparable, V any] struct {
+	Key K
+	Val V
+}
+
+type Item struct {
+	Name, Owner string
+	Created     time.Time
+}
+
+type Kitchen interface {
+	Nested(ctx context.Context, a map[string][]Item, b []*Item, c [][]int64) ([]byte, error)
+	Numbers(ctx context.Context, a int32, b float32, c uint16, d uint64, e int) (time.Time, error)
+	Generic(ctx context.Context, p Pair[string, Item], al Alias, m map[string]time.Duration, vals ...int8) error
+}
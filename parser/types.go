@@ -1,5 +1,9 @@
 package parser
 
+import (
+	"strings"
+)
+
 type Type int
 
 const (
@@ -23,57 +27,164 @@ const (
 	INTERFACE
 	CONTEXT
 	ERROR
+	INT
+	INT8
+	INT16
+	INT32
+	UINT
+	UINT8
+	UINT16
+	UINT32
+	UINT64
+	FLOAT32
+	BYTE
 )
 
+var basicTypeNames = map[BasicType]string{
+	INT64:     "int64",
+	BOOL:      "bool",
+	DOUBLE:    "float64",
+	STRING:    "string",
+	INTERFACE: "interface{}",
+	CONTEXT:   "context.Context",
+	ERROR:     "error",
+	INT:       "int",
+	INT8:      "int8",
+	INT16:     "int16",
+	INT32:     "int32",
+	UINT:      "uint",
+	UINT8:     "uint8",
+	UINT16:    "uint16",
+	UINT32:    "uint32",
+	UINT64:    "uint64",
+	FLOAT32:   "float32",
+	BYTE:      "byte",
+}
+
 type TypeDetail struct {
 	TypeName BasicType
 	UserType string
+	PkgPath  string // Import path of user defined types that are declared outside of the specification, e.g. "time"
 }
 
 func (td TypeDetail) String(userdefined bool) string {
 	if userdefined {
 		return td.UserType
 	}
-	switch td.TypeName {
-	case INT64:
-		return "int64"
-	case BOOL:
-		return "bool"
-	case DOUBLE:
-		return "float64"
-	case STRING:
-		return "string"
-	case INTERFACE:
-		return "interface{}"
-	case CONTEXT:
-		return "context.Context"
-	case ERROR:
-		return "error"
-	}
-
-	return ""
+	return basicTypeNames[td.TypeName]
 }
 
+// TypeInfo describes the type of an argument, return value or field.
+// Key and Elem describe the map key and the element type of containers (lists, pointers, variadic
+// arguments, channels and map values) so that arbitrarily nested types are represented.
+// The flat ContainerType/Detail fields mirror the first level of Key and Elem.
 type TypeInfo struct {
-	BaseType Type
-	ContainerType1 Type // For list element type and map key type
-	Detail TypeDetail // Info about BasicType and ContainerType1
-	ContainerType2 Type // map Value type
+	BaseType         Type
+	ContainerType1   Type       // For list element type and map key type
+	Detail           TypeDetail // Info about BasicType and ContainerType1
+	ContainerType2   Type       // map Value type
 	Container2Detail TypeDetail
+	Key              *TypeInfo
+	Elem             *TypeInfo
+}
+
+func isContainer(t Type) bool {
+	return t == POINTER || t == LIST || t == ELLIPSIS || t == CHAN || t == MAP
+}
+
+// Returns the element type of a container
+func (t TypeInfo) ElemType() TypeInfo {
+	if t.Elem != nil {
+		return *t.Elem
+	}
+	if t.BaseType == MAP {
+		return TypeInfo{BaseType: t.ContainerType2, Detail: t.Container2Detail}
+	}
+	return TypeInfo{BaseType: t.ContainerType1, Detail: t.Detail}
+}
+
+// Returns the key type of a map
+func (t TypeInfo) KeyType() TypeInfo {
+	if t.Key != nil {
+		return *t.Key
+	}
+	return TypeInfo{BaseType: t.ContainerType1, Detail: t.Detail}
 }
 
+// Builds a container type and fills in the flat fields from the key and element types
+func containerType(base Type, key *TypeInfo, elem *TypeInfo) TypeInfo {
+	t := TypeInfo{BaseType: base, Key: key, Elem: elem}
+	if base == MAP {
+		t.ContainerType1 = key.BaseType
+		if !isContainer(key.BaseType) {
+			t.Detail = key.Detail
+		}
+		t.ContainerType2 = elem.BaseType
+		if !isContainer(elem.BaseType) {
+			t.Container2Detail = elem.Detail
+		}
+	} else {
+		t.ContainerType1 = elem.BaseType
+		if !isContainer(elem.BaseType) {
+			t.Detail = elem.Detail
+		}
+	}
+	return t
+}
+
+// Returns true if the type refers to a type declared in the specification package
 func (t TypeInfo) IsUserDefined() bool {
-	return t.BaseType == USERDEFINED || (t.BaseType == LIST && t.ContainerType1 == USERDEFINED) || (t.BaseType == MAP && t.ContainerType2 == USERDEFINED)
+	switch t.BaseType {
+	case USERDEFINED:
+		return t.Detail.PkgPath == ""
+	case MAP:
+		return t.KeyType().IsUserDefined() || t.ElemType().IsUserDefined()
+	case POINTER, LIST, ELLIPSIS, CHAN:
+		return t.ElemType().IsUserDefined()
+	}
+	return false
+}
+
+// Returns true if name is already qualified with a package name. Type arguments are ignored.
+func isQualified(name string) bool {
+	if idx := strings.Index(name, "["); idx != -1 {
+		name = name[:idx]
+	}
+	return strings.Contains(name, ".")
 }
 
+// Qualifies the types declared in the specification package with pkgName
 func PrependPackageName(pkgName string, t TypeInfo) TypeInfo {
-	t1 := t
-	if t1.BaseType == USERDEFINED || (t1.BaseType == LIST && t1.ContainerType1 == USERDEFINED) {
-		t1.Detail.UserType = pkgName + "." + t1.Detail.UserType
-	} else if t1.BaseType == MAP && t1.ContainerType2 == USERDEFINED {
-		t1.Container2Detail.UserType = pkgName + "." + t1.Container2Detail.UserType
+	switch t.BaseType {
+	case USERDEFINED:
+		if t.Detail.PkgPath == "" && !isQualified(t.Detail.UserType) {
+			t.Detail.UserType = pkgName + "." + t.Detail.UserType
+		}
+		return t
+	case MAP:
+		key := PrependPackageName(pkgName, t.KeyType())
+		elem := PrependPackageName(pkgName, t.ElemType())
+		return containerType(MAP, &key, &elem)
+	case POINTER, LIST, ELLIPSIS, CHAN:
+		elem := PrependPackageName(pkgName, t.ElemType())
+		return containerType(t.BaseType, nil, &elem)
+	}
+	return t
+}
+
+// Returns the import paths of the packages outside of the specification that the type refers to
+func (t TypeInfo) PkgPaths() []string {
+	switch t.BaseType {
+	case USERDEFINED:
+		if t.Detail.PkgPath != "" {
+			return []string{t.Detail.PkgPath}
+		}
+	case MAP:
+		return append(t.KeyType().PkgPaths(), t.ElemType().PkgPaths()...)
+	case POINTER, LIST, ELLIPSIS, CHAN:
+		return t.ElemType().PkgPaths()
 	}
-	return t1
+	return nil
 }
 
 func (t TypeInfo) String() string {
@@ -83,59 +194,53 @@ func (t TypeInfo) String() string {
 	case USERDEFINED:
 		return t.Detail.String(true)
 	case POINTER:
-		userdefined := t.ContainerType1 == USERDEFINED
-		return "*" + t.Detail.String(userdefined)
+		return "*" + t.ElemType().String()
 	case LIST:
-		userdefined := t.ContainerType1 == USERDEFINED
-		return "[]" + t.Detail.String(userdefined)
+		return "[]" + t.ElemType().String()
 	case MAP:
-		return "map[" + t.Detail.String(t.ContainerType1 == USERDEFINED) + "]" + t.Container2Detail.String(t.ContainerType2 == USERDEFINED)
+		return "map[" + t.KeyType().String() + "]" + t.ElemType().String()
 	case ELLIPSIS:
-		return "..." + t.Detail.String(t.ContainerType1 == USERDEFINED)
+		return "..." + t.ElemType().String()
+	case CHAN:
+		return "chan " + t.ElemType().String()
+	case FUNC:
+		// Holds the signature if it is known
+		return t.Detail.UserType
 	}
 	return ""
 }
 
-func isSameTypeDetail(d1 TypeDetail, d2 TypeDetail) bool {
-	if d1.TypeName == d2.TypeName && d1.UserType == d2.UserType {
-		return true
-	}
-	return false
-}
-
 func isSameType(t1 TypeInfo, t2 TypeInfo) bool {
-	if t1.BaseType == t2.BaseType && t1.ContainerType1 == t2.ContainerType1 && t1.ContainerType2 == t2.ContainerType2 && isSameTypeDetail(t1.Detail, t2.Detail) && isSameTypeDetail(t1.Container2Detail, t2.Container2Detail) {
-		return true
-	}
-	return false
+	return t1.BaseType == t2.BaseType && t1.String() == t2.String()
 }
 
 func isBasic(name string) bool {
-	if name == "int64" || name == "int" || name == "int32" || name == "string" || name == "float64" || name == "float32" || name == "bool" || name == "interface" || name == "context.Context" || name == "error" {
-		return true
-	}
+	_, ok := basicTypeByName(name)
+	return ok
+}
 
-	return false
+func basicTypeByName(name string) (BasicType, bool) {
+	switch name {
+	case "interface":
+		return INTERFACE, true
+	case "float64":
+		return DOUBLE, true
+	case "rune":
+		return INT32, true
+	}
+	for basicType, basicName := range basicTypeNames {
+		if basicName == name {
+			return basicType, true
+		}
+	}
+	return 0, false
 }
 
 func getTypeDetail(name string) TypeDetail {
-	if name == "int64" || name == "int" || name == "int32" {
-		return TypeDetail{TypeName:INT64}
-	} else if name == "string" {
-		return TypeDetail{TypeName: STRING}
-	} else if name == "float64" || name == "float32" {
-		return TypeDetail{TypeName: DOUBLE}
-	} else if name == "bool" {
-		return TypeDetail{TypeName: BOOL}
-	} else if name == "interface" {
-		return TypeDetail{TypeName: INTERFACE}
-	} else if name == "context.Context" {
-		return TypeDetail{TypeName: CONTEXT}
-	} else if name == "error" {
-		return TypeDetail{TypeName: ERROR}
-	} else {
-		return TypeDetail{UserType:name}
+	if basicType, ok := basicTypeByName(name); ok {
+		return TypeDetail{TypeName: basicType}
 	}
+	return TypeDetail{UserType: name}
 }
 
 func stringToType(name string) TypeInfo {
@@ -147,35 +252,19 @@ func stringToType(name string) TypeInfo {
 }
 
 func arrayToType(name string) TypeInfo {
-	tdetail := getTypeDetail(name)
-	ctype := BASIC
-	if !isBasic(name) {
-		ctype = USERDEFINED
-	}
-	return TypeInfo{BaseType: LIST, ContainerType1: ctype, Detail: tdetail}
+	elem := stringToType(name)
+	return containerType(LIST, nil, &elem)
 }
 
 func ellipsisToType(name string) TypeInfo {
-	tdetail := getTypeDetail(name)
-	ctype := BASIC
-	if !isBasic(name) {
-		ctype = USERDEFINED
-	}
-	return TypeInfo{BaseType: ELLIPSIS, ContainerType1: ctype, Detail: tdetail}
+	elem := stringToType(name)
+	return containerType(ELLIPSIS, nil, &elem)
 }
 
 func mapToType(keyName string, valName string) TypeInfo {
-	kDetail := getTypeDetail(keyName)
-	kType := BASIC
-	if !isBasic(keyName) {
-		kType = USERDEFINED
-	}
-	vDetail := getTypeDetail(valName)
-	vType := BASIC
-	if !isBasic(valName) {
-		vType = USERDEFINED
-	}
-	return TypeInfo{BaseType: MAP, ContainerType1: kType, Detail: kDetail, ContainerType2: vType, Container2Detail: vDetail}
+	key := stringToType(keyName)
+	elem := stringToType(valName)
+	return containerType(MAP, &key, &elem)
 }
 
 func interfaceToType() TypeInfo {
@@ -183,12 +272,8 @@ func interfaceToType() TypeInfo {
 }
 
 func pointerToType(name string) TypeInfo {
-	ctype := BASIC
-	if !isBasic(name) {
-		ctype = USERDEFINED
-	}
-	tdetail := getTypeDetail(name)
-	return TypeInfo{BaseType: POINTER, ContainerType1: ctype, Detail: tdetail}
+	elem := stringToType(name)
+	return containerType(POINTER, nil, &elem)
 }
 
 func ctxType() TypeInfo {
@@ -200,14 +285,10 @@ func errType() TypeInfo {
 }
 
 func chanToType(name string) TypeInfo {
-	ctype := BASIC
-	if !isBasic(name) {
-		ctype = USERDEFINED
-	}
-	tdetail := getTypeDetail(name)
-	return TypeInfo{BaseType: CHAN, ContainerType1: ctype, Detail: tdetail}
+	elem := stringToType(name)
+	return containerType(CHAN, nil, &elem)
 }
 
 func funcType() TypeInfo {
 	return TypeInfo{BaseType: FUNC}
-}
\ No newline at end of file
+}
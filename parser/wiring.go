@@ -1,20 +1,14 @@
 package parser
 
 import (
-	"encoding/json"
+	"fmt"
+	"go/token"
 	"io/ioutil"
 	"log"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"strconv"
 	"strings"
 )
 
-// Copied from: https://gist.github.com/ivanzoid/129460aa08aff72862a534ebe0a9ae30
-func fileNameWithoutExtension(fileName string) string {
-	return strings.TrimSuffix(fileName, filepath.Ext(fileName))
-}
-
 type MillenialNode struct {
 	Name     string          `json:"name"`
 	Children []ContainerNode `json:"children"`
@@ -28,6 +22,7 @@ type ContainerNode struct {
 type ModifierNode struct {
 	ModifierType   string         `json:"modifier_type"`
 	ModifierParams []ArgumentNode `json:"modifier_params"`
+	Pos            token.Position `json:"-"` // Location in the wiring file
 }
 
 type ArgumentNode struct {
@@ -36,6 +31,7 @@ type ArgumentNode struct {
 	ClientModifiers []ModifierNode `json:"client_modifiers"`
 	KeywordName     string         `json:"keyword_name"`
 	Value           string         `json:"client_node"`
+	Pos             token.Position `json:"-"`
 }
 
 type DetailNode struct {
@@ -46,44 +42,612 @@ type DetailNode struct {
 	ClientModifiers []ModifierNode `json:"client_modifier"`
 	ServerModifiers []ModifierNode `json:"server_modifiers"`
 	Children        []DetailNode   `json:"children"`
+	Pos             token.Position `json:"-"`
 }
 
-type WiringParser struct {
-	config   *Config
-	logger   *log.Logger
-	RootNode *MillenialNode
+// Abstract types of the components that can be instantiated in a wiring file
+var WiringComponents = map[string]bool{
+	"Cache":           true,
+	"NoSQLDatabase":   true,
+	"Queue":           true,
+	"RelationalDB":    true,
+	"Tracer":          true,
+	"MetricCollector": true,
+	"XTracer":         true,
+	"LoadBalancer":    true,
+	"Registry":        true,
 }
 
-func NewWiringParser(config *Config, logger *log.Logger) *WiringParser {
-	return &WiringParser{config: config, logger: logger, RootNode: nil}
+// Data collected from the wiring file
+type wiringParam struct {
+	KeywordName     string
+	Node            wiringExpr
+	InstanceName    string
+	IsInstance      bool
+	ClientOpts      []wiringExpr
+	ClientModifiers []*wiringModifier
 }
 
-func (w *WiringParser) ParseWiring() {
-	wiring_file_output := fileNameWithoutExtension(w.config.WiringFile) + "_compiled.json"
-	cmd := exec.Command("python", "wiring_translate.py", w.config.WiringFile, wiring_file_output)
+type wiringModifier struct {
+	Name         string
+	Line         int
+	ModifierType string
+	Params       []*wiringParam
+}
 
-	stdout, err := cmd.CombinedOutput()
+type wiringInstance struct {
+	Name         string
+	Line         int
+	AbstractType string
+	ActualType   string
+	Params       []*wiringParam
+	ServerOpts   []wiringExpr
+	ClientOpts   []wiringExpr
+}
+
+type wiringProcess struct {
+	Name      string
+	Line      int
+	Instances []string
+}
+
+type wiringCollector struct {
+	instances      []*wiringInstance
+	modifiers      map[string]*wiringModifier
+	modifierLists  map[string]*wList
+	modifierLambda map[string]*wLambda
+	processes      []*wiringProcess
+	defined        map[string]bool
+	broken         map[string]bool // Instances that had errors. Later references to them are not reported again.
+	expanding      []string        // Lambdas being expanded, innermost last
+	filename       string
+}
+
+func newWiringCollector(filename string) *wiringCollector {
+	return &wiringCollector{filename: filename, broken: make(map[string]bool), modifiers: make(map[string]*wiringModifier), modifierLists: make(map[string]*wList), modifierLambda: make(map[string]*wLambda), defined: make(map[string]bool)}
+}
 
-	w.logger.Print(string(stdout))
+// Error found while translating the wiring file. The line is reported separately as part of the diagnostic.
+type wiringError struct {
+	line int
+	msg  string
+}
 
+func (e *wiringError) Error() string {
+	return e.msg
+}
+
+func wiringErrorf(line int, format string, args ...interface{}) error {
+	return &wiringError{line: line, msg: fmt.Sprintf(format, args...)}
+}
+
+func isServiceType(abstractType string) bool {
+	return abstractType == "Service" || abstractType == "QueueService" || strings.HasSuffix(abstractType, "Service")
+}
+
+func isConstantExpr(expr wiringExpr) bool {
+	switch e := expr.(type) {
+	case *wConst, *wDict, *wList:
+		return true
+	case *wUnary:
+		_, ok := e.Operand.(*wConst)
+		return ok
+	}
+	return false
+}
+
+// Returns the name of the instance whose client is overridden using instance.WithClient(...)
+func (c *wiringCollector) parseClientOverriding(call *wCall) (string, []wiringExpr, error) {
+	if attr, ok := call.Func.(*wAttribute); ok && attr.Attr == "WithClient" {
+		if name, ok := attr.Value.(*wName); ok {
+			return name.Id, call.Args, nil
+		}
+	}
+	return "", nil, wiringErrorf(call.Line(), "Instance parameter can only be modified using '.WithClient' method")
+}
+
+func (c *wiringCollector) getParamInfos(call *wCall) ([]*wiringParam, error) {
+	if len(call.Args) > 0 {
+		return nil, wiringErrorf(call.Line(), "Only keyword arguments are supported")
+	}
+	var params []*wiringParam
+	for _, kw := range call.Keywords {
+		param := &wiringParam{KeywordName: kw.Arg, Node: kw.Value}
+		switch value := kw.Value.(type) {
+		case *wName:
+			param.IsInstance = true
+			param.InstanceName = value.Id
+		case *wCall:
+			name, opts, err := c.parseClientOverriding(value)
+			if err != nil {
+				return nil, err
+			}
+			param.IsInstance = true
+			param.InstanceName = name
+			param.ClientOpts = opts
+		default:
+			if !isConstantExpr(value) {
+				return nil, wiringErrorf(value.Line(), "Argument to service instantiation must be a constant or an instance")
+			}
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+func (c *wiringCollector) getProcessParams(call *wCall) ([]string, error) {
+	if len(call.Args) > 0 || len(call.Keywords) != 1 || call.Keywords[0].Arg != "services" {
+		return nil, wiringErrorf(call.Line(), "Process only takes 1 argument named 'services'")
+	}
+	list, ok := call.Keywords[0].Value.(*wList)
+	if !ok {
+		return nil, wiringErrorf(call.Line(), "'services' argument expected list")
+	}
+	var instances []string
+	for _, elt := range list.Elts {
+		name, ok := elt.(*wName)
+		if !ok {
+			return nil, wiringErrorf(elt.Line(), "Only names of service instances are permitted in the 'services' argument")
+		}
+		instances = append(instances, name.Id)
+	}
+	return instances, nil
+}
+
+// Unwraps Type(...).WithServer(...).WithClient(...) chains
+func (c *wiringCollector) parseServiceInstantiation(instance *wiringInstance, expr wiringExpr) error {
+	call, ok := expr.(*wCall)
+	if !ok {
+		return wiringErrorf(expr.Line(), "Instance of %s type must be an object of a known type", instance.AbstractType)
+	}
+	switch fn := call.Func.(type) {
+	case *wName:
+		params, err := c.getParamInfos(call)
+		if err != nil {
+			return err
+		}
+		instance.ActualType = fn.Id
+		instance.Params = params
+		return nil
+	case *wAttribute:
+		if len(call.Args) == 0 {
+			return wiringErrorf(call.Line(), "%s expects a modifier argument", fn.Attr)
+		}
+		switch fn.Attr {
+		case "WithServer":
+			if instance.ServerOpts != nil {
+				return wiringErrorf(call.Line(), "WithServer can only be used once")
+			}
+			instance.ServerOpts = call.Args
+		case "WithClient":
+			if instance.ClientOpts != nil {
+				return wiringErrorf(call.Line(), "WithClient can only be used once")
+			}
+			instance.ClientOpts = call.Args
+		default:
+			return wiringErrorf(call.Line(), "Unknown instance option %s", fn.Attr)
+		}
+		return c.parseServiceInstantiation(instance, fn.Value)
+	}
+	return wiringErrorf(call.Line(), "Invalid instantiation of %s", instance.Name)
+}
+
+// Returns the kind of annotation (Name, List or Callable) along with the abstract type(s)
+func parseAnnotation(annotation wiringExpr) (string, wiringExpr, error) {
+	switch a := annotation.(type) {
+	case *wName:
+		return "", a, nil
+	case *wSubscript:
+		if name, ok := a.Value.(*wName); ok {
+			switch name.Id {
+			case "List":
+				return "List", a.Index, nil
+			case "Callable":
+				if t, ok := a.Index.(*wTuple); ok && len(t.Elts) == 2 {
+					return "Callable", t.Elts[1], nil
+				}
+			}
+		}
+	}
+	return "", nil, wiringErrorf(annotation.Line(), "Invalid annotation type %s", unparseWiringExpr(annotation))
+}
+
+func isModifierType(expr wiringExpr) bool {
+	name, ok := expr.(*wName)
+	return ok && name.Id == "Modifier"
+}
+
+func (c *wiringCollector) collect(stmt wiringStmt) error {
+	if stmt.Annotation == nil {
+		return wiringErrorf(stmt.Line, "UnAnnotated Assignment statement found in wiring file")
+	}
+	if stmt.Value == nil {
+		return wiringErrorf(stmt.Line, "%s is declared but never assigned", stmt.Target)
+	}
+	if c.defined[stmt.Target] {
+		return wiringErrorf(stmt.Line, "Instance Name %s has been previously used!", stmt.Target)
+	}
+	c.defined[stmt.Target] = true
+
+	kind, abstractType, err := parseAnnotation(stmt.Annotation)
 	if err != nil {
-		w.logger.Fatal(err)
+		return err
 	}
+	switch kind {
+	case "List":
+		if !isModifierType(abstractType) {
+			return wiringErrorf(stmt.Line, "List annotation type is only supported with Modifiers")
+		}
+		list, ok := stmt.Value.(*wList)
+		if !ok {
+			return wiringErrorf(stmt.Line, "List[Modifier] must be assigned a list of modifiers")
+		}
+		c.modifierLists[stmt.Target] = list
+	case "Callable":
+		if sub, ok := abstractType.(*wSubscript); ok {
+			if name, ok := sub.Value.(*wName); !ok || name.Id != "List" || !isModifierType(sub.Index) {
+				return wiringErrorf(stmt.Line, "Lambda annotation type must produce a Modifier or a List[Modifier]")
+			}
+		} else if !isModifierType(abstractType) {
+			return wiringErrorf(stmt.Line, "Lambda annotation type must produce a Modifier or a List[Modifier]")
+		}
+		lambda, ok := stmt.Value.(*wLambda)
+		if !ok {
+			return wiringErrorf(stmt.Line, "Callable annotation must be assigned a lambda")
+		}
+		c.modifierLambda[stmt.Target] = lambda
+	default:
+		typeName, ok := abstractType.(*wName)
+		if !ok {
+			return wiringErrorf(stmt.Line, "Invalid annotation type %s", unparseWiringExpr(abstractType))
+		}
+		return c.collectNormal(stmt, typeName.Id)
+	}
+	return nil
+}
 
-	// TODO: Parse Output
+func (c *wiringCollector) collectNormal(stmt wiringStmt, abstractType string) error {
+	switch {
+	case isServiceType(abstractType) || WiringComponents[abstractType]:
+		instance := &wiringInstance{Name: stmt.Target, Line: stmt.Line, AbstractType: abstractType}
+		if err := c.parseServiceInstantiation(instance, stmt.Value); err != nil {
+			return err
+		}
+		c.instances = append(c.instances, instance)
+	case abstractType == "Modifier":
+		call, ok := stmt.Value.(*wCall)
+		if !ok {
+			return wiringErrorf(stmt.Line, "Modifier must be assigned a modifier instantiation")
+		}
+		name, ok := call.Func.(*wName)
+		if !ok {
+			return wiringErrorf(stmt.Line, "Modifier must be assigned a modifier instantiation")
+		}
+		params, err := c.getParamInfos(call)
+		if err != nil {
+			return err
+		}
+		c.modifiers[stmt.Target] = &wiringModifier{Name: stmt.Target, Line: stmt.Line, ModifierType: name.Id, Params: params}
+	case abstractType == "Process":
+		call, ok := stmt.Value.(*wCall)
+		if !ok {
+			return wiringErrorf(stmt.Line, "Process must be assigned a Process instantiation")
+		}
+		instances, err := c.getProcessParams(call)
+		if err != nil {
+			return err
+		}
+		c.processes = append(c.processes, &wiringProcess{Name: stmt.Target, Line: stmt.Line, Instances: instances})
+	default:
+		return wiringErrorf(stmt.Line, "Currently only instances of services or components are supported")
+	}
+	return nil
+}
 
-	wiringCompiledFile, err := os.Open(wiring_file_output)
+// Partial evaluation of modifier lists and lambdas
+func (c *wiringCollector) getMapping(name string, lambda *wLambda, call *wCall) (map[string]wiringExpr, error) {
+	if len(call.Args) > len(lambda.Params) {
+		return nil, wiringErrorf(call.Line(), "Lambda %s takes %d arguments but %d were given", name, len(lambda.Params), len(call.Args))
+	}
+	mapping := make(map[string]wiringExpr)
+	for idx, arg := range call.Args {
+		mapping[lambda.Params[idx]] = arg
+	}
+	for _, kw := range call.Keywords {
+		known := false
+		for _, param := range lambda.Params {
+			known = known || param == kw.Arg
+		}
+		if !known {
+			return nil, wiringErrorf(call.Line(), "Lambda %s has no argument named %s", name, kw.Arg)
+		}
+		if _, ok := mapping[kw.Arg]; ok {
+			return nil, wiringErrorf(call.Line(), "Lambda %s got multiple values for argument %s", name, kw.Arg)
+		}
+		mapping[kw.Arg] = kw.Value
+	}
+	for _, param := range lambda.Params {
+		if _, ok := mapping[param]; !ok {
+			return nil, wiringErrorf(call.Line(), "Lambda %s is missing argument %s", name, param)
+		}
+	}
+	return mapping, nil
+}
+
+func (c *wiringCollector) parseModifierCall(call *wCall) (*wiringModifier, error) {
+	name, ok := call.Func.(*wName)
+	if !ok {
+		return nil, wiringErrorf(call.Line(), "Invalid modifier instantiation %s", unparseWiringExpr(call))
+	}
+	var params []*wiringParam
+	for _, kw := range call.Keywords {
+		param := &wiringParam{KeywordName: kw.Arg, Node: kw.Value}
+		if instance, ok := kw.Value.(*wName); ok {
+			param.IsInstance = true
+			param.InstanceName = instance.Id
+		}
+		params = append(params, param)
+	}
+	return &wiringModifier{Name: name.Id, Line: call.Line(), ModifierType: name.Id, Params: params}, nil
+}
+
+func (c *wiringCollector) lookupModifier(expr wiringExpr) (*wiringModifier, error) {
+	name, ok := expr.(*wName)
+	if !ok {
+		return nil, wiringErrorf(expr.Line(), "Expected the name of a modifier but found %s", unparseWiringExpr(expr))
+	}
+	if modifier, ok := c.modifiers[name.Id]; ok {
+		return modifier, nil
+	}
+	return nil, wiringErrorf(expr.Line(), "Modifier named %s is undefined", name.Id)
+}
+
+func (c *wiringCollector) evaluateLambdaCall(call *wCall) ([]*wiringModifier, error) {
+	name, ok := call.Func.(*wName)
+	if !ok {
+		return nil, wiringErrorf(call.Line(), "Invalid modifier arguments %s", unparseWiringExpr(call))
+	}
+	lambda, ok := c.modifierLambda[name.Id]
+	if !ok {
+		return nil, wiringErrorf(call.Line(), "Modifier named %s is undefined", name.Id)
+	}
+	for idx, expanding := range c.expanding {
+		if expanding == name.Id {
+			cycle := append(append([]string{}, c.expanding[idx:]...), name.Id)
+			return nil, wiringErrorf(call.Line(), "Recursive lambda %s: %s", name.Id, strings.Join(cycle, " -> "))
+		}
+	}
+	mapping, err := c.getMapping(name.Id, lambda, call)
 	if err != nil {
-		w.logger.Fatal(err)
+		return nil, err
+	}
+	c.expanding = append(c.expanding, name.Id)
+	defer func() { c.expanding = c.expanding[:len(c.expanding)-1] }()
+	body := substituteWiringExpr(lambda.Body, mapping)
+	return c.evaluateLambda(body)
+}
+
+func (c *wiringCollector) evaluateLambda(body wiringExpr) ([]*wiringModifier, error) {
+	var modifiers []*wiringModifier
+	switch b := body.(type) {
+	case *wList:
+		for _, elt := range b.Elts {
+			switch e := elt.(type) {
+			case *wCall:
+				lambda_modifiers, err := c.evaluateLambdaCall(e)
+				if err != nil {
+					return nil, err
+				}
+				modifiers = append(modifiers, lambda_modifiers...)
+			default:
+				modifier, err := c.lookupModifier(e)
+				if err != nil {
+					return nil, err
+				}
+				modifiers = append(modifiers, modifier)
+			}
+		}
+	case *wCall:
+		modifier, err := c.parseModifierCall(b)
+		if err != nil {
+			return nil, err
+		}
+		modifiers = append(modifiers, modifier)
+	default:
+		return nil, wiringErrorf(body.Line(), "Lambda must produce a Modifier or a List[Modifier]")
+	}
+	return modifiers, nil
+}
+
+func (c *wiringCollector) getModifierList(opts []wiringExpr, instanceName string) ([]*wiringModifier, error) {
+	if len(opts) == 0 {
+		return nil, nil
+	}
+	switch arg := opts[0].(type) {
+	case *wName:
+		if modifier, ok := c.modifiers[arg.Id]; ok {
+			return []*wiringModifier{modifier}, nil
+		}
+		if list, ok := c.modifierLists[arg.Id]; ok {
+			var modifiers []*wiringModifier
+			for _, elt := range list.Elts {
+				modifier, err := c.lookupModifier(elt)
+				if err != nil {
+					return nil, err
+				}
+				modifiers = append(modifiers, modifier)
+			}
+			return modifiers, nil
+		}
+		return nil, wiringErrorf(arg.Line(), "Modifier named %s is undefined", arg.Id)
+	case *wCall:
+		return c.evaluateLambdaCall(arg)
 	}
-	defer wiringCompiledFile.Close()
+	return nil, wiringErrorf(opts[0].Line(), "Invalid modifier args provided for instance %s", instanceName)
+}
+
+// Conversion to the serialized representation consumed by the generators
+func (c *wiringCollector) position(line int) token.Position {
+	return token.Position{Filename: c.filename, Line: line}
+}
 
-	bytes, err := ioutil.ReadAll(wiringCompiledFile)
+func (c *wiringCollector) convertWiringParam(param *wiringParam) ArgumentNode {
+	arg := ArgumentNode{IsService: param.IsInstance, KeywordName: param.KeywordName, Value: unparseWiringExpr(param.Node), Pos: c.position(param.Node.Line())}
+	if param.IsInstance {
+		arg.Name = param.InstanceName
+	}
+	for _, modifier := range param.ClientModifiers {
+		arg.ClientModifiers = append(arg.ClientModifiers, c.convertWiringModifier(modifier))
+	}
+	return arg
+}
+
+func (c *wiringCollector) convertWiringModifier(modifier *wiringModifier) ModifierNode {
+	node := ModifierNode{ModifierType: modifier.ModifierType, Pos: c.position(modifier.Line)}
+	for _, param := range modifier.Params {
+		node.ModifierParams = append(node.ModifierParams, c.convertWiringParam(param))
+	}
+	return node
+}
+
+func (c *wiringCollector) buildServiceNode(instance *wiringInstance) (DetailNode, error) {
+	node := DetailNode{Name: instance.Name, Type: instance.ActualType, AbsType: instance.AbstractType, Pos: c.position(instance.Line)}
+	server_modifiers, err := c.getModifierList(instance.ServerOpts, instance.Name)
 	if err != nil {
-		w.logger.Fatal(err)
+		return node, err
+	}
+	client_modifiers, err := c.getModifierList(instance.ClientOpts, instance.Name)
+	if err != nil {
+		return node, err
+	}
+	for _, modifier := range server_modifiers {
+		node.ServerModifiers = append(node.ServerModifiers, c.convertWiringModifier(modifier))
 	}
-	var rootNode MillenialNode
-	json.Unmarshal(bytes, &rootNode)
+	for _, modifier := range client_modifiers {
+		node.ClientModifiers = append(node.ClientModifiers, c.convertWiringModifier(modifier))
+	}
+	for _, param := range instance.Params {
+		if param.ClientOpts != nil {
+			param.ClientModifiers, err = c.getModifierList(param.ClientOpts, instance.Name)
+			if err != nil {
+				return node, err
+			}
+		}
+		node.Arguments = append(node.Arguments, c.convertWiringParam(param))
+	}
+	return node, nil
+}
+
+// Builds the container hierarchy. Components get a container each, services are grouped into
+// their declared processes, and services not placed in any process get a process of their own.
+// Problems are reported through report and the offending instance is left out of the tree.
+func (c *wiringCollector) buildTree(report func(error)) *MillenialNode {
+	root := &MillenialNode{Name: "root"}
+	containerCounter := 1
+	nextContainer := func(children []DetailNode) {
+		root.Children = append(root.Children, ContainerNode{Name: "container" + strconv.Itoa(containerCounter), Children: children})
+		containerCounter += 1
+	}
+
+	serviceNodes := make(map[string]DetailNode)
+	var serviceOrder []string
+	for _, instance := range c.instances {
+		node, err := c.buildServiceNode(instance)
+		if err != nil {
+			report(err)
+			c.broken[instance.Name] = true
+			continue
+		}
+		if WiringComponents[instance.AbstractType] {
+			nextContainer([]DetailNode{node})
+		} else {
+			serviceNodes[instance.Name] = node
+			serviceOrder = append(serviceOrder, instance.Name)
+		}
+	}
+
+	seenServices := make(map[string]bool)
+	for _, process := range c.processes {
+		var children []DetailNode
+		for _, name := range process.Instances {
+			if seenServices[name] {
+				report(wiringErrorf(process.Line, "Double use of a service instance %s", name))
+				continue
+			}
+			node, ok := serviceNodes[name]
+			if !ok {
+				if !c.broken[name] {
+					report(wiringErrorf(process.Line, "Process %s uses undeclared service instance %s", process.Name, name))
+				}
+				continue
+			}
+			seenServices[name] = true
+			children = append(children, node)
+		}
+		nextContainer([]DetailNode{{Name: process.Name, AbsType: "Process", Children: children, Pos: c.position(process.Line)}})
+	}
+
+	// Take care of orphan services
+	numProcs := len(c.processes)
+	for _, name := range serviceOrder {
+		if seenServices[name] {
+			continue
+		}
+		numProcs += 1
+		nextContainer([]DetailNode{{Name: "Proc" + strconv.Itoa(numProcs), AbsType: "Process", Children: []DetailNode{serviceNodes[name]}}})
+	}
+	return root
+}
+
+// Translates the source of a wiring file into the serialized IR representation.
+// Every problem found is added to diags; nil is returned if the wiring file contains errors.
+func TranslateWiring(filename string, src string, diags *Diagnostics) *MillenialNode {
+	num_errors := diags.ErrorCount()
+	report := func(err error) {
+		line := 0
+		if werr, ok := err.(*wiringError); ok {
+			line = werr.line
+		}
+		diags.Errorf(token.Position{Filename: filename, Line: line}, "%s", err)
+	}
+	stmts, errs := parseWiringSource(src)
+	for _, err := range errs {
+		report(err)
+	}
+	collector := newWiringCollector(filename)
+	for _, stmt := range stmts {
+		if err := collector.collect(stmt); err != nil {
+			report(err)
+			collector.broken[stmt.Target] = true
+		}
+	}
+	root := collector.buildTree(report)
+	if diags.ErrorCount() > num_errors {
+		return nil
+	}
+	return root
+}
 
-	w.RootNode = &rootNode
+type WiringParser struct {
+	config   *Config
+	logger   *log.Logger
+	diags    *Diagnostics
+	RootNode *MillenialNode
+}
+
+func NewWiringParser(config *Config, logger *log.Logger, diags *Diagnostics) *WiringParser {
+	return &WiringParser{config: config, logger: logger, diags: diags, RootNode: nil}
+}
+
+func (w *WiringParser) ParseWiring() {
+	src, err := ioutil.ReadFile(w.config.WiringFile)
+	if err != nil {
+		w.diags.Errorf(token.Position{Filename: w.config.WiringFile}, "%v", err)
+		return
+	}
+	w.RootNode = TranslateWiring(w.config.WiringFile, string(src), w.diags)
+	if w.RootNode != nil {
+		w.config.checkInstanceNames(w.RootNode, w.diags)
+		w.logger.Println("Wiring Translation Completed")
+	}
 }
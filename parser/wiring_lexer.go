@@ -0,0 +1,258 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokens of the Python subset used by wiring files
+type wiringTokenKind int
+
+const (
+	tokEOF wiringTokenKind = iota
+	tokNewline
+	tokName
+	tokNumber
+	tokString
+	tokOp
+)
+
+type wiringToken struct {
+	Kind  wiringTokenKind
+	Text  string // Identifier, operator, raw number literal or decoded string value
+	Line  int
+	Float bool // Only set for number tokens
+}
+
+func (t wiringToken) String() string {
+	switch t.Kind {
+	case tokEOF:
+		return "end of file"
+	case tokNewline:
+		return "end of line"
+	case tokString:
+		return "string literal"
+	}
+	return "'" + t.Text + "'"
+}
+
+type wiringLexer struct {
+	src    []rune
+	pos    int
+	line   int
+	depth  int // Bracket nesting depth. Newlines are insignificant inside brackets.
+	tokens []wiringToken
+}
+
+func (l *wiringLexer) errorf(format string, args ...interface{}) error {
+	return wiringErrorf(l.line, format, args...)
+}
+
+func (l *wiringLexer) peekRune(offset int) rune {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *wiringLexer) emit(kind wiringTokenKind, text string, line int) {
+	l.tokens = append(l.tokens, wiringToken{Kind: kind, Text: text, Line: line})
+}
+
+func (l *wiringLexer) emitNewline() {
+	// Collapse blank lines and never start the stream with a newline
+	if len(l.tokens) == 0 || l.tokens[len(l.tokens)-1].Kind == tokNewline {
+		return
+	}
+	l.emit(tokNewline, "", l.line)
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isNameChar(r rune) bool {
+	return isNameStart(r) || unicode.IsDigit(r)
+}
+
+func (l *wiringLexer) lexNumber() error {
+	start := l.pos
+	isFloat := false
+	if l.peekRune(0) == '0' && strings.ContainsRune("xXoObB", l.peekRune(1)) {
+		l.pos += 2
+		for l.pos < len(l.src) && (unicode.IsDigit(l.src[l.pos]) || unicode.IsLetter(l.src[l.pos]) || l.src[l.pos] == '_') {
+			l.pos++
+		}
+	} else {
+		for l.pos < len(l.src) && (unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '_') {
+			l.pos++
+		}
+		if l.peekRune(0) == '.' {
+			isFloat = true
+			l.pos++
+			for l.pos < len(l.src) && (unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '_') {
+				l.pos++
+			}
+		}
+		if r := l.peekRune(0); r == 'e' || r == 'E' {
+			isFloat = true
+			l.pos++
+			if r := l.peekRune(0); r == '+' || r == '-' {
+				l.pos++
+			}
+			for l.pos < len(l.src) && unicode.IsDigit(l.src[l.pos]) {
+				l.pos++
+			}
+		}
+	}
+	if l.pos < len(l.src) && isNameStart(l.src[l.pos]) {
+		return l.errorf("invalid number literal %s", string(l.src[start:l.pos+1]))
+	}
+	l.tokens = append(l.tokens, wiringToken{Kind: tokNumber, Text: string(l.src[start:l.pos]), Line: l.line, Float: isFloat})
+	return nil
+}
+
+func (l *wiringLexer) lexString(raw bool) error {
+	line := l.line
+	quote := l.src[l.pos]
+	triple := l.peekRune(1) == quote && l.peekRune(2) == quote
+	if triple {
+		l.pos += 3
+	} else {
+		l.pos++
+	}
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			return wiringErrorf(line, "unterminated string literal")
+		}
+		r := l.src[l.pos]
+		if r == quote {
+			if !triple {
+				l.pos++
+				break
+			}
+			if l.peekRune(1) == quote && l.peekRune(2) == quote {
+				l.pos += 3
+				break
+			}
+		}
+		if r == '\n' {
+			if !triple {
+				return wiringErrorf(line, "unterminated string literal")
+			}
+			l.line++
+		}
+		if r == '\\' && l.pos+1 < len(l.src) {
+			next := l.src[l.pos+1]
+			if raw {
+				sb.WriteRune(r)
+				sb.WriteRune(next)
+				l.pos += 2
+				continue
+			}
+			l.pos += 2
+			switch next {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case '0':
+				sb.WriteRune(0)
+			case '\\', '\'', '"':
+				sb.WriteRune(next)
+			case '\n':
+				// Escaped newline is a line continuation inside the literal
+				l.line++
+			default:
+				sb.WriteRune('\\')
+				sb.WriteRune(next)
+			}
+			continue
+		}
+		sb.WriteRune(r)
+		l.pos++
+	}
+	l.emit(tokString, sb.String(), line)
+	return nil
+}
+
+// Splits the wiring source into tokens. Logical lines are terminated by a newline token.
+func lexWiring(src string) ([]wiringToken, error) {
+	if !utf8.ValidString(src) {
+		return nil, wiringErrorf(0, "wiring file is not valid UTF-8")
+	}
+	l := &wiringLexer{src: []rune(src), line: 1}
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+		switch {
+		case r == '\n':
+			if l.depth == 0 {
+				l.emitNewline()
+			}
+			l.line++
+			l.pos++
+		case r == ' ' || r == '\t' || r == '\r' || r == '\f':
+			l.pos++
+		case r == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case r == '\\' && l.peekRune(1) == '\n':
+			// Explicit line continuation
+			l.line++
+			l.pos += 2
+		case r == ';':
+			if l.depth == 0 {
+				l.emitNewline()
+			}
+			l.pos++
+		case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(l.peekRune(1))):
+			if err := l.lexNumber(); err != nil {
+				return nil, err
+			}
+		case r == '\'' || r == '"':
+			if err := l.lexString(false); err != nil {
+				return nil, err
+			}
+		case isNameStart(r):
+			start := l.pos
+			for l.pos < len(l.src) && isNameChar(l.src[l.pos]) {
+				l.pos++
+			}
+			name := string(l.src[start:l.pos])
+			if next := l.peekRune(0); (next == '\'' || next == '"') && (name == "r" || name == "R" || name == "u" || name == "U") {
+				if err := l.lexString(name == "r" || name == "R"); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			l.emit(tokName, name, l.line)
+		case strings.ContainsRune("([{", r):
+			l.depth++
+			l.emit(tokOp, string(r), l.line)
+			l.pos++
+		case strings.ContainsRune(")]}", r):
+			if l.depth == 0 {
+				return nil, l.errorf("unmatched '%c'", r)
+			}
+			l.depth--
+			l.emit(tokOp, string(r), l.line)
+			l.pos++
+		case strings.ContainsRune(",:=.-+*", r):
+			l.emit(tokOp, string(r), l.line)
+			l.pos++
+		default:
+			return nil, l.errorf("unexpected character '%c'", r)
+		}
+	}
+	if l.depth != 0 {
+		return nil, l.errorf("unexpected end of file inside brackets")
+	}
+	l.emitNewline()
+	l.emit(tokEOF, "", l.line)
+	return l.tokens, nil
+}
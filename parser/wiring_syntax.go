@@ -0,0 +1,578 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Expressions of the wiring language. The wiring language is a subset of Python so the
+// node types mirror the corresponding nodes of Python's ast module.
+type wiringExpr interface {
+	Line() int
+}
+
+type wName struct {
+	line int
+	Id   string
+}
+
+type wConstKind int
+
+const (
+	constString wConstKind = iota
+	constInt
+	constFloat
+	constBool
+	constNone
+)
+
+type wConst struct {
+	line  int
+	Kind  wConstKind
+	Value string // Decoded string value, or the normalized literal for other kinds
+}
+
+type wList struct {
+	line int
+	Elts []wiringExpr
+}
+
+type wTuple struct {
+	line int
+	Elts []wiringExpr
+}
+
+type wDict struct {
+	line   int
+	Keys   []wiringExpr
+	Values []wiringExpr
+}
+
+type wKeyword struct {
+	Arg   string
+	Value wiringExpr
+}
+
+type wCall struct {
+	line     int
+	Func     wiringExpr
+	Args     []wiringExpr
+	Keywords []wKeyword
+}
+
+type wAttribute struct {
+	line  int
+	Value wiringExpr
+	Attr  string
+}
+
+type wSubscript struct {
+	line  int
+	Value wiringExpr
+	Index wiringExpr
+}
+
+type wLambda struct {
+	line   int
+	Params []string
+	Body   wiringExpr
+}
+
+type wUnary struct {
+	line    int
+	Op      string
+	Operand wiringExpr
+}
+
+func (e *wName) Line() int      { return e.line }
+func (e *wConst) Line() int     { return e.line }
+func (e *wList) Line() int      { return e.line }
+func (e *wTuple) Line() int     { return e.line }
+func (e *wDict) Line() int      { return e.line }
+func (e *wCall) Line() int      { return e.line }
+func (e *wAttribute) Line() int { return e.line }
+func (e *wSubscript) Line() int { return e.line }
+func (e *wLambda) Line() int    { return e.line }
+func (e *wUnary) Line() int     { return e.line }
+
+// Top-level statements of a wiring file
+type wiringStmt struct {
+	Line       int
+	Target     string
+	Annotation wiringExpr // nil for un-annotated assignments
+	Value      wiringExpr // nil for bare annotations
+}
+
+type wiringSyntaxParser struct {
+	tokens []wiringToken
+	pos    int
+}
+
+func (p *wiringSyntaxParser) peek() wiringToken {
+	return p.tokens[p.pos]
+}
+
+func (p *wiringSyntaxParser) next() wiringToken {
+	tok := p.tokens[p.pos]
+	if tok.Kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *wiringSyntaxParser) isOp(op string) bool {
+	tok := p.peek()
+	return tok.Kind == tokOp && tok.Text == op
+}
+
+func (p *wiringSyntaxParser) expectOp(op string) error {
+	tok := p.next()
+	if tok.Kind != tokOp || tok.Text != op {
+		return wiringErrorf(tok.Line, "expected '%s' but found %s", op, tok)
+	}
+	return nil
+}
+
+// Skips the remaining tokens of the current logical line
+func (p *wiringSyntaxParser) skipLine() {
+	for p.peek().Kind != tokNewline && p.peek().Kind != tokEOF {
+		p.next()
+	}
+	p.next()
+}
+
+// Parses the wiring source into a list of statements. Import statements are skipped.
+// A syntax error only discards the statement it occurs in so that all errors can be reported.
+func parseWiringSource(src string) ([]wiringStmt, []error) {
+	tokens, err := lexWiring(src)
+	if err != nil {
+		return nil, []error{err}
+	}
+	p := &wiringSyntaxParser{tokens: tokens}
+	var stmts []wiringStmt
+	var errs []error
+	for p.peek().Kind != tokEOF {
+		tok := p.peek()
+		if tok.Kind == tokName && (tok.Text == "import" || tok.Text == "from") {
+			p.skipLine()
+			continue
+		}
+		stmt, err := p.parseStatement()
+		if err != nil {
+			errs = append(errs, err)
+			p.skipLine()
+			continue
+		}
+		if tok := p.peek(); tok.Kind != tokNewline {
+			errs = append(errs, wiringErrorf(tok.Line, "unexpected %s", tok))
+			p.skipLine()
+			continue
+		}
+		p.next()
+		if stmt != nil {
+			stmts = append(stmts, *stmt)
+		}
+	}
+	return stmts, errs
+}
+
+func (p *wiringSyntaxParser) parseStatement() (*wiringStmt, error) {
+	line := p.peek().Line
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	var annotation wiringExpr
+	if p.isOp(":") {
+		p.next()
+		if annotation, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if !p.isOp("=") {
+		if annotation == nil {
+			// Bare expression statements have no effect on the wiring
+			return nil, nil
+		}
+	} else {
+		p.next()
+	}
+	target, ok := expr.(*wName)
+	if !ok {
+		return nil, wiringErrorf(line, "assignment target must be a name")
+	}
+	var value wiringExpr
+	if p.peek().Kind != tokNewline {
+		if value, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return &wiringStmt{Line: line, Target: target.Id, Annotation: annotation, Value: value}, nil
+}
+
+func (p *wiringSyntaxParser) parseExpr() (wiringExpr, error) {
+	tok := p.peek()
+	if tok.Kind == tokName && tok.Text == "lambda" {
+		return p.parseLambda()
+	}
+	return p.parseUnary()
+}
+
+func (p *wiringSyntaxParser) parseLambda() (wiringExpr, error) {
+	line := p.next().Line
+	var params []string
+	for !p.isOp(":") {
+		tok := p.next()
+		if tok.Kind != tokName {
+			return nil, wiringErrorf(tok.Line, "expected lambda parameter name but found %s", tok)
+		}
+		params = append(params, tok.Text)
+		if !p.isOp(":") {
+			if err := p.expectOp(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	p.next()
+	body, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &wLambda{line: line, Params: params, Body: body}, nil
+}
+
+func (p *wiringSyntaxParser) parseUnary() (wiringExpr, error) {
+	if p.isOp("-") || p.isOp("+") {
+		tok := p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &wUnary{line: tok.Line, Op: tok.Text, Operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *wiringSyntaxParser) parsePostfix() (wiringExpr, error) {
+	expr, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.isOp("."):
+			p.next()
+			tok := p.next()
+			if tok.Kind != tokName {
+				return nil, wiringErrorf(tok.Line, "expected attribute name but found %s", tok)
+			}
+			expr = &wAttribute{line: tok.Line, Value: expr, Attr: tok.Text}
+		case p.isOp("("):
+			line := p.next().Line
+			call := &wCall{line: line, Func: expr}
+			if err := p.parseCallArgs(call); err != nil {
+				return nil, err
+			}
+			expr = call
+		case p.isOp("["):
+			line := p.next().Line
+			elts, trailing, err := p.parseExprList("]")
+			if err != nil {
+				return nil, err
+			}
+			if len(elts) == 0 {
+				return nil, wiringErrorf(line, "empty subscript")
+			}
+			var index wiringExpr = &wTuple{line: line, Elts: elts}
+			if len(elts) == 1 && !trailing {
+				index = elts[0]
+			}
+			expr = &wSubscript{line: line, Value: expr, Index: index}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *wiringSyntaxParser) parseCallArgs(call *wCall) error {
+	for !p.isOp(")") {
+		tok := p.peek()
+		if tok.Kind == tokName && p.tokens[p.pos+1].Kind == tokOp && p.tokens[p.pos+1].Text == "=" {
+			p.pos += 2
+			value, err := p.parseExpr()
+			if err != nil {
+				return err
+			}
+			call.Keywords = append(call.Keywords, wKeyword{Arg: tok.Text, Value: value})
+		} else {
+			if len(call.Keywords) > 0 {
+				return wiringErrorf(tok.Line, "positional argument follows keyword argument")
+			}
+			arg, err := p.parseExpr()
+			if err != nil {
+				return err
+			}
+			call.Args = append(call.Args, arg)
+		}
+		if !p.isOp(")") {
+			if err := p.expectOp(","); err != nil {
+				return err
+			}
+		}
+	}
+	p.next()
+	return nil
+}
+
+// Parses comma separated expressions up to the closing bracket. Also reports whether a trailing comma was present.
+func (p *wiringSyntaxParser) parseExprList(closing string) ([]wiringExpr, bool, error) {
+	var elts []wiringExpr
+	trailing := false
+	for !p.isOp(closing) {
+		elt, err := p.parseExpr()
+		if err != nil {
+			return nil, false, err
+		}
+		elts = append(elts, elt)
+		trailing = false
+		if !p.isOp(closing) {
+			if err := p.expectOp(","); err != nil {
+				return nil, false, err
+			}
+			trailing = true
+		}
+	}
+	p.next()
+	return elts, trailing, nil
+}
+
+func (p *wiringSyntaxParser) parseAtom() (wiringExpr, error) {
+	tok := p.next()
+	switch tok.Kind {
+	case tokName:
+		switch tok.Text {
+		case "True", "False":
+			return &wConst{line: tok.Line, Kind: constBool, Value: tok.Text}, nil
+		case "None":
+			return &wConst{line: tok.Line, Kind: constNone, Value: tok.Text}, nil
+		case "lambda", "import", "from":
+			return nil, wiringErrorf(tok.Line, "unexpected keyword '%s'", tok.Text)
+		}
+		return &wName{line: tok.Line, Id: tok.Text}, nil
+	case tokNumber:
+		return parseNumberLiteral(tok)
+	case tokString:
+		value := tok.Text
+		// Adjacent string literals are concatenated
+		for p.peek().Kind == tokString {
+			value += p.next().Text
+		}
+		return &wConst{line: tok.Line, Kind: constString, Value: value}, nil
+	case tokOp:
+		switch tok.Text {
+		case "(":
+			elts, trailing, err := p.parseExprList(")")
+			if err != nil {
+				return nil, err
+			}
+			if len(elts) == 1 && !trailing {
+				return elts[0], nil
+			}
+			return &wTuple{line: tok.Line, Elts: elts}, nil
+		case "[":
+			elts, _, err := p.parseExprList("]")
+			if err != nil {
+				return nil, err
+			}
+			return &wList{line: tok.Line, Elts: elts}, nil
+		case "{":
+			return p.parseDict(tok.Line)
+		}
+	}
+	return nil, wiringErrorf(tok.Line, "unexpected %s", tok)
+}
+
+func (p *wiringSyntaxParser) parseDict(line int) (wiringExpr, error) {
+	dict := &wDict{line: line}
+	for !p.isOp("}") {
+		key, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		dict.Keys = append(dict.Keys, key)
+		dict.Values = append(dict.Values, value)
+		if !p.isOp("}") {
+			if err := p.expectOp(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	p.next()
+	return dict, nil
+}
+
+func parseNumberLiteral(tok wiringToken) (wiringExpr, error) {
+	literal := strings.ReplaceAll(tok.Text, "_", "")
+	if tok.Float {
+		f, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			return nil, wiringErrorf(tok.Line, "invalid number literal %s", tok.Text)
+		}
+		return &wConst{line: tok.Line, Kind: constFloat, Value: pythonFloatRepr(f)}, nil
+	}
+	i, ok := new(big.Int).SetString(literal, 0)
+	if !ok {
+		return nil, wiringErrorf(tok.Line, "invalid number literal %s", tok.Text)
+	}
+	return &wConst{line: tok.Line, Kind: constInt, Value: i.String()}, nil
+}
+
+// Formats a float the way Python's repr does
+func pythonFloatRepr(f float64) string {
+	if math.IsInf(f, 1) {
+		return "inf"
+	}
+	abs := math.Abs(f)
+	if abs != 0 && (abs < 1e-4 || abs >= 1e16) {
+		return strconv.FormatFloat(f, 'e', -1, 64)
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// Formats a string the way Python's repr does
+func pythonStringRepr(s string) string {
+	quote := '\''
+	if strings.ContainsRune(s, '\'') && !strings.ContainsRune(s, '"') {
+		quote = '"'
+	}
+	var sb strings.Builder
+	sb.WriteRune(quote)
+	for _, r := range s {
+		switch {
+		case r == quote || r == '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString("\\n")
+		case r == '\t':
+			sb.WriteString("\\t")
+		case r == '\r':
+			sb.WriteString("\\r")
+		case r < 0x20 || r == 0x7f:
+			sb.WriteString(fmt.Sprintf("\\x%02x", r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteRune(quote)
+	return sb.String()
+}
+
+func unparseList(elts []wiringExpr) string {
+	var strs []string
+	for _, elt := range elts {
+		strs = append(strs, unparseWiringExpr(elt))
+	}
+	return strings.Join(strs, ", ")
+}
+
+// Converts an expression back to its source form. The output follows the conventions of
+// Python's unparser (single quoted strings, normalized numbers) that the generators expect.
+func unparseWiringExpr(expr wiringExpr) string {
+	switch e := expr.(type) {
+	case *wName:
+		return e.Id
+	case *wConst:
+		if e.Kind == constString {
+			return pythonStringRepr(e.Value)
+		}
+		return e.Value
+	case *wList:
+		return "[" + unparseList(e.Elts) + "]"
+	case *wTuple:
+		if len(e.Elts) == 1 {
+			return "(" + unparseWiringExpr(e.Elts[0]) + ",)"
+		}
+		return "(" + unparseList(e.Elts) + ")"
+	case *wDict:
+		var strs []string
+		for idx, key := range e.Keys {
+			strs = append(strs, unparseWiringExpr(key)+": "+unparseWiringExpr(e.Values[idx]))
+		}
+		return "{" + strings.Join(strs, ", ") + "}"
+	case *wCall:
+		args := []string{}
+		for _, arg := range e.Args {
+			args = append(args, unparseWiringExpr(arg))
+		}
+		for _, kw := range e.Keywords {
+			args = append(args, kw.Arg+"="+unparseWiringExpr(kw.Value))
+		}
+		return unparseWiringExpr(e.Func) + "(" + strings.Join(args, ", ") + ")"
+	case *wAttribute:
+		return unparseWiringExpr(e.Value) + "." + e.Attr
+	case *wSubscript:
+		index := unparseWiringExpr(e.Index)
+		if t, ok := e.Index.(*wTuple); ok && len(t.Elts) > 1 {
+			index = unparseList(t.Elts)
+		}
+		return unparseWiringExpr(e.Value) + "[" + index + "]"
+	case *wLambda:
+		return "(lambda " + strings.Join(e.Params, ", ") + ": " + unparseWiringExpr(e.Body) + ")"
+	case *wUnary:
+		return e.Op + unparseWiringExpr(e.Operand)
+	}
+	return ""
+}
+
+// Returns a copy of the expression with every name in the mapping replaced by its bound value
+func substituteWiringExpr(expr wiringExpr, mapping map[string]wiringExpr) wiringExpr {
+	switch e := expr.(type) {
+	case *wName:
+		if val, ok := mapping[e.Id]; ok {
+			return val
+		}
+		return e
+	case *wList:
+		return &wList{line: e.line, Elts: substituteWiringExprs(e.Elts, mapping)}
+	case *wTuple:
+		return &wTuple{line: e.line, Elts: substituteWiringExprs(e.Elts, mapping)}
+	case *wDict:
+		return &wDict{line: e.line, Keys: substituteWiringExprs(e.Keys, mapping), Values: substituteWiringExprs(e.Values, mapping)}
+	case *wCall:
+		call := &wCall{line: e.line, Func: substituteWiringExpr(e.Func, mapping), Args: substituteWiringExprs(e.Args, mapping)}
+		for _, kw := range e.Keywords {
+			call.Keywords = append(call.Keywords, wKeyword{Arg: kw.Arg, Value: substituteWiringExpr(kw.Value, mapping)})
+		}
+		return call
+	case *wAttribute:
+		return &wAttribute{line: e.line, Value: substituteWiringExpr(e.Value, mapping), Attr: e.Attr}
+	case *wSubscript:
+		return &wSubscript{line: e.line, Value: substituteWiringExpr(e.Value, mapping), Index: substituteWiringExpr(e.Index, mapping)}
+	case *wUnary:
+		return &wUnary{line: e.line, Op: e.Op, Operand: substituteWiringExpr(e.Operand, mapping)}
+	}
+	return expr
+}
+
+func substituteWiringExprs(exprs []wiringExpr, mapping map[string]wiringExpr) []wiringExpr {
+	var result []wiringExpr
+	for _, expr := range exprs {
+		result = append(result, substituteWiringExpr(expr, mapping))
+	}
+	return result
+}
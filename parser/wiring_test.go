@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Drops empty lists and null values so that output of the old python translator can be compared with ours
+func normalizeJSON(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{})
+		for k, elem := range val {
+			elem = normalizeJSON(elem)
+			if elem == nil {
+				continue
+			}
+			res[k] = elem
+		}
+		return res
+	case []interface{}:
+		if len(val) == 0 {
+			return nil
+		}
+		var res []interface{}
+		for _, elem := range val {
+			res = append(res, normalizeJSON(elem))
+		}
+		return res
+	case string:
+		if val == "" {
+			return nil
+		}
+	}
+	return v
+}
+
+func toNormalizedJSON(t *testing.T, v interface{}) interface{} {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(bytes, &generic); err != nil {
+		t.Fatal(err)
+	}
+	return normalizeJSON(generic)
+}
+
+func TestTranslateWiringMatchesPythonTranslator(t *testing.T) {
+	src, err := ioutil.ReadFile("../examples/Leaf/wiring/instances.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diags := NewDiagnostics()
+	root := TranslateWiring("instances.py", string(src), diags)
+	if diags.HasErrors() {
+		t.Fatal(diags.Items)
+	}
+	expectedBytes, err := ioutil.ReadFile("testdata/leaf_instances_compiled.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected interface{}
+	if err := json.Unmarshal(expectedBytes, &expected); err != nil {
+		t.Fatal(err)
+	}
+	actual := toNormalizedJSON(t, root)
+	if !reflect.DeepEqual(normalizeJSON(expected), actual) {
+		actualBytes, _ := json.MarshalIndent(root, "", "    ")
+		t.Errorf("Translated wiring does not match the python translator output. Got:\n%s", actualBytes)
+	}
+}
+
+func TestTranslateWiringProcesses(t *testing.T) {
+	src := `
+server_modifiers : List[Modifier] = [WebServerOpts, deployer]
+WebServerOpts : Modifier = WebServer(framework="default")
+deployer : Modifier = Deployer(framework="docker")
+leafService : LeafService = LeafServiceImpl()
+nonleafService : NonLeafService = NonLeafServiceImpl(leafService=leafService).WithServer(server_modifiers)
+process : Process = Process(services=[leafService, nonleafService])
+`
+	diags := NewDiagnostics()
+	root := TranslateWiring("wiring.py", src, diags)
+	if diags.HasErrors() {
+		t.Fatal(diags.Items)
+	}
+	if len(root.Children) != 1 {
+		t.Fatalf("Expected 1 container, got %d", len(root.Children))
+	}
+	proc := root.Children[0].Children[0]
+	if proc.Name != "process" || proc.AbsType != "Process" || len(proc.Children) != 2 {
+		t.Errorf("Unexpected process node %+v", proc)
+	}
+	nonleaf := proc.Children[1]
+	if len(nonleaf.ServerModifiers) != 2 || nonleaf.ServerModifiers[0].ModifierParams[0].Value != "'default'" {
+		t.Errorf("Unexpected server modifiers %+v", nonleaf.ServerModifiers)
+	}
+	if arg := nonleaf.Arguments[0]; !arg.IsService || arg.Name != "leafService" {
+		t.Errorf("Unexpected argument %+v", arg)
+	}
+	if nonleaf.Pos.Line != 6 || nonleaf.ServerModifiers[1].Pos.Line != 4 || nonleaf.Pos.Filename != "wiring.py" {
+		t.Errorf("Unexpected positions %v %v", nonleaf.Pos, nonleaf.ServerModifiers[1].Pos)
+	}
+}
+
+func TestTranslateWiringErrors(t *testing.T) {
+	cases := map[string]string{
+		"x = RPCServer()":                           "wiring.py:1: error: UnAnnotated",
+		"a : Service = A()\na : Service = A()":      "wiring.py:2: error: Instance Name a has been previously used!",
+		"a : Service = A().WithServer(missing)":     "wiring.py:1: error: Modifier named missing is undefined",
+		"a : Service = A(b=foo.bar)":                "wiring.py:1: error: Argument to service instantiation",
+		"p : Process = Process(services=[ghost])":   "wiring.py:1: error: Process p uses undeclared service instance ghost",
+		"m : Modifier = RPCServer(framework='grpc'": "wiring.py:1: error: unexpected end of file",
+	}
+	for src, expected := range cases {
+		diags := NewDiagnostics()
+		root := TranslateWiring("wiring.py", src, diags)
+		if root != nil || len(diags.Items) != 1 || !strings.HasPrefix(diags.Items[0].String(), expected) {
+			t.Errorf("Expected a single diagnostic starting with %q for %q, got %v", expected, src, diags.Items)
+		}
+	}
+}
+
+func TestTranslateWiringLambdaErrors(t *testing.T) {
+	header := "m : Modifier = RPCServer(framework='grpc')\n"
+	cases := map[string]string{
+		"f : Callable[str, List[Modifier]] = lambda a: [f(a)]\ns : Service = S().WithServer(f(1))":                                                       "wiring.py:2: error: Recursive lambda f: f -> f",
+		"f : Callable[str, List[Modifier]] = lambda a: [g(a)]\ng : Callable[str, List[Modifier]] = lambda a: [f(a)]\ns : Service = S().WithServer(f(1))": "wiring.py:3: error: Recursive lambda f: f -> g -> f",
+		"f : Callable[str, List[Modifier]] = lambda a: [m]\ns : Service = S().WithServer(f(1, 2))":                                                       "wiring.py:3: error: Lambda f takes 1 arguments but 2 were given",
+		"f : Callable[str, List[Modifier]] = lambda a, b: [m]\ns : Service = S().WithServer(f(1))":                                                       "wiring.py:3: error: Lambda f is missing argument b",
+		"f : Callable[str, List[Modifier]] = lambda a: [m]\ns : Service = S().WithServer(f(b=1))":                                                        "wiring.py:3: error: Lambda f has no argument named b",
+	}
+	for src, expected := range cases {
+		diags := NewDiagnostics()
+		TranslateWiring("wiring.py", header+src, diags)
+		if len(diags.Items) != 1 || !strings.HasPrefix(diags.Items[0].String(), expected) {
+			t.Errorf("Expected a single diagnostic starting with %q for %q, got %v", expected, src, diags.Items)
+		}
+	}
+}
+
+func TestTranslateWiringReportsAllErrors(t *testing.T) {
+	src := `
+a : Service = A(b=foo.bar)
+x = RPCServer()
+c : Service = C(a=a)
+d : Service = D(x=)
+e : Service = E().WithServer(missing)
+p : Process = Process(services=[a, c])
+`
+	diags := NewDiagnostics()
+	TranslateWiring("wiring.py", src, diags)
+	var lines []int
+	for _, diag := range diags.Sorted() {
+		lines = append(lines, diag.Pos.Line)
+	}
+	// The reference to the broken instance a in the process is not reported again
+	if !reflect.DeepEqual(lines, []int{2, 3, 5, 6}) {
+		t.Errorf("Unexpected diagnostics %v", diags.Items)
+	}
+}
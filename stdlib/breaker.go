@@ -0,0 +1,314 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/debug"
+)
+
+// Trip strategies of a CircuitBreaker
+const (
+	TripFailureRate         = "failure_rate"         // Trip once the ratio of failed calls reaches the threshold
+	TripConsecutiveFailures = "consecutive_failures" // Trip once threshold calls in a row failed
+	TripSlowCallRate        = "slow_call_rate"       // Trip once the ratio of calls slower than slow_call reaches the threshold
+)
+
+// ErrBreakerOpen is returned for the calls that a CircuitBreaker rejects
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// States of a CircuitBreaker
+const (
+	BreakerClosed   = "closed"    // Calls pass and their outcomes are counted
+	BreakerOpen     = "open"      // Calls are rejected until the open timeout expires
+	BreakerHalfOpen = "half_open" // A number of probe calls pass, the breaker closes if they all succeed
+)
+
+// BreakerPolicy configures when a breaker trips and how it recovers
+type BreakerPolicy struct {
+	Trip           string
+	Threshold      float64       // Ratio of the calls for the rate strategies, number of calls for consecutive_failures
+	MinSamples     int64         // Calls counted before the rate strategies may trip
+	SlowCall       time.Duration // Duration from which a call is slow
+	Interval       time.Duration // Interval after which the counters of a closed breaker are reset
+	OpenTimeout    time.Duration // Time a breaker stays open before it lets probe calls pass
+	HalfOpenProbes int64         // Probe calls that have to succeed to close the breaker
+}
+
+// A breaker and the outcomes of the calls it let pass
+type breaker struct {
+	name   string
+	policy BreakerPolicy
+
+	state       string
+	since       time.Time // Start of the state, or of the interval of the counters while closed
+	calls       int64
+	failures    int64
+	slow        int64
+	consecutive int64
+	probes      int64 // Probe calls let pass while half open
+	successes   int64 // Successful probe calls
+}
+
+func (b *breaker) transition(to string, now time.Time) {
+	b.state = to
+	b.since = now
+	b.calls, b.failures, b.slow, b.consecutive, b.probes, b.successes = 0, 0, 0, 0, 0, 0
+}
+
+// Reports whether a call may pass, and whether it made the breaker half open
+func (b *breaker) allow(now time.Time) (bool, bool) {
+	switch b.state {
+	case BreakerClosed:
+		if b.policy.Interval > 0 && now.Sub(b.since) >= b.policy.Interval {
+			b.transition(BreakerClosed, now)
+		}
+		return true, false
+	case BreakerOpen:
+		if now.Sub(b.since) < b.policy.OpenTimeout {
+			return false, false
+		}
+		b.transition(BreakerHalfOpen, now)
+		b.probes++
+		return true, true
+	}
+	if b.probes >= b.policy.HalfOpenProbes {
+		return false, false
+	}
+	b.probes++
+	return true, false
+}
+
+// Records the outcome of a call and returns the state that the breaker moved to, if it changed
+func (b *breaker) done(failed bool, slow bool, now time.Time) (string, bool) {
+	switch b.state {
+	case BreakerHalfOpen:
+		if failed || (slow && b.policy.Trip == TripSlowCallRate) {
+			b.transition(BreakerOpen, now)
+			return BreakerOpen, true
+		}
+		b.successes++
+		if b.successes >= b.policy.HalfOpenProbes {
+			b.transition(BreakerClosed, now)
+			return BreakerClosed, true
+		}
+		return b.state, false
+	case BreakerOpen:
+		// The call started before the breaker tripped
+		return b.state, false
+	}
+	b.calls++
+	if failed {
+		b.failures++
+		b.consecutive++
+	} else {
+		b.consecutive = 0
+	}
+	if slow {
+		b.slow++
+	}
+	var trip bool
+	switch b.policy.Trip {
+	case TripConsecutiveFailures:
+		trip = float64(b.consecutive) >= b.policy.Threshold
+	case TripFailureRate:
+		trip = b.calls >= b.policy.MinSamples && float64(b.failures) >= b.policy.Threshold*float64(b.calls)
+	case TripSlowCallRate:
+		trip = b.calls >= b.policy.MinSamples && float64(b.slow) >= b.policy.Threshold*float64(b.calls)
+	}
+	if !trip {
+		return b.state, false
+	}
+	b.transition(BreakerOpen, now)
+	return BreakerOpen, true
+}
+
+// CircuitBreaker rejects the calls of a client while the service fails them. The methods of the client share one
+// breaker, unless PerMethod is set or the method has a policy of its own in Methods. Every state transition is
+// reported as the metric <name>:CircuitBreaker, or <name>.<method>:CircuitBreaker for the breaker of a method, with
+// the new state as the value.
+type CircuitBreaker struct {
+	Name          string
+	Policy        BreakerPolicy
+	Methods       map[string]BreakerPolicy // Policies of the methods that don't use Policy
+	PerMethod     bool                     // Whether every method has a breaker of its own
+	OnStateChange func(method string, from string, to string)
+
+	lock     sync.Mutex
+	breakers map[string]*breaker
+	now      func() time.Time
+}
+
+func NewCircuitBreaker(name string, policy BreakerPolicy, methods map[string]BreakerPolicy, perMethod bool) *CircuitBreaker {
+	return &CircuitBreaker{Name: name, Policy: policy, Methods: methods, PerMethod: perMethod, breakers: make(map[string]*breaker), now: time.Now}
+}
+
+// Returns the breaker of the calls of method
+func (cb *CircuitBreaker) breaker(method string) *breaker {
+	policy, ok := cb.Methods[method]
+	if !ok {
+		policy = cb.Policy
+		if !cb.PerMethod {
+			method = ""
+		}
+	}
+	b, ok := cb.breakers[method]
+	if !ok {
+		name := cb.Name
+		if method != "" {
+			name += "." + method
+		}
+		b = &breaker{name: name, policy: policy, state: BreakerClosed, since: cb.now()}
+		cb.breakers[method] = b
+	}
+	return b
+}
+
+// State returns the state of the breaker of method
+func (cb *CircuitBreaker) State(method string) string {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	return cb.breaker(method).state
+}
+
+func (cb *CircuitBreaker) report(b *breaker, method string, from string, to string) {
+	debug.ReportMetric(b.name+":CircuitBreaker", to)
+	if cb.OnStateChange != nil {
+		cb.OnStateChange(method, from, to)
+	}
+}
+
+// Do calls call unless the breaker of method is open, in which case it returns ErrBreakerOpen, and records the
+// outcome of the call. Calls that fail because ctx was cancelled by the caller aren't counted, calls that a Timeout
+// cancelled count as slow calls.
+func (cb *CircuitBreaker) Do(ctx context.Context, method string, call func() error) error {
+	cb.lock.Lock()
+	b := cb.breaker(method)
+	from := b.state
+	ok, halfOpened := b.allow(cb.now())
+	cb.lock.Unlock()
+	if halfOpened {
+		cb.report(b, method, from, BreakerHalfOpen)
+	}
+	if !ok {
+		return ErrBreakerOpen
+	}
+	start := cb.now()
+	err := call()
+	end := cb.now()
+
+	cb.lock.Lock()
+	from = b.state
+	var to string
+	var changed bool
+	if errors.Is(err, context.Canceled) && ctx.Err() == context.Canceled {
+		if b.state == BreakerHalfOpen {
+			b.probes--
+		}
+	} else {
+		// Calls that a Timeout cancelled took at least as long as their timeout
+		slow := errors.Is(err, ErrTimeout) || (b.policy.SlowCall > 0 && end.Sub(start) >= b.policy.SlowCall)
+		to, changed = b.done(err != nil, slow, end)
+	}
+	cb.lock.Unlock()
+	if changed {
+		cb.report(b, method, from, to)
+	}
+	return err
+}
+
+// ParseCircuitBreaker creates the CircuitBreaker name from the options of the CircuitBreaker modifier in the wiring
+// file: trip (the strategy), threshold, min_samples, slow_call, interval, open_timeout, half_open_probes, per_method
+// and methods, a dictionary of the options of single methods.
+func ParseCircuitBreaker(name string, opts map[string]string) (*CircuitBreaker, error) {
+	o := make(options)
+	for key, value := range opts {
+		o[key] = value
+	}
+	policy, err := parseBreakerPolicy(o)
+	if err != nil {
+		return nil, err
+	}
+	perMethod, err := o.bool("per_method", false)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := o.methods("methods")
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[string]BreakerPolicy)
+	for _, method := range sortedKeys(methods) {
+		methodOpts := methods[method]
+		if err := methodOpts.check("trip", "threshold", "min_samples", "slow_call", "interval", "open_timeout", "half_open_probes"); err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+		merged := o.overlay(methodOpts)
+		if _, ok := methodOpts["trip"]; ok {
+			// The threshold of another strategy doesn't carry over
+			if _, ok := methodOpts["threshold"]; !ok {
+				delete(merged, "threshold")
+			}
+		}
+		policies[method], err = parseBreakerPolicy(merged)
+		if err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+	}
+	return NewCircuitBreaker(name, policy, policies, perMethod), nil
+}
+
+func parseBreakerPolicy(o options) (BreakerPolicy, error) {
+	var policy BreakerPolicy
+	var err error
+	if policy.Trip, err = o.string("trip", TripFailureRate); err != nil {
+		return policy, err
+	}
+	switch policy.Trip {
+	case TripFailureRate:
+		policy.Threshold, err = o.float("threshold", 0.1)
+	case TripSlowCallRate:
+		policy.Threshold, err = o.float("threshold", 0.5)
+	case TripConsecutiveFailures:
+		var count int64
+		count, err = o.int("threshold", 5)
+		policy.Threshold = float64(count)
+		if err == nil && count < 1 {
+			err = fmt.Errorf("threshold: %d is less than 1", count)
+		}
+	default:
+		err = fmt.Errorf("trip: unknown strategy %s, expected one of failure_rate, consecutive_failures, slow_call_rate", policy.Trip)
+	}
+	if err != nil {
+		return policy, err
+	}
+	if policy.Trip != TripConsecutiveFailures && (policy.Threshold <= 0 || policy.Threshold > 1 || math.IsNaN(policy.Threshold)) {
+		return policy, fmt.Errorf("threshold: %v is not a ratio between 0 and 1", policy.Threshold)
+	}
+	if policy.MinSamples, err = o.int("min_samples", 1000); err != nil {
+		return policy, err
+	}
+	if policy.MinSamples < 1 {
+		return policy, fmt.Errorf("min_samples: %d is less than 1", policy.MinSamples)
+	}
+	if policy.SlowCall, err = o.duration("slow_call", time.Second); err != nil {
+		return policy, err
+	}
+	if policy.Interval, err = o.duration("interval", 0); err != nil {
+		return policy, err
+	}
+	if policy.OpenTimeout, err = o.duration("open_timeout", 10*time.Second); err != nil {
+		return policy, err
+	}
+	if policy.HalfOpenProbes, err = o.int("half_open_probes", 1); err != nil {
+		return policy, err
+	}
+	if policy.HalfOpenProbes < 1 {
+		return policy, fmt.Errorf("half_open_probes: %d is less than 1", policy.HalfOpenProbes)
+	}
+	return policy, nil
+}
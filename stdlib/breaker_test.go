@@ -0,0 +1,144 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns a breaker whose clock only moves when the calls of the test advance it
+func testBreaker(policy BreakerPolicy, methods map[string]BreakerPolicy, perMethod bool) (*CircuitBreaker, *time.Time, *[]string) {
+	now := time.Unix(0, 0)
+	var transitions []string
+	cb := NewCircuitBreaker("leafService", policy, methods, perMethod)
+	cb.now = func() time.Time { return now }
+	cb.OnStateChange = func(method string, from string, to string) {
+		transitions = append(transitions, method+": "+from+" -> "+to)
+	}
+	return cb, &now, &transitions
+}
+
+func TestCircuitBreakerTrips(t *testing.T) {
+	failure := errors.New("unavailable")
+	tests := []struct {
+		policy   BreakerPolicy
+		outcomes []error // Outcomes of the calls until the breaker trips
+	}{
+		{BreakerPolicy{Trip: TripConsecutiveFailures, Threshold: 3}, []error{failure, nil, failure, failure, failure}},
+		{BreakerPolicy{Trip: TripFailureRate, Threshold: 0.5, MinSamples: 4}, []error{failure, nil, failure, nil}},
+	}
+	for _, test := range tests {
+		test.policy.OpenTimeout = time.Second
+		test.policy.HalfOpenProbes = 1
+		cb, _, _ := testBreaker(test.policy, nil, false)
+		for idx, outcome := range test.outcomes {
+			if state := cb.State("Leaf"); state != BreakerClosed {
+				t.Errorf("%s: expected the breaker to be closed before call %d, got %s", test.policy.Trip, idx, state)
+			}
+			cb.Do(context.Background(), "Leaf", func() error { return outcome })
+		}
+		if state := cb.State("Leaf"); state != BreakerOpen {
+			t.Errorf("%s: expected the breaker to be open, got %s", test.policy.Trip, state)
+		}
+	}
+}
+
+func TestCircuitBreakerSlowCalls(t *testing.T) {
+	cb, now, _ := testBreaker(BreakerPolicy{Trip: TripSlowCallRate, Threshold: 0.5, MinSamples: 2, SlowCall: time.Second, OpenTimeout: time.Second, HalfOpenProbes: 1}, nil, false)
+	cb.Do(context.Background(), "Leaf", func() error { return nil })
+	cb.Do(context.Background(), "Leaf", func() error {
+		*now = now.Add(2 * time.Second)
+		return nil
+	})
+	if state := cb.State("Leaf"); state != BreakerOpen {
+		t.Errorf("Expected a slow call to trip the breaker, got %s", state)
+	}
+}
+
+func TestCircuitBreakerRecovers(t *testing.T) {
+	failure := errors.New("unavailable")
+	cb, now, transitions := testBreaker(BreakerPolicy{Trip: TripConsecutiveFailures, Threshold: 1, OpenTimeout: time.Second, HalfOpenProbes: 2}, nil, false)
+	calls := 0
+	call := func(err error) func() error {
+		return func() error {
+			calls++
+			return err
+		}
+	}
+	cb.Do(context.Background(), "Leaf", call(failure))
+	if err := cb.Do(context.Background(), "Leaf", call(nil)); !errors.Is(err, ErrBreakerOpen) || calls != 1 {
+		t.Errorf("Expected the open breaker to reject the call, got %v after %d calls", err, calls)
+	}
+	// A failed probe opens the breaker again
+	*now = now.Add(time.Second)
+	cb.Do(context.Background(), "Leaf", call(failure))
+	*now = now.Add(time.Second)
+	cb.Do(context.Background(), "Leaf", call(nil))
+	cb.Do(context.Background(), "Leaf", call(nil))
+	expected := []string{
+		"Leaf: closed -> open",
+		"Leaf: open -> half_open",
+		"Leaf: half_open -> open",
+		"Leaf: open -> half_open",
+		"Leaf: half_open -> closed",
+	}
+	if !reflect.DeepEqual(*transitions, expected) {
+		t.Errorf("Expected transitions %q, got %q", expected, *transitions)
+	}
+}
+
+func TestCircuitBreakerIgnoresCancelledCalls(t *testing.T) {
+	cb, _, _ := testBreaker(BreakerPolicy{Trip: TripConsecutiveFailures, Threshold: 1, OpenTimeout: time.Second, HalfOpenProbes: 1}, nil, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cb.Do(ctx, "Leaf", func() error { return ctx.Err() })
+	if state := cb.State("Leaf"); state != BreakerClosed {
+		t.Errorf("Expected a call cancelled by the caller not to trip the breaker, got %s", state)
+	}
+}
+
+func TestCircuitBreakerPerMethod(t *testing.T) {
+	failure := errors.New("unavailable")
+	policy := BreakerPolicy{Trip: TripConsecutiveFailures, Threshold: 1, OpenTimeout: time.Second, HalfOpenProbes: 1}
+	for _, perMethod := range []bool{false, true} {
+		cb, _, _ := testBreaker(policy, nil, perMethod)
+		cb.Do(context.Background(), "Leaf", func() error { return failure })
+		if expected := map[bool]string{false: BreakerOpen, true: BreakerClosed}[perMethod]; cb.State("Object") != expected {
+			t.Errorf("per_method=%v: expected Object to be %s, got %s", perMethod, expected, cb.State("Object"))
+		}
+	}
+}
+
+func TestParseCircuitBreaker(t *testing.T) {
+	cb, err := ParseCircuitBreaker("leafService", map[string]string{"interval": "10s", "threshold": "0.3", "per_method": "True", "methods": "{Leaf: {trip: consecutive_failures, open_timeout: 1s}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := BreakerPolicy{Trip: TripFailureRate, Threshold: 0.3, MinSamples: 1000, SlowCall: time.Second, Interval: 10 * time.Second, OpenTimeout: 10 * time.Second, HalfOpenProbes: 1}
+	if cb.Policy != expected || !cb.PerMethod {
+		t.Errorf("Expected policy %+v per method, got %+v", expected, cb.Policy)
+	}
+	expected.Trip, expected.Threshold, expected.OpenTimeout = TripConsecutiveFailures, 5, time.Second
+	if cb.Methods["Leaf"] != expected {
+		t.Errorf("Expected the policy %+v for Leaf, got %+v", expected, cb.Methods["Leaf"])
+	}
+
+	errs := map[string]map[string]string{
+		"trip: unknown strategy slow":                  {"trip": "slow"},
+		"threshold: 2 is not a ratio between 0 and 1":  {"threshold": "2"},
+		"threshold: 0 is less than 1":                  {"trip": "consecutive_failures", "threshold": "0"},
+		"threshold: 0.5 is not an integer":             {"trip": "consecutive_failures", "threshold": "0.5"},
+		"half_open_probes: 0 is less than 1":           {"half_open_probes": "0"},
+		"per_method: yes is not True or False":         {"per_method": "yes"},
+		"methods: Leaf: unknown option per_method":     {"methods": "{Leaf: {per_method: True}}"},
+		"methods: Leaf: open_timeout: 1 is not a dura": {"methods": "{Leaf: {open_timeout: 1}}"},
+	}
+	for expected, opts := range errs {
+		if _, err := ParseCircuitBreaker("leafService", opts); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%v: expected error %q, got %v", opts, expected, err)
+		}
+	}
+}
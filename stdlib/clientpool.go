@@ -1,13 +1,18 @@
 package stdlib
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/debug"
 )
 
 type ClientPool[T any] struct {
+	MaxWait time.Duration // Time PopContext waits for a free client, 0 for no bound
+
 	lock         sync.Mutex
 	wait_channel chan T
 	fn           func() T
@@ -28,7 +33,7 @@ func (this *ClientPool[T]) StartMetricsThread(pool_id string) {
 			select {
 			case <-ticker.C:
 				debug.ReportMetric(pool_id+":FreeClients", len(this.wait_channel))
-				debug.ReportMetric(pool_id+":CurrentWaiting", this.waiting)
+				debug.ReportMetric(pool_id+":CurrentWaiting", atomic.LoadInt64(&this.waiting))
 			}
 		}
 	}()
@@ -43,14 +48,44 @@ func (this *ClientPool[T]) Pop() T {
 		return client
 	}
 	this.lock.Unlock()
-	this.waiting += 1
+	atomic.AddInt64(&this.waiting, 1)
 	select {
 	case client := <-this.wait_channel:
-		this.waiting -= 1
+		atomic.AddInt64(&this.waiting, -1)
 		return client
 	}
 }
 
+// PopContext returns a client like Pop, but gives up once ctx is done or no client was free for MaxWait, in which
+// case it returns a ResourceExhaustedError
+func (this *ClientPool[T]) PopContext(ctx context.Context) (T, error) {
+	this.lock.Lock()
+	if this.curClients < this.maxClients {
+		defer this.lock.Unlock()
+		client := this.fn()
+		this.curClients += 1
+		return client, nil
+	}
+	this.lock.Unlock()
+	atomic.AddInt64(&this.waiting, 1)
+	defer atomic.AddInt64(&this.waiting, -1)
+	var timeout <-chan time.Time
+	if this.MaxWait > 0 {
+		timer := time.NewTimer(this.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var none T
+	select {
+	case client := <-this.wait_channel:
+		return client, nil
+	case <-timeout:
+		return none, &ResourceExhaustedError{Reason: fmt.Sprintf("no free client in the pool after %v", this.MaxWait)}
+	case <-ctx.Done():
+		return none, ctx.Err()
+	}
+}
+
 func (this *ClientPool[T]) Push(client T) {
 	this.wait_channel <- client
 }
@@ -0,0 +1,302 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alifarahbakhsh/forked-legacy-blueprint-compiler/stdlib/debug"
+)
+
+// Algorithms that adapt the limit of a ConcurrencyLimiter
+const (
+	LimitFixed    = "fixed"    // The limit doesn't change
+	LimitAIMD     = "aimd"     // Add one while calls succeed near the limit, multiply by backoff once a call is dropped
+	LimitGradient = "gradient" // Shrink the limit while the latency of the calls exceeds its long-term average
+)
+
+// Parameters of the gradient algorithm, as in Netflix's Gradient2Limit
+const (
+	gradientTolerance = 1.5 // Ratio of the latencies tolerated before the limit shrinks
+	gradientSmoothing = 0.2 // Weight of a new estimate of the limit
+	gradientWindow    = 600 // Calls averaged by the long-term latency
+)
+
+// ConcurrencyPolicy configures how many calls may be in flight at once and what happens to the others
+type ConcurrencyPolicy struct {
+	Limit    int64         // Calls in flight at once, the initial limit of the adaptive algorithms
+	MaxWait  time.Duration // Time a call waits for a free slot, 0 to reject the calls over the limit
+	MaxQueue int64         // Calls that may wait at once, 0 for no bound
+	Adaptive string
+	MinLimit int64
+	MaxLimit int64
+	Backoff  float64       // Factor of the limit once a call is dropped, aimd only
+	SlowCall time.Duration // Duration from which a call counts as dropped, aimd only
+}
+
+type waiter struct {
+	ready   chan bool
+	granted bool
+}
+
+// A limit and the calls it let pass
+type concurrencyLimit struct {
+	name   string
+	policy ConcurrencyPolicy
+
+	limit    float64
+	inflight int64
+	queue    []*waiter
+	longRtt  float64 // Long-term average latency in nanoseconds, gradient only
+}
+
+// Lets the waiting calls take the free slots
+func (l *concurrencyLimit) grant() {
+	for len(l.queue) > 0 && l.inflight < int64(l.limit) {
+		w := l.queue[0]
+		l.queue = l.queue[1:]
+		w.granted = true
+		l.inflight++
+		close(w.ready)
+	}
+}
+
+// Adapts the limit to a call that took rtt while inflight calls were in flight
+func (l *concurrencyLimit) adapt(rtt time.Duration, inflight int64, dropped bool) {
+	switch l.policy.Adaptive {
+	case LimitAIMD:
+		if dropped || rtt >= l.policy.SlowCall {
+			l.limit *= l.policy.Backoff
+		} else if float64(inflight)*2 >= l.limit {
+			l.limit++
+		}
+	case LimitGradient:
+		sample := float64(rtt)
+		if l.longRtt == 0 {
+			l.longRtt = sample
+		} else {
+			l.longRtt += (sample - l.longRtt) * 2 / (gradientWindow + 1)
+		}
+		// Calls far below the limit say nothing about it
+		if float64(inflight) < l.limit/2 {
+			return
+		}
+		gradient := math.Max(0.5, math.Min(1, gradientTolerance*l.longRtt/math.Max(sample, 1)))
+		estimate := l.limit*gradient + math.Sqrt(l.limit)
+		l.limit = l.limit*(1-gradientSmoothing) + estimate*gradientSmoothing
+	default:
+		return
+	}
+	l.limit = math.Max(float64(l.policy.MinLimit), math.Min(float64(l.policy.MaxLimit), l.limit))
+}
+
+// ConcurrencyLimiter caps the calls of a client, or to a service, that are in flight at once. Calls over the limit
+// wait up to MaxWait for a free slot and fail with a ResourceExhaustedError otherwise. The methods share one limit,
+// unless PerMethod is set or the method has a policy of its own in Methods. The adaptive algorithms report every
+// change of a limit as the metric <name>:ConcurrencyLimit, or <name>.<method>:ConcurrencyLimit for the limit of a
+// method.
+type ConcurrencyLimiter struct {
+	Name      string
+	Policy    ConcurrencyPolicy
+	Methods   map[string]ConcurrencyPolicy // Policies of the methods that don't use Policy
+	PerMethod bool                         // Whether every method has a limit of its own
+
+	lock   sync.Mutex
+	limits map[string]*concurrencyLimit
+	now    func() time.Time
+}
+
+func NewConcurrencyLimiter(name string, policy ConcurrencyPolicy, methods map[string]ConcurrencyPolicy, perMethod bool) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{Name: name, Policy: policy, Methods: methods, PerMethod: perMethod, limits: make(map[string]*concurrencyLimit), now: time.Now}
+}
+
+// Returns the limit of the calls of method
+func (cl *ConcurrencyLimiter) limitOf(method string) *concurrencyLimit {
+	policy, ok := cl.Methods[method]
+	if !ok {
+		policy = cl.Policy
+		if !cl.PerMethod {
+			method = ""
+		}
+	}
+	l, ok := cl.limits[method]
+	if !ok {
+		name := cl.Name
+		if method != "" {
+			name += "." + method
+		}
+		l = &concurrencyLimit{name: name, policy: policy, limit: float64(policy.Limit)}
+		cl.limits[method] = l
+	}
+	return l
+}
+
+// Limit returns the current limit of the calls of method
+func (cl *ConcurrencyLimiter) Limit(method string) int64 {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+	return int64(cl.limitOf(method).limit)
+}
+
+// Takes a slot for a call of method, and returns the calls in flight with it
+func (cl *ConcurrencyLimiter) acquire(ctx context.Context, method string, l *concurrencyLimit) (int64, error) {
+	cl.lock.Lock()
+	if l.inflight < int64(l.limit) && len(l.queue) == 0 {
+		l.inflight++
+		defer cl.lock.Unlock()
+		return l.inflight, nil
+	}
+	rejected := &ResourceExhaustedError{Reason: fmt.Sprintf("%s: concurrency limit of %d calls reached", method, int64(l.limit))}
+	if l.policy.MaxWait == 0 || (l.policy.MaxQueue > 0 && int64(len(l.queue)) >= l.policy.MaxQueue) {
+		cl.lock.Unlock()
+		return 0, rejected
+	}
+	w := &waiter{ready: make(chan bool)}
+	l.queue = append(l.queue, w)
+	cl.lock.Unlock()
+
+	timer := time.NewTimer(l.policy.MaxWait)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-w.ready:
+	case <-timer.C:
+		err = rejected
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+	// The slot may have been granted while the wait ended
+	if w.granted {
+		return l.inflight, nil
+	}
+	for idx, queued := range l.queue {
+		if queued == w {
+			l.queue = append(l.queue[:idx], l.queue[idx+1:]...)
+			break
+		}
+	}
+	return 0, err
+}
+
+// Do calls call once a slot of the limit of method is free, and adapts the limit to the outcome of the call. Calls
+// that a service rejected because it is overloaded, or that timed out, count as dropped.
+func (cl *ConcurrencyLimiter) Do(ctx context.Context, method string, call func() error) error {
+	cl.lock.Lock()
+	l := cl.limitOf(method)
+	cl.lock.Unlock()
+	inflight, err := cl.acquire(ctx, method, l)
+	if err != nil {
+		return err
+	}
+	start := cl.now()
+	err = call()
+	rtt := cl.now().Sub(start)
+
+	cl.lock.Lock()
+	l.inflight--
+	from := int64(l.limit)
+	dropped := errors.Is(err, ErrResourceExhausted) || errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded)
+	l.adapt(rtt, inflight, dropped)
+	to := int64(l.limit)
+	l.grant()
+	cl.lock.Unlock()
+	if to != from {
+		debug.ReportMetric(l.name+":ConcurrencyLimit", to)
+	}
+	return err
+}
+
+// ParseConcurrencyLimiter creates the ConcurrencyLimiter name from the options of the ConcurrencyLimiter modifier in
+// the wiring file: limit, max_wait, max_queue, adaptive (the algorithm), min_limit, max_limit, backoff, slow_call,
+// per_method and methods, a dictionary of the options of single methods.
+func ParseConcurrencyLimiter(name string, opts map[string]string) (*ConcurrencyLimiter, error) {
+	o := make(options)
+	for key, value := range opts {
+		o[key] = value
+	}
+	policy, err := parseConcurrencyPolicy(o)
+	if err != nil {
+		return nil, err
+	}
+	perMethod, err := o.bool("per_method", false)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := o.methods("methods")
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[string]ConcurrencyPolicy)
+	for _, method := range sortedKeys(methods) {
+		methodOpts := methods[method]
+		if err := methodOpts.check("limit", "max_wait", "max_queue", "adaptive", "min_limit", "max_limit", "backoff", "slow_call"); err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+		merged := o.overlay(methodOpts)
+		if _, ok := methodOpts["limit"]; ok {
+			// The bounds of another limit don't carry over
+			if _, ok := methodOpts["max_limit"]; !ok {
+				delete(merged, "max_limit")
+			}
+		}
+		if policies[method], err = parseConcurrencyPolicy(merged); err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+	}
+	return NewConcurrencyLimiter(name, policy, policies, perMethod), nil
+}
+
+func parseConcurrencyPolicy(o options) (ConcurrencyPolicy, error) {
+	var policy ConcurrencyPolicy
+	var err error
+	if policy.Limit, err = o.int("limit", 0); err != nil {
+		return policy, err
+	}
+	if policy.Limit < 1 {
+		return policy, fmt.Errorf("limit: a limit of at least 1 call is required, e.g. 10")
+	}
+	if policy.MaxWait, err = o.duration("max_wait", 0); err != nil {
+		return policy, err
+	}
+	if policy.MaxQueue, err = o.int("max_queue", 0); err != nil {
+		return policy, err
+	}
+	if policy.MaxQueue < 0 {
+		return policy, fmt.Errorf("max_queue: %d is less than 0", policy.MaxQueue)
+	}
+	if policy.Adaptive, err = o.string("adaptive", LimitFixed); err != nil {
+		return policy, err
+	}
+	switch policy.Adaptive {
+	case LimitFixed, LimitAIMD, LimitGradient:
+	default:
+		return policy, fmt.Errorf("adaptive: unknown algorithm %s, expected one of fixed, aimd, gradient", policy.Adaptive)
+	}
+	if policy.MinLimit, err = o.int("min_limit", 1); err != nil {
+		return policy, err
+	}
+	if policy.MaxLimit, err = o.int("max_limit", 10*policy.Limit); err != nil {
+		return policy, err
+	}
+	if policy.MinLimit < 1 || policy.MinLimit > policy.Limit || policy.MaxLimit < policy.Limit {
+		return policy, fmt.Errorf("limit: %d is not between min_limit %d and max_limit %d", policy.Limit, policy.MinLimit, policy.MaxLimit)
+	}
+	if policy.Backoff, err = o.float("backoff", 0.9); err != nil {
+		return policy, err
+	}
+	if policy.Backoff <= 0 || policy.Backoff >= 1 || math.IsNaN(policy.Backoff) {
+		return policy, fmt.Errorf("backoff: %v is not a ratio between 0 and 1", policy.Backoff)
+	}
+	if policy.SlowCall, err = o.duration("slow_call", time.Second); err != nil {
+		return policy, err
+	}
+	if policy.SlowCall == 0 {
+		return policy, fmt.Errorf("slow_call: a duration greater than 0 is required")
+	}
+	return policy, nil
+}
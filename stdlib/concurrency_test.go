@@ -0,0 +1,253 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Returns a limiter whose calls take the time the test sets with the returned function
+func testConcurrencyLimiter(policy ConcurrencyPolicy, methods map[string]ConcurrencyPolicy, perMethod bool) (*ConcurrencyLimiter, func(time.Duration)) {
+	var lock sync.Mutex
+	now := time.Unix(0, 0)
+	rtt := time.Millisecond
+	cl := NewConcurrencyLimiter("leafService", policy, methods, perMethod)
+	cl.now = func() time.Time {
+		lock.Lock()
+		defer lock.Unlock()
+		now = now.Add(rtt)
+		return now
+	}
+	return cl, func(d time.Duration) {
+		lock.Lock()
+		defer lock.Unlock()
+		rtt = d
+	}
+}
+
+// Starts a call of method that holds its slot until release is closed, and waits until the call has a slot
+func holdSlot(t *testing.T, cl *ConcurrencyLimiter, method string, release chan bool) chan error {
+	started := make(chan bool)
+	done := make(chan error, 1)
+	go func() {
+		done <- cl.Do(context.Background(), method, func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	select {
+	case <-started:
+	case err := <-done:
+		t.Fatalf("Expected the call of %s to get a slot, got %v", method, err)
+	}
+	return done
+}
+
+// Returns the calls in the queue of the limit of method
+func queued(cl *ConcurrencyLimiter, method string) int {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+	return len(cl.limitOf(method).queue)
+}
+
+func TestConcurrencyLimiterReject(t *testing.T) {
+	cl, _ := testConcurrencyLimiter(ConcurrencyPolicy{Limit: 1, MinLimit: 1, MaxLimit: 1}, nil, false)
+	release := make(chan bool)
+	done := holdSlot(t, cl, "Leaf", release)
+	err := cl.Do(context.Background(), "Object", func() error { return nil })
+	if !errors.Is(err, ErrResourceExhausted) || !strings.Contains(err.Error(), "concurrency limit of 1 calls reached") {
+		t.Errorf("Expected the methods to share the limit and the call to be rejected, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.Do(context.Background(), "Object", func() error { return nil }); err != nil {
+		t.Errorf("Expected the slot to be free again, got %v", err)
+	}
+}
+
+func TestConcurrencyLimiterQueue(t *testing.T) {
+	cl, _ := testConcurrencyLimiter(ConcurrencyPolicy{Limit: 1, MaxWait: time.Minute, MaxQueue: 2, MinLimit: 1, MaxLimit: 1}, nil, false)
+	release := make(chan bool)
+	done := holdSlot(t, cl, "Leaf", release)
+
+	order := make(chan int, 2)
+	waiting := make([]chan error, 2)
+	for i := range waiting {
+		i := i
+		waiting[i] = make(chan error, 1)
+		go func() {
+			waiting[i] <- cl.Do(context.Background(), "Leaf", func() error {
+				order <- i
+				return nil
+			})
+		}()
+		for queued(cl, "Leaf") != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if err := cl.Do(context.Background(), "Leaf", func() error { return nil }); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("Expected the call to be rejected once the queue is full, got %v", err)
+	}
+	close(release)
+	for i := range waiting {
+		if err := <-waiting[i]; err != nil {
+			t.Fatal(err)
+		}
+		if first := <-order; first != i {
+			t.Errorf("Expected the waiting calls to pass in order, got call %d as call %d", first, i)
+		}
+	}
+	<-done
+}
+
+func TestConcurrencyLimiterMaxWait(t *testing.T) {
+	cl, _ := testConcurrencyLimiter(ConcurrencyPolicy{Limit: 1, MaxWait: 10 * time.Millisecond, MinLimit: 1, MaxLimit: 1}, nil, false)
+	release := make(chan bool)
+	defer close(release)
+	holdSlot(t, cl, "Leaf", release)
+	if err := cl.Do(context.Background(), "Leaf", func() error { return nil }); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("Expected the call to be rejected after max_wait, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cl.Do(ctx, "Leaf", func() error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the call to give up once its context is done, got %v", err)
+	}
+	if n := queued(cl, "Leaf"); n != 0 {
+		t.Errorf("Expected the calls that gave up to leave the queue, got %d waiting", n)
+	}
+}
+
+func TestConcurrencyLimiterAIMD(t *testing.T) {
+	policy := ConcurrencyPolicy{Limit: 2, Adaptive: LimitAIMD, MinLimit: 1, MaxLimit: 3, Backoff: 0.5, SlowCall: time.Second}
+	cl, setRtt := testConcurrencyLimiter(policy, nil, false)
+	for i := 0; i < 3; i++ {
+		if err := cl.Do(context.Background(), "Leaf", func() error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if limit := cl.Limit("Leaf"); limit != 3 {
+		t.Errorf("Expected the limit to grow up to max_limit 3, got %d", limit)
+	}
+	cl.Do(context.Background(), "Leaf", func() error { return &ResourceExhaustedError{Reason: "overloaded"} })
+	if limit := cl.Limit("Leaf"); limit != 1 {
+		t.Errorf("Expected a dropped call to halve the limit, got %d", limit)
+	}
+	cl.Do(context.Background(), "Leaf", func() error { return errors.New("not found") })
+	if limit := cl.Limit("Leaf"); limit != 2 {
+		t.Errorf("Expected other errors to count as successes, got the limit %d", limit)
+	}
+	setRtt(2 * time.Second)
+	cl.Do(context.Background(), "Leaf", func() error { return nil })
+	if limit := cl.Limit("Leaf"); limit != 1 {
+		t.Errorf("Expected a slow call to halve the limit, got %d", limit)
+	}
+}
+
+func TestConcurrencyLimiterGradient(t *testing.T) {
+	policy := ConcurrencyPolicy{Limit: 10, Adaptive: LimitGradient, MinLimit: 1, MaxLimit: 100}
+	cl, setRtt := testConcurrencyLimiter(policy, nil, false)
+	// Calls far below the limit leave it alone
+	cl.Do(context.Background(), "Leaf", func() error { return nil })
+	if limit := cl.Limit("Leaf"); limit != 10 {
+		t.Errorf("Expected a single call to leave the limit alone, got %d", limit)
+	}
+	release := make(chan bool)
+	var held []chan error
+	for i := 0; i < 8; i++ {
+		held = append(held, holdSlot(t, cl, "Leaf", release))
+	}
+	for i := 0; i < 5; i++ {
+		cl.Do(context.Background(), "Leaf", func() error { return nil })
+	}
+	grown := cl.Limit("Leaf")
+	if grown <= 10 {
+		t.Errorf("Expected the limit to grow while the latency is steady, got %d", grown)
+	}
+	setRtt(time.Second)
+	for i := 0; i < 10; i++ {
+		cl.Do(context.Background(), "Leaf", func() error { return nil })
+	}
+	if limit := cl.Limit("Leaf"); limit >= grown {
+		t.Errorf("Expected the limit to shrink once the latency spikes, got %d after %d", limit, grown)
+	}
+	close(release)
+	for _, done := range held {
+		<-done
+	}
+}
+
+func TestConcurrencyLimiterKeys(t *testing.T) {
+	methods := map[string]ConcurrencyPolicy{"Object": {Limit: 5, MinLimit: 1, MaxLimit: 5}}
+	cl, _ := testConcurrencyLimiter(ConcurrencyPolicy{Limit: 1, MinLimit: 1, MaxLimit: 1}, methods, true)
+	release := make(chan bool)
+	defer close(release)
+	holdSlot(t, cl, "Leaf", release)
+	holdSlot(t, cl, "Other", release)
+	holdSlot(t, cl, "Object", release)
+	if cl.limitOf("Leaf") == cl.limitOf("Other") || cl.Limit("Object") != 5 {
+		t.Errorf("Expected every method to have a limit of its own")
+	}
+	if err := cl.Do(context.Background(), "Leaf", func() error { return nil }); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("Expected the limit of Leaf to be reached, got %v", err)
+	}
+}
+
+func TestParseConcurrencyLimiter(t *testing.T) {
+	cl, err := ParseConcurrencyLimiter("leafService", map[string]string{"limit": "20", "adaptive": "aimd", "max_wait": "50ms", "methods": "{Leaf: {max_queue: 10}, Object: {limit: 5}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ConcurrencyPolicy{Limit: 20, MaxWait: 50 * time.Millisecond, Adaptive: LimitAIMD, MinLimit: 1, MaxLimit: 200, Backoff: 0.9, SlowCall: time.Second}
+	if cl.Policy != expected || cl.PerMethod {
+		t.Errorf("Expected the policy %+v, got %+v", expected, cl.Policy)
+	}
+	leaf := expected
+	leaf.MaxQueue = 10
+	object := expected
+	object.Limit, object.MaxLimit = 5, 50
+	if cl.Methods["Leaf"] != leaf || cl.Methods["Object"] != object {
+		t.Errorf("Expected the policies of the methods to overlay the policy, got %+v", cl.Methods)
+	}
+
+	errs := map[string]map[string]string{
+		"limit: a limit of at least 1 call is required":      {},
+		"adaptive: unknown algorithm vegas":                  {"limit": "10", "adaptive": "vegas"},
+		"limit: 10 is not between min_limit 1 and max_limit": {"limit": "10", "max_limit": "5"},
+		"backoff: 1.5 is not a ratio between 0 and 1":        {"limit": "10", "backoff": "1.5"},
+		"methods: Leaf: unknown option per_method":           {"limit": "10", "methods": "{Leaf: {per_method: True}}"},
+	}
+	for expected, opts := range errs {
+		if _, err := ParseConcurrencyLimiter("", opts); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%v: expected error %q, got %v", opts, expected, err)
+		}
+	}
+}
+
+func TestClientPoolPopContext(t *testing.T) {
+	pool := NewClientPool[int](1, func() int { return 1 })
+	pool.MaxWait = 10 * time.Millisecond
+	client, err := pool.PopContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.PopContext(context.Background()); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("Expected no free client after max_wait, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pool.MaxWait = 0
+	if _, err := pool.PopContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected PopContext to give up once its context is done, got %v", err)
+	}
+	pool.Push(client)
+	if _, err := pool.PopContext(context.Background()); err != nil {
+		t.Errorf("Expected the pushed client back, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrResourceExhausted is matched by the errors of the calls that a service rejected because it is overloaded
+var ErrResourceExhausted = errors.New("resource exhausted")
+
+// ResourceExhaustedError is the error of a call that a service rejected because it is overloaded. The frameworks
+// send it as their native status, e.g. HTTP 429 or the gRPC code ResourceExhausted, and clients get it back.
+type ResourceExhaustedError struct {
+	Reason     string
+	RetryAfter time.Duration // Time after which the call may pass, 0 if unknown
+}
+
+func (e *ResourceExhaustedError) Error() string {
+	return e.Reason
+}
+
+func (e *ResourceExhaustedError) Is(target error) bool { return target == ErrResourceExhausted }
+
+// WriteHTTPError writes err as the response of a web handler, with the status 429 and a Retry-After header for a
+// ResourceExhaustedError and 500 otherwise
+func WriteHTTPError(w http.ResponseWriter, err error) {
+	var exhausted *ResourceExhaustedError
+	if !errors.As(err, &exhausted) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if exhausted.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(exhausted.RetryAfter.Seconds())), 10))
+	}
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+}
+
+// ReadHTTPError returns the error of a response that WriteHTTPError wrote
+func ReadHTTPError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	message := strings.TrimSpace(string(body))
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return errors.New(message)
+	}
+	exhausted := &ResourceExhaustedError{Reason: message}
+	if seconds, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64); err == nil {
+		exhausted.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return exhausted
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx that carries the key of the caller of a service
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// WithCallerAddr returns a copy of ctx whose caller is the host of addr. The servers of the frameworks set it to the
+// address of the client, whose port changes between connections.
+func WithCallerAddr(ctx context.Context, addr string) context.Context {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return WithCaller(ctx, addr)
+}
+
+// Caller returns the key of the caller that ctx carries, or an empty string
+func Caller(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
@@ -0,0 +1,226 @@
+package stdlib
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The generated code configures the types of this package with the values of the keyword arguments of the
+// modifiers in the wiring file. Dictionaries reach the generated code without quotes, e.g. the argument
+// methods={"Leaf": {"max_retries": 3}} is passed as {Leaf: {max_retries: 3}}.
+
+// Parses a dictionary of the wiring file. Values are either strings or dictionaries.
+func parseDict(s string) (map[string]interface{}, error) {
+	p := &dictParser{s: s}
+	dict, err := p.parseDict()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q after the dictionary %s", p.s[p.pos:], s)
+	}
+	return dict, nil
+}
+
+type dictParser struct {
+	s   string
+	pos int
+}
+
+func (p *dictParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' || p.s[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func (p *dictParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != c {
+		return fmt.Errorf("expected %q at offset %d of %s", c, p.pos, p.s)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *dictParser) parseDict() (map[string]interface{}, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	dict := make(map[string]interface{})
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return dict, nil
+	}
+	for {
+		key := strings.TrimSpace(p.scan(":"))
+		if key == "" {
+			return nil, fmt.Errorf("missing key at offset %d of %s", p.pos, p.s)
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos < len(p.s) && p.s[p.pos] == '{' {
+			value, err := p.parseDict()
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = value
+		} else {
+			dict[key] = strings.TrimSpace(p.scan(",}"))
+		}
+		p.skipSpace()
+		if p.pos < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if err := p.expect('}'); err != nil {
+			return nil, err
+		}
+		return dict, nil
+	}
+}
+
+// Returns the text up to the next of the stop characters outside of brackets
+func (p *dictParser) scan(stop string) string {
+	start, depth := p.pos, 0
+	for ; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		if depth == 0 && strings.IndexByte(stop, c) >= 0 {
+			break
+		}
+		switch c {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		}
+	}
+	return p.s[start:p.pos]
+}
+
+// Options of a modifier by keyword. Missing and empty options take the default value.
+type options map[string]interface{}
+
+func (o options) string(name string, def string) (string, error) {
+	value, ok := o[name]
+	if !ok || value == "" {
+		return def, nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a value, not a dictionary", name)
+	}
+	return str, nil
+}
+
+func (o options) int(name string, def int64) (int64, error) {
+	str, err := o.string(name, "")
+	if err != nil || str == "" {
+		return def, err
+	}
+	value, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s is not an integer", name, str)
+	}
+	return value, nil
+}
+
+func (o options) float(name string, def float64) (float64, error) {
+	str, err := o.string(name, "")
+	if err != nil || str == "" {
+		return def, err
+	}
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s is not a number", name, str)
+	}
+	return value, nil
+}
+
+// Booleans of the wiring file are written True and False
+func (o options) bool(name string, def bool) (bool, error) {
+	str, err := o.string(name, "")
+	if err != nil || str == "" {
+		return def, err
+	}
+	value, err := strconv.ParseBool(str)
+	if err != nil {
+		return false, fmt.Errorf("%s: %s is not True or False", name, str)
+	}
+	return value, nil
+}
+
+func (o options) duration(name string, def time.Duration) (time.Duration, error) {
+	str, err := o.string(name, "")
+	if err != nil || str == "" {
+		return def, err
+	}
+	value, err := time.ParseDuration(str)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s: %s is not a duration, e.g. 100ms", name, str)
+	}
+	return value, nil
+}
+
+// Returns the options of each method in the dictionary option name, e.g. methods={Leaf: {max_retries: 3}}
+func (o options) methods(name string) (map[string]options, error) {
+	methods := make(map[string]options)
+	str, err := o.string(name, "")
+	if err != nil || str == "" {
+		return methods, err
+	}
+	dict, err := parseDict(str)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	for method, value := range dict {
+		methodOpts, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: the options of %s must be a dictionary", name, method)
+		}
+		methods[method] = methodOpts
+	}
+	return methods, nil
+}
+
+// Returns the options of method overlaid on o
+func (o options) overlay(method options) options {
+	merged := make(options)
+	for key, value := range o {
+		merged[key] = value
+	}
+	for key, value := range method {
+		merged[key] = value
+	}
+	return merged
+}
+
+// Returns the keys of m in increasing order, so that options are checked and reported in the same order every time
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Returns an error if o has an option that isn't known
+func (o options) check(known ...string) error {
+	for _, key := range sortedKeys(o) {
+		found := false
+		for _, name := range known {
+			found = found || key == name
+		}
+		if !found {
+			return fmt.Errorf("unknown option %s, expected one of %s", key, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package stdlib
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit is the rate of a token bucket: calls pass at Rate calls per second on average, and up to Burst calls
+// pass at once
+type RateLimit struct {
+	Rate  float64
+	Burst int64
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Takes a token from the bucket, or returns the time until the next token
+func (b *tokenBucket) take(limit RateLimit, now time.Time) (bool, time.Duration) {
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// Returns the time it takes an empty bucket to fill up. A bucket that was left alone as long is full, like a new one.
+func (limit RateLimit) refill() time.Duration {
+	return time.Duration(float64(limit.Burst) / limit.Rate * float64(time.Second))
+}
+
+type bucketKey struct {
+	method string
+	caller string
+}
+
+// RateLimiter rejects the calls to a service that exceed its rate limit with a ResourceExhaustedError. The methods
+// share one token bucket, unless the method has a limit of its own in Methods. With PerCaller every caller, as
+// returned by Caller, has buckets of its own. The buckets of callers that have been idle until their buckets filled
+// up are dropped, at most once per EvictInterval.
+type RateLimiter struct {
+	Limit     RateLimit
+	Methods   map[string]RateLimit // Limits of the methods that don't use Limit
+	PerCaller bool
+
+	lock    sync.Mutex
+	buckets map[bucketKey]*tokenBucket
+	evicted time.Time
+	now     func() time.Time
+}
+
+// EvictInterval is the time between two scans of the buckets of a RateLimiter for idle callers
+const EvictInterval = time.Minute
+
+func NewRateLimiter(limit RateLimit, methods map[string]RateLimit, perCaller bool) *RateLimiter {
+	return &RateLimiter{Limit: limit, Methods: methods, PerCaller: perCaller, buckets: make(map[bucketKey]*tokenBucket), now: time.Now}
+}
+
+// Allow takes a token for a call of method made with ctx, and returns a ResourceExhaustedError if there is none
+func (rl *RateLimiter) Allow(ctx context.Context, method string) error {
+	key := bucketKey{method: method}
+	limit, ok := rl.Methods[method]
+	if !ok {
+		limit = rl.Limit
+		key.method = ""
+	}
+	if rl.PerCaller {
+		key.caller = Caller(ctx)
+	}
+	rl.lock.Lock()
+	now := rl.now()
+	if rl.PerCaller && now.Sub(rl.evicted) >= EvictInterval {
+		rl.evict(now)
+	}
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit.Burst), last: now}
+		rl.buckets[key] = b
+	}
+	allowed, wait := b.take(limit, now)
+	rl.lock.Unlock()
+	if allowed {
+		return nil
+	}
+	return &ResourceExhaustedError{Reason: fmt.Sprintf("%s: rate limit of %v calls per second exceeded", method, limit.Rate), RetryAfter: wait}
+}
+
+// Drops the buckets that are full again, callers get a new full bucket with their next call
+func (rl *RateLimiter) evict(now time.Time) {
+	for key, b := range rl.buckets {
+		limit, ok := rl.Methods[key.method]
+		if !ok {
+			limit = rl.Limit
+		}
+		if now.Sub(b.last) >= limit.refill() {
+			delete(rl.buckets, key)
+		}
+	}
+	rl.evicted = now
+}
+
+// ParseRateLimiter creates a RateLimiter from the options of the RateLimiter modifier in the wiring file: rate (calls
+// per second), burst, per_caller and methods, a dictionary of the options of single methods.
+func ParseRateLimiter(opts map[string]string) (*RateLimiter, error) {
+	o := make(options)
+	for key, value := range opts {
+		o[key] = value
+	}
+	limit, err := parseRateLimit(o)
+	if err != nil {
+		return nil, err
+	}
+	perCaller, err := o.bool("per_caller", false)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := o.methods("methods")
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[string]RateLimit)
+	for _, method := range sortedKeys(methods) {
+		methodOpts := methods[method]
+		if err := methodOpts.check("rate", "burst"); err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+		merged := o.overlay(methodOpts)
+		if _, ok := methodOpts["rate"]; ok {
+			// The burst of another rate doesn't carry over
+			if _, ok := methodOpts["burst"]; !ok {
+				delete(merged, "burst")
+			}
+		}
+		if limits[method], err = parseRateLimit(merged); err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+	}
+	return NewRateLimiter(limit, limits, perCaller), nil
+}
+
+func parseRateLimit(o options) (RateLimit, error) {
+	var limit RateLimit
+	var err error
+	if limit.Rate, err = o.float("rate", 0); err != nil {
+		return limit, err
+	}
+	if limit.Rate <= 0 || math.IsInf(limit.Rate, 0) || math.IsNaN(limit.Rate) {
+		return limit, fmt.Errorf("rate: a rate greater than 0 calls per second is required, e.g. 100")
+	}
+	// By default a second worth of calls may pass at once
+	if limit.Burst, err = o.int("burst", int64(math.Ceil(limit.Rate))); err != nil {
+		return limit, err
+	}
+	if limit.Burst < 1 {
+		return limit, fmt.Errorf("burst: %d is less than 1", limit.Burst)
+	}
+	return limit, nil
+}
@@ -0,0 +1,138 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns a limiter whose clock only moves when the test advances it
+func testRateLimiter(limit RateLimit, methods map[string]RateLimit, perCaller bool) (*RateLimiter, *time.Time) {
+	now := time.Unix(0, 0)
+	rl := NewRateLimiter(limit, methods, perCaller)
+	rl.now = func() time.Time { return now }
+	return rl, &now
+}
+
+// Returns how many of calls calls the limiter lets pass
+func allowed(rl *RateLimiter, ctx context.Context, method string, calls int) int {
+	passed := 0
+	for i := 0; i < calls; i++ {
+		if rl.Allow(ctx, method) == nil {
+			passed++
+		}
+	}
+	return passed
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	rl, now := testRateLimiter(RateLimit{Rate: 10, Burst: 5}, nil, false)
+	if passed := allowed(rl, context.Background(), "Leaf", 10); passed != 5 {
+		t.Errorf("Expected a burst of 5 calls to pass, got %d", passed)
+	}
+	err := rl.Allow(context.Background(), "Object")
+	var exhausted *ResourceExhaustedError
+	if !errors.Is(err, ErrResourceExhausted) || !errors.As(err, &exhausted) || exhausted.RetryAfter != 100*time.Millisecond {
+		t.Errorf("Expected the methods to share the bucket and to retry after 100ms, got %v", err)
+	}
+	*now = now.Add(time.Second)
+	if passed := allowed(rl, context.Background(), "Leaf", 10); passed != 5 {
+		t.Errorf("Expected the bucket to refill up to the burst, got %d calls", passed)
+	}
+}
+
+func TestRateLimiterKeys(t *testing.T) {
+	rl, _ := testRateLimiter(RateLimit{Rate: 1, Burst: 1}, map[string]RateLimit{"Object": {Rate: 2, Burst: 2}}, true)
+	frontend := WithCallerAddr(context.Background(), "10.0.0.1:40000")
+	if Caller(frontend) != "10.0.0.1" {
+		t.Errorf("Expected the caller 10.0.0.1, got %s", Caller(frontend))
+	}
+	tests := []struct {
+		ctx      context.Context
+		method   string
+		expected int
+	}{
+		{frontend, "Leaf", 1},
+		{frontend, "Object", 2},
+		{WithCallerAddr(context.Background(), "10.0.0.1:40001"), "Leaf", 0},
+		{WithCaller(context.Background(), "10.0.0.2"), "Leaf", 1},
+	}
+	for _, test := range tests {
+		if passed := allowed(rl, test.ctx, test.method, 3); passed != test.expected {
+			t.Errorf("%s of %s: expected %d calls to pass, got %d", test.method, Caller(test.ctx), test.expected, passed)
+		}
+	}
+}
+
+func TestRateLimiterEvictsIdleCallers(t *testing.T) {
+	rl, now := testRateLimiter(RateLimit{Rate: 1, Burst: 2}, map[string]RateLimit{"Object": {Rate: 0.01, Burst: 1}}, true)
+	callers := make([]context.Context, 3)
+	for i := range callers {
+		callers[i] = WithCaller(context.Background(), fmt.Sprintf("10.0.0.%d", i))
+		rl.Allow(callers[i], "Leaf")
+	}
+	rl.Allow(callers[0], "Object")
+	*now = now.Add(30 * time.Second)
+	rl.Allow(callers[1], "Leaf")
+	if len(rl.buckets) != 4 {
+		t.Errorf("Expected the buckets to stay until the next scan, got %d buckets", len(rl.buckets))
+	}
+	*now = now.Add(EvictInterval)
+	rl.Allow(callers[2], "Leaf")
+	if len(rl.buckets) != 2 {
+		t.Errorf("Expected the full buckets to be dropped, got %d buckets", len(rl.buckets))
+	}
+	if passed := allowed(rl, callers[0], "Object", 1); passed != 0 {
+		t.Errorf("Expected the bucket of Object to be kept until it is full, got %d calls", passed)
+	}
+}
+
+func TestHTTPErrorRoundTrip(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteHTTPError(w, &ResourceExhaustedError{Reason: "Leaf: rate limit exceeded", RetryAfter: 1500 * time.Millisecond})
+	resp := w.Result()
+	if resp.StatusCode != 429 || resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("Expected 429 with Retry-After 2, got %d with %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	err := ReadHTTPError(resp)
+	var exhausted *ResourceExhaustedError
+	if !errors.As(err, &exhausted) || exhausted.Reason != "Leaf: rate limit exceeded" || exhausted.RetryAfter != 2*time.Second {
+		t.Errorf("Expected the client to get the error back, got %#v", err)
+	}
+
+	w = httptest.NewRecorder()
+	WriteHTTPError(w, errors.New("unavailable"))
+	resp = w.Result()
+	if err := ReadHTTPError(resp); resp.StatusCode != 500 || errors.Is(err, ErrResourceExhausted) || err.Error() != "unavailable" {
+		t.Errorf("Expected 500 with the message unavailable, got %d with %v", resp.StatusCode, err)
+	}
+}
+
+func TestParseRateLimiter(t *testing.T) {
+	rl, err := ParseRateLimiter(map[string]string{"rate": "2.5", "per_caller": "True", "methods": "{Leaf: {burst: 10}, Object: {rate: 100}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rl.Limit != (RateLimit{Rate: 2.5, Burst: 3}) || !rl.PerCaller {
+		t.Errorf("Expected the limit 2.5/s with a burst of 3 per caller, got %+v", rl.Limit)
+	}
+	if rl.Methods["Leaf"] != (RateLimit{Rate: 2.5, Burst: 10}) || rl.Methods["Object"] != (RateLimit{Rate: 100, Burst: 100}) {
+		t.Errorf("Expected the limits of the methods to overlay the limit, got %+v", rl.Methods)
+	}
+
+	errs := map[string]map[string]string{
+		"rate: a rate greater than 0 calls per second is required": {},
+		"rate: fast is not a number":                               {"rate": "fast"},
+		"burst: 0 is less than 1":                                  {"rate": "1", "burst": "0"},
+		"methods: Leaf: unknown option per_caller":                 {"rate": "1", "methods": "{Leaf: {per_caller: True}}"},
+	}
+	for expected, opts := range errs {
+		if _, err := ParseRateLimiter(opts); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%v: expected error %q, got %v", opts, expected, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff policies of a Retrier
+const (
+	BackoffNone        = "none"        // Retry immediately
+	BackoffConstant    = "constant"    // Wait base_delay between attempts
+	BackoffExponential = "exponential" // Double the delay after every attempt, starting at base_delay
+	BackoffJitter      = "jitter"      // Decorrelated jitter: a random delay between base_delay and three times the previous delay
+)
+
+// RetryBudgetBurst is the number of retries that a RetryBudget allows before any call has been made, and the most
+// that it saves up while calls succeed
+const RetryBudgetBurst = 10.0
+
+// RetryPolicy configures the retries of the calls of a method
+type RetryPolicy struct {
+	MaxAttempts int64 // Attempts of a call, including the first one
+	Backoff     string
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration // Upper bound of the delay between two attempts
+}
+
+// Returns the delay before the attempt after attempt, which was delayed by prev
+func (p RetryPolicy) delay(attempt int64, prev time.Duration, rnd func(int64) int64) time.Duration {
+	var delay time.Duration
+	switch p.Backoff {
+	case BackoffConstant:
+		delay = p.BaseDelay
+	case BackoffExponential:
+		delay = p.BaseDelay
+		for i := int64(1); i < attempt && delay < p.MaxDelay; i++ {
+			delay *= 2
+		}
+	case BackoffJitter:
+		upper := prev * 3
+		if upper <= p.BaseDelay {
+			upper = p.BaseDelay + 1
+		}
+		delay = p.BaseDelay + time.Duration(rnd(int64(upper-p.BaseDelay)))
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err so that calls failing with it aren't retried
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// Retryable reports whether a call made with ctx that failed with err may be retried. Calls aren't retried once ctx
+// is done, if they failed because a context was cancelled or timed out, if a circuit breaker rejected them, or if err
+// was marked with Permanent. Calls that a Timeout cancelled are retried while ctx isn't done.
+func Retryable(ctx context.Context, err error) bool {
+	var permanent *permanentError
+	switch {
+	case err == nil || ctx.Err() != nil:
+		return false
+	case errors.Is(err, ErrTimeout):
+		return true
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, ErrBreakerOpen):
+		return false
+	case errors.As(err, &permanent):
+		return false
+	}
+	return true
+}
+
+// RetryBudget caps the retries of a client at a ratio of its calls, so that retries can't multiply the load on a
+// service that is failing. Every call deposits ratio tokens and every retry withdraws one.
+type RetryBudget struct {
+	lock    sync.Mutex
+	ratio   float64
+	balance float64
+}
+
+func NewRetryBudget(ratio float64) *RetryBudget {
+	return &RetryBudget{ratio: ratio, balance: RetryBudgetBurst}
+}
+
+// Deposit records a call
+func (b *RetryBudget) Deposit() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.balance += b.ratio
+	if b.balance > RetryBudgetBurst {
+		b.balance = RetryBudgetBurst
+	}
+}
+
+// Withdraw reports whether a retry is allowed and records it if it is
+func (b *RetryBudget) Withdraw() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.balance < 1 {
+		return false
+	}
+	b.balance -= 1
+	return true
+}
+
+// Retrier retries the failed calls of a client according to the RetryPolicy of their method
+type Retrier struct {
+	Policy    RetryPolicy
+	Methods   map[string]RetryPolicy                    // Policies of the methods that don't use Policy
+	Budget    *RetryBudget                              // Shared by all methods, no limit if nil
+	Retryable func(ctx context.Context, err error) bool // Classifies the errors of the calls, Retryable by default
+
+	lock sync.Mutex
+	rnd  *rand.Rand
+}
+
+func NewRetrier(policy RetryPolicy, methods map[string]RetryPolicy, budget *RetryBudget) *Retrier {
+	return &Retrier{Policy: policy, Methods: methods, Budget: budget, Retryable: Retryable, rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// ParseRetrier creates a Retrier from the options of the Retry modifier in the wiring file: max_retries (attempts of a
+// call), backoff, base_delay, max_delay, budget (the ratio of retries to calls, 0 disables the budget) and methods,
+// a dictionary of the options of single methods.
+func ParseRetrier(opts map[string]string) (*Retrier, error) {
+	o := make(options)
+	for key, value := range opts {
+		o[key] = value
+	}
+	policy, err := parseRetryPolicy(o)
+	if err != nil {
+		return nil, err
+	}
+	ratio, err := o.float("budget", 0.2)
+	if err != nil {
+		return nil, err
+	}
+	if ratio < 0 {
+		return nil, fmt.Errorf("budget: %v is negative", ratio)
+	}
+	var budget *RetryBudget
+	if ratio > 0 {
+		budget = NewRetryBudget(ratio)
+	}
+	methods, err := o.methods("methods")
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[string]RetryPolicy)
+	for _, method := range sortedKeys(methods) {
+		methodOpts := methods[method]
+		if err := methodOpts.check("max_retries", "backoff", "base_delay", "max_delay"); err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+		policies[method], err = parseRetryPolicy(o.overlay(methodOpts))
+		if err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+	}
+	return NewRetrier(policy, policies, budget), nil
+}
+
+func parseRetryPolicy(o options) (RetryPolicy, error) {
+	var policy RetryPolicy
+	var err error
+	if policy.MaxAttempts, err = o.int("max_retries", 1); err != nil {
+		return policy, err
+	}
+	if policy.MaxAttempts < 1 {
+		return policy, fmt.Errorf("max_retries: %d is less than 1", policy.MaxAttempts)
+	}
+	if policy.Backoff, err = o.string("backoff", BackoffExponential); err != nil {
+		return policy, err
+	}
+	switch policy.Backoff {
+	case BackoffNone, BackoffConstant, BackoffExponential, BackoffJitter:
+	default:
+		return policy, fmt.Errorf("backoff: unknown policy %s, expected one of none, constant, exponential, jitter", policy.Backoff)
+	}
+	if policy.BaseDelay, err = o.duration("base_delay", 10*time.Millisecond); err != nil {
+		return policy, err
+	}
+	if policy.MaxDelay, err = o.duration("max_delay", time.Second); err != nil {
+		return policy, err
+	}
+	if policy.MaxDelay < policy.BaseDelay {
+		return policy, fmt.Errorf("max_delay: %v is less than the base_delay %v", policy.MaxDelay, policy.BaseDelay)
+	}
+	return policy, nil
+}
+
+// Do calls call until it succeeds, fails with an error that isn't retryable, or the attempts of the policy of method
+// are used up, and returns the error of the last attempt. A retry is skipped if the retry budget is exhausted or if
+// the delay before it would exceed the deadline of ctx.
+func (r *Retrier) Do(ctx context.Context, method string, call func() error) error {
+	policy, ok := r.Methods[method]
+	if !ok {
+		policy = r.Policy
+	}
+	if r.Budget != nil {
+		r.Budget.Deposit()
+	}
+	var delay time.Duration
+	for attempt := int64(1); ; attempt++ {
+		err := call()
+		// Calls of a cancelled context are never retried, whatever the classifier says
+		if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !r.Retryable(ctx, err) {
+			return err
+		}
+		r.lock.Lock()
+		delay = policy.delay(attempt, delay, r.rnd.Int63n)
+		r.lock.Unlock()
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		if r.Budget != nil && !r.Budget.Withdraw() {
+			return err
+		}
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
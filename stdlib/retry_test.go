@@ -0,0 +1,141 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	maxRnd := func(n int64) int64 { return n - 1 }
+	tests := []struct {
+		backoff  string
+		expected []time.Duration
+	}{
+		{BackoffNone, []time.Duration{0, 0, 0, 0}},
+		{BackoffConstant, []time.Duration{10, 10, 10, 10}},
+		{BackoffExponential, []time.Duration{10, 20, 40, 50}},
+		{BackoffJitter, []time.Duration{10, 29, 50, 50}},
+	}
+	for _, test := range tests {
+		policy := RetryPolicy{Backoff: test.backoff, BaseDelay: 10, MaxDelay: 50}
+		var delays []time.Duration
+		var delay time.Duration
+		for attempt := int64(1); attempt <= 4; attempt++ {
+			delay = policy.delay(attempt, delay, maxRnd)
+			delays = append(delays, delay)
+		}
+		if !reflect.DeepEqual(delays, test.expected) {
+			t.Errorf("%s: expected delays %v, got %v", test.backoff, test.expected, delays)
+		}
+	}
+}
+
+func TestRetrierDo(t *testing.T) {
+	failure := errors.New("unavailable")
+	retrier := NewRetrier(RetryPolicy{MaxAttempts: 3, Backoff: BackoffNone}, map[string]RetryPolicy{"Once": {MaxAttempts: 1}}, nil)
+	tests := []struct {
+		name     string
+		method   string
+		ctx      func() context.Context
+		err      error
+		attempts int
+	}{
+		{"retried", "Leaf", context.Background, failure, 3},
+		{"method policy", "Once", context.Background, failure, 1},
+		{"permanent", "Leaf", context.Background, Permanent(failure), 1},
+		{"timed out call", "Leaf", context.Background, context.DeadlineExceeded, 1},
+		{"breaker open", "Leaf", context.Background, ErrBreakerOpen, 1},
+		{"cancelled", "Leaf", func() context.Context {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx
+		}, failure, 1},
+	}
+	for _, test := range tests {
+		attempts := 0
+		err := retrier.Do(test.ctx(), test.method, func() error {
+			attempts++
+			return test.err
+		})
+		if !errors.Is(err, failure) && !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+		if attempts != test.attempts {
+			t.Errorf("%s: expected %d attempts, got %d", test.name, test.attempts, attempts)
+		}
+	}
+}
+
+func TestRetrierRespectsDeadline(t *testing.T) {
+	retrier := NewRetrier(RetryPolicy{MaxAttempts: 3, Backoff: BackoffConstant, BaseDelay: time.Hour, MaxDelay: time.Hour}, nil, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	attempts := 0
+	retrier.Do(ctx, "Leaf", func() error {
+		attempts++
+		return errors.New("unavailable")
+	})
+	if attempts != 1 {
+		t.Errorf("Expected no retry past the deadline, got %d attempts", attempts)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	retrier := NewRetrier(RetryPolicy{MaxAttempts: 100, Backoff: BackoffNone}, nil, NewRetryBudget(0.5))
+	attempts := 0
+	retrier.Do(context.Background(), "Leaf", func() error {
+		attempts++
+		return errors.New("unavailable")
+	})
+	// The burst and the deposit of the call
+	if expected := 1 + int(RetryBudgetBurst); attempts != expected {
+		t.Errorf("Expected %d attempts, got %d", expected, attempts)
+	}
+	for i := 0; i < 4; i++ {
+		retrier.Do(context.Background(), "Leaf", func() error { return nil })
+	}
+	attempts = 0
+	retrier.Do(context.Background(), "Leaf", func() error {
+		attempts++
+		return errors.New("unavailable")
+	})
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts after 5 calls, got %d", attempts)
+	}
+}
+
+func TestParseRetrier(t *testing.T) {
+	retrier, err := ParseRetrier(map[string]string{"max_retries": "4", "backoff": "constant", "base_delay": "5ms", "methods": "{Leaf: {max_retries: 2, backoff: jitter}, Object: {}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]RetryPolicy{
+		"Leaf":   {MaxAttempts: 2, Backoff: BackoffJitter, BaseDelay: 5 * time.Millisecond, MaxDelay: time.Second},
+		"Object": {MaxAttempts: 4, Backoff: BackoffConstant, BaseDelay: 5 * time.Millisecond, MaxDelay: time.Second},
+	}
+	if !reflect.DeepEqual(retrier.Methods, expected) {
+		t.Errorf("Expected method policies %v, got %v", expected, retrier.Methods)
+	}
+	if retrier.Budget == nil {
+		t.Errorf("Expected the default budget")
+	}
+
+	errs := map[string]map[string]string{
+		"backoff: unknown policy linear":                    {"backoff": "linear"},
+		"max_retries: 0 is less than 1":                     {"max_retries": "0"},
+		"max_delay: 1ms is less than the base_delay":        {"base_delay": "10ms", "max_delay": "1ms"},
+		"base_delay: 10 is not a duration":                  {"base_delay": "10"},
+		"methods: Leaf: unknown option budget":              {"methods": "{Leaf: {budget: 1}}"},
+		"methods: the options of Leaf must be a dictionary": {"methods": "{Leaf: 3}"},
+		"methods: expected '}'":                             {"methods": "{Leaf: {max_retries: 3}"},
+	}
+	for expected, opts := range errs {
+		if _, err := ParseRetrier(opts); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%v: expected error %q, got %v", opts, expected, err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is matched by the errors of the calls that a Timeout cancelled
+var ErrTimeout = errors.New("call timed out")
+
+// TimeoutError is the error of a call that a Timeout cancelled because it outlasted the timeout of its method. It
+// matches ErrTimeout, and the error returned by the call through Unwrap.
+type TimeoutError struct {
+	Method  string
+	Timeout time.Duration
+	Err     error // Error returned by the call
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v: %v", e.Method, e.Timeout, e.Err)
+}
+
+func (e *TimeoutError) Is(target error) bool { return target == ErrTimeout }
+func (e *TimeoutError) Unwrap() error        { return e.Err }
+
+// DeadlineHeader is the header that carries the time left until the deadline of a call to the server, for the
+// frameworks that don't propagate deadlines themselves
+const DeadlineHeader = "Blueprint-Timeout"
+
+// EncodeDeadline returns the time left until the deadline of ctx, to be sent with a call as the DeadlineHeader, and
+// false if ctx has no deadline
+func EncodeDeadline(ctx context.Context) (string, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return "", false
+	}
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining.String(), true
+}
+
+// WithEncodedDeadline returns a copy of ctx with the deadline that EncodeDeadline encoded as value. The copy has no
+// other deadline than the one of ctx if value is empty or invalid.
+func WithEncodedDeadline(ctx context.Context, value string) (context.Context, context.CancelFunc) {
+	remaining, err := time.ParseDuration(value)
+	if value == "" || err != nil {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, remaining)
+}
+
+// Timeout cancels the calls of a client that outlast the timeout of their method
+type Timeout struct {
+	Default time.Duration
+	Methods map[string]time.Duration // Timeouts of the methods that don't use Default
+}
+
+func NewTimeout(def time.Duration, methods map[string]time.Duration) *Timeout {
+	return &Timeout{Default: def, Methods: methods}
+}
+
+// ParseTimeout creates a Timeout from the options of the Timeout modifier in the wiring file: timeout and methods, a
+// dictionary of the options of single methods.
+func ParseTimeout(opts map[string]string) (*Timeout, error) {
+	o := make(options)
+	for key, value := range opts {
+		o[key] = value
+	}
+	def, err := o.duration("timeout", 0)
+	if err != nil {
+		return nil, err
+	}
+	if def == 0 {
+		return nil, fmt.Errorf("timeout: a timeout greater than 0 is required, e.g. 500ms")
+	}
+	methods, err := o.methods("methods")
+	if err != nil {
+		return nil, err
+	}
+	timeouts := make(map[string]time.Duration)
+	for _, method := range sortedKeys(methods) {
+		methodOpts := methods[method]
+		if err := methodOpts.check("timeout"); err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+		if timeouts[method], err = methodOpts.duration("timeout", def); err != nil {
+			return nil, fmt.Errorf("methods: %s: %v", method, err)
+		}
+	}
+	return NewTimeout(def, timeouts), nil
+}
+
+// Do calls call with a copy of ctx that is cancelled once the timeout of method expires, and returns a TimeoutError
+// if the call failed because it did. ctx keeps its deadline if it expires earlier. call has to return once the
+// context it is called with is done.
+func (t *Timeout) Do(ctx context.Context, method string, call func(ctx context.Context) error) error {
+	timeout, ok := t.Methods[method]
+	if !ok {
+		timeout = t.Default
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := call(callCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Method: method, Timeout: timeout, Err: err}
+	}
+	return err
+}
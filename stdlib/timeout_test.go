@@ -0,0 +1,97 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Blocks until the context of the call is done
+func blockingCall(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTimeoutDo(t *testing.T) {
+	timeout := NewTimeout(time.Hour, map[string]time.Duration{"Leaf": time.Millisecond})
+	err := timeout.Do(context.Background(), "Leaf", blockingCall)
+	var timeoutErr *TimeoutError
+	if !errors.Is(err, ErrTimeout) || !errors.As(err, &timeoutErr) || timeoutErr.Timeout != time.Millisecond {
+		t.Fatalf("Expected Leaf to time out after 1ms, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the error of the call to be wrapped, got %v", err)
+	}
+	if err := timeout.Do(context.Background(), "Object", func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("Expected Object to succeed, got %v", err)
+	}
+}
+
+func TestTimeoutDoKeepsEarlierDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err := NewTimeout(time.Hour, nil).Do(ctx, "Leaf", blockingCall)
+	if errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline of the caller to expire, got %v", err)
+	}
+}
+
+func TestDeadlinePropagation(t *testing.T) {
+	if _, ok := EncodeDeadline(context.Background()); ok {
+		t.Errorf("Expected no deadline to be encoded for a context without one")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	value, ok := EncodeDeadline(ctx)
+	if !ok {
+		t.Fatalf("Expected the deadline to be encoded")
+	}
+	server, cancel := WithEncodedDeadline(context.Background(), value)
+	defer cancel()
+	deadline, ok := server.Deadline()
+	if remaining := time.Until(deadline); !ok || remaining > time.Minute || remaining < 50*time.Second {
+		t.Errorf("Expected the server to have about a minute left, got %v", remaining)
+	}
+	for _, value := range []string{"", "soon"} {
+		server, cancel := WithEncodedDeadline(context.Background(), value)
+		defer cancel()
+		if _, ok := server.Deadline(); ok {
+			t.Errorf("%q: expected no deadline", value)
+		}
+	}
+}
+
+func TestTimeoutsOfResilience(t *testing.T) {
+	err := &TimeoutError{Method: "Leaf", Timeout: time.Millisecond, Err: context.DeadlineExceeded}
+	if !Retryable(context.Background(), err) {
+		t.Errorf("Expected a timed out call to be retried")
+	}
+	cb, _, _ := testBreaker(BreakerPolicy{Trip: TripSlowCallRate, Threshold: 1, MinSamples: 1, SlowCall: time.Hour, OpenTimeout: time.Second, HalfOpenProbes: 1}, nil, false)
+	cb.Do(context.Background(), "Leaf", func() error { return err })
+	if state := cb.State("Leaf"); state != BreakerOpen {
+		t.Errorf("Expected a timed out call to count as slow, got %s", state)
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	timeout, err := ParseTimeout(map[string]string{"timeout": "500ms", "methods": "{Leaf: {timeout: 100ms}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeout.Default != 500*time.Millisecond || timeout.Methods["Leaf"] != 100*time.Millisecond {
+		t.Errorf("Expected the timeouts 500ms and 100ms for Leaf, got %v and %v", timeout.Default, timeout.Methods["Leaf"])
+	}
+
+	errs := map[string]map[string]string{
+		"timeout: a timeout greater than 0 is required": {},
+		"timeout: soon is not a duration":               {"timeout": "soon"},
+		"methods: Leaf: unknown option max_retries":     {"timeout": "1s", "methods": "{Leaf: {max_retries: 1}}"},
+	}
+	for expected, opts := range errs {
+		if _, err := ParseTimeout(opts); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%v: expected error %q, got %v", opts, expected, err)
+		}
+	}
+}
@@ -1,7 +0,0 @@
-
-from .eval import ModifierPartialEvaluator
-from .registry import ModifierRegistry, COMPONENTS
-from .parser import WiringParser
-from .modifier_parser import ModifierParser
-
-__all__ = ['ModifierPartialEvaluator', 'ModifierRegistry', 'WiringParser', 'ModifierParser', 'COMPONENTS']
\ No newline at end of file